@@ -0,0 +1,328 @@
+package handlers
+
+import (
+	"fmt"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/karlbehrensg/go-fiber-template/cmd/api/internal/http/middlewares"
+	"github.com/karlbehrensg/go-fiber-template/internal/http/requests"
+	"github.com/karlbehrensg/go-fiber-template/internal/http/responses"
+	"github.com/karlbehrensg/go-fiber-template/internal/service"
+	"github.com/karlbehrensg/go-fiber-template/pkg/errs"
+	"github.com/karlbehrensg/go-fiber-template/pkg/logger"
+	"github.com/karlbehrensg/go-fiber-template/pkg/utils"
+)
+
+type AdminUserHandler struct {
+	Service  service.UserService
+	TokenSrv service.TokenService
+}
+
+// GetUsers godoc
+// @Summary list users.
+// @Description endpoint for list a page of users, searching by email or name.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param search query string false "email or name contains"
+// @Param role query string false "role" Enums(reader, librarian, admin)
+// @Param status query string false "status" Enums(active, disabled, deleted)
+// @Param page query int false "page, starting at 1"
+// @Param limit query int false "users per page, max 100"
+// @Success 200 {object} responses.UserListResponse
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 403 {object} responses.ErrorResponse
+// @Failure 500 {object} responses.ErrorResponse
+// @Security Bearer
+// @Router /admin/users [get]
+// GetUsers controller to list users
+func (h AdminUserHandler) GetUsers(c *fiber.Ctx) error {
+	// Convert the query string to the structure
+	data := requests.UserListRequest{}
+	if err := c.QueryParser(&data); err != nil {
+		logger.Error(fmt.Sprintf("Error decode: %s", err.Error()))
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Invalid data",
+		})
+	}
+
+	// validates the structure
+	if err := utils.GetValidator().Struct(data); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": err.Error(),
+		})
+	}
+
+	var response *responses.UserListResponse
+	var appErr *errs.AppError
+	// calls use case to list users
+	if response, appErr = h.Service.FindUsers(data); appErr != nil {
+		return c.Status(appErr.Code).JSON(appErr.AsMessage())
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+// GetUser godoc
+// @Summary get user.
+// @Description endpoint for inspect a user, soft deleted users included.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param id path integer true "User ID"
+// @Success 200 {object} responses.AdminUserResponse
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 403 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Failure 500 {object} responses.ErrorResponse
+// @Security Bearer
+// @Router /admin/users/{id} [get]
+// GetUser controller to inspect a user
+func (h AdminUserHandler) GetUser(c *fiber.Ctx) error {
+	var id int
+	var err error
+	// get ID parameter from url
+	if id, err = c.ParamsInt("id"); err != nil {
+		logger.Error(err.Error())
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Invalid user id",
+		})
+	}
+
+	var response *responses.AdminUserResponse
+	var appErr *errs.AppError
+	// calls use case to find user
+	if response, appErr = h.Service.FindUser(uint(id)); appErr != nil {
+		return c.Status(appErr.Code).JSON(appErr.AsMessage())
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+// UpdateUserRole godoc
+// @Summary assign role.
+// @Description endpoint for assign the role of a user, it applies to the tokens already issued.
+// @Tags Admin
+// @Accept x-www-form-urlencoded
+// @Produce json
+// @Param id path integer true "User ID"
+// @Param role formData string true "role" Enums(reader, librarian, admin)
+// @Success 200 {object} responses.AdminUserResponse
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 403 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Failure 500 {object} responses.ErrorResponse
+// @Security Bearer
+// @Router /admin/users/{id}/role [put]
+// UpdateUserRole controller to assign the role of a user
+func (h AdminUserHandler) UpdateUserRole(c *fiber.Ctx) error {
+	var id int
+	var err error
+	// get ID parameter from url
+	if id, err = c.ParamsInt("id"); err != nil {
+		logger.Error(err.Error())
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Invalid user id",
+		})
+	}
+
+	// Convert the request data to the structure
+	data := requests.UserRoleRequest{}
+	if err := c.BodyParser(&data); err != nil {
+		logger.Error(fmt.Sprintf("Error decode: %s", err.Error()))
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Invalid data",
+		})
+	}
+
+	// validates the structure
+	if err := utils.GetValidator().Struct(data); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": err.Error(),
+		})
+	}
+
+	var response *responses.AdminUserResponse
+	var appErr *errs.AppError
+	// calls use case to assign the role
+	if response, appErr = h.Service.UpdateUserRole(middlewares.CurrentActor(c), uint(id), data.Role); appErr != nil {
+		return c.Status(appErr.Code).JSON(appErr.AsMessage())
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+// DisableUser godoc
+// @Summary disable user.
+// @Description endpoint for disable a user, the user cannot login and its tokens are rejected.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param id path integer true "User ID"
+// @Success 200 {object} responses.AdminUserResponse
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 403 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Failure 500 {object} responses.ErrorResponse
+// @Security Bearer
+// @Router /admin/users/{id}/disable [post]
+// DisableUser controller to disable a user
+func (h AdminUserHandler) DisableUser(c *fiber.Ctx) error {
+	return h.setUserDisabled(c, true)
+}
+
+// EnableUser godoc
+// @Summary enable user.
+// @Description endpoint for enable a disabled user.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param id path integer true "User ID"
+// @Success 200 {object} responses.AdminUserResponse
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 403 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Failure 500 {object} responses.ErrorResponse
+// @Security Bearer
+// @Router /admin/users/{id}/enable [post]
+// EnableUser controller to enable a user
+func (h AdminUserHandler) EnableUser(c *fiber.Ctx) error {
+	return h.setUserDisabled(c, false)
+}
+
+func (h AdminUserHandler) setUserDisabled(c *fiber.Ctx, disabled bool) error {
+	var id int
+	var err error
+	// get ID parameter from url
+	if id, err = c.ParamsInt("id"); err != nil {
+		logger.Error(err.Error())
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Invalid user id",
+		})
+	}
+
+	var response *responses.AdminUserResponse
+	var appErr *errs.AppError
+	// calls use case to disable or enable the user
+	if response, appErr = h.Service.SetUserDisabled(middlewares.CurrentActor(c), uint(id), disabled); appErr != nil {
+		return c.Status(appErr.Code).JSON(appErr.AsMessage())
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+// DeleteUser godoc
+// @Summary delete user.
+// @Description endpoint for soft delete a user, it can be restored later.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param id path integer true "User ID"
+// @Success 204
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 403 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Failure 500 {object} responses.ErrorResponse
+// @Security Bearer
+// @Router /admin/users/{id} [delete]
+// DeleteUser controller to soft delete a user
+func (h AdminUserHandler) DeleteUser(c *fiber.Ctx) error {
+	var id int
+	var err error
+	// get ID parameter from url
+	if id, err = c.ParamsInt("id"); err != nil {
+		logger.Error(err.Error())
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Invalid user id",
+		})
+	}
+
+	// calls use case to delete the user
+	if appErr := h.Service.DeleteUser(middlewares.CurrentActor(c), uint(id)); appErr != nil {
+		return c.Status(appErr.Code).JSON(appErr.AsMessage())
+	}
+
+	return c.Status(fiber.StatusNoContent).JSON(fiber.Map{
+		"message": "User deleted",
+	})
+}
+
+// RestoreUser godoc
+// @Summary restore user.
+// @Description endpoint for restore a soft deleted user.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param id path integer true "User ID"
+// @Success 200 {object} responses.AdminUserResponse
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 403 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Failure 500 {object} responses.ErrorResponse
+// @Security Bearer
+// @Router /admin/users/{id}/restore [post]
+// RestoreUser controller to restore a soft deleted user
+func (h AdminUserHandler) RestoreUser(c *fiber.Ctx) error {
+	var id int
+	var err error
+	// get ID parameter from url
+	if id, err = c.ParamsInt("id"); err != nil {
+		logger.Error(err.Error())
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Invalid user id",
+		})
+	}
+
+	var response *responses.AdminUserResponse
+	var appErr *errs.AppError
+	// calls use case to restore the user
+	if response, appErr = h.Service.RestoreUser(middlewares.CurrentActor(c), uint(id)); appErr != nil {
+		return c.Status(appErr.Code).JSON(appErr.AsMessage())
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+// ImpersonateUser godoc
+// @Summary impersonate user.
+// @Description endpoint for mint a short-lived access token to act as a non admin user, every request made with it is audited.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param id path integer true "User ID"
+// @Success 200 {object} responses.ImpersonationResponse
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 403 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Failure 500 {object} responses.ErrorResponse
+// @Security Bearer
+// @Router /admin/users/{id}/impersonate [post]
+// ImpersonateUser controller to impersonate a user
+func (h AdminUserHandler) ImpersonateUser(c *fiber.Ctx) error {
+	var id int
+	var err error
+	// get ID parameter from url
+	if id, err = c.ParamsInt("id"); err != nil {
+		logger.Error(err.Error())
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Invalid user id",
+		})
+	}
+
+	var response *responses.ImpersonationResponse
+	var appErr *errs.AppError
+	// calls use case to mint the impersonation token
+	if response, appErr = h.TokenSrv.Impersonate(middlewares.CurrentActor(c), uint(id)); appErr != nil {
+		return c.Status(appErr.Code).JSON(appErr.AsMessage())
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"fmt"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/karlbehrensg/go-fiber-template/cmd/api/internal/http/middlewares"
+
+	"github.com/karlbehrensg/go-fiber-template/internal/http/requests"
+	"github.com/karlbehrensg/go-fiber-template/internal/http/responses"
+	"github.com/karlbehrensg/go-fiber-template/internal/service"
+	"github.com/karlbehrensg/go-fiber-template/pkg/errs"
+	"github.com/karlbehrensg/go-fiber-template/pkg/logger"
+	"github.com/karlbehrensg/go-fiber-template/pkg/utils"
+)
+
+type APIKeyHandler struct {
+	Service service.APIKeyService
+}
+
+// CreateAPIKey godoc
+// @Summary create API key.
+// @Description endpoint for create a personal API key, the key is only shown in this response.
+// @Tags API Keys
+// @Accept x-www-form-urlencoded
+// @Produce json
+// @Param name formData string true "name"
+// @Param scopes formData []string false "scopes, empty grants every scope" collectionFormat(csv) Enums(catalog:read, catalog:write)
+// @Param expires_in_days formData int false "days until the key expires"
+// @Success 201 {object} responses.APIKeyCreatedResponse
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 500 {object} responses.ErrorResponse
+// @Security Bearer
+// @Router /auth/api-keys [post]
+// CreateAPIKey controller to create API key
+func (h APIKeyHandler) CreateAPIKey(c *fiber.Ctx) error {
+	// Convert the request data to the structure
+	data := requests.APIKeyRequest{}
+	if err := c.BodyParser(&data); err != nil {
+		logger.Error(fmt.Sprintf("Error decode: %s", err.Error()))
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Invalid data",
+		})
+	}
+
+	// validates the structure
+	if err := utils.GetValidator().Struct(data); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": err.Error(),
+		})
+	}
+
+	var response *responses.APIKeyCreatedResponse
+	var appErr *errs.AppError
+	// calls use case to create API key
+	if response, appErr = h.Service.CreateAPIKey(middlewares.CurrentUser(c).UserID, data); appErr != nil {
+		return c.Status(appErr.Code).JSON(appErr.AsMessage())
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(response)
+}
+
+// GetAPIKeys godoc
+// @Summary list API keys.
+// @Description endpoint for list the API keys of the authenticated user, only the prefix of each key is shown.
+// @Tags API Keys
+// @Accept json
+// @Produce json
+// @Success 200 {array} responses.APIKeyResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 500 {object} responses.ErrorResponse
+// @Security Bearer
+// @Router /auth/api-keys [get]
+// GetAPIKeys controller to list API keys
+func (h APIKeyHandler) GetAPIKeys(c *fiber.Ctx) error {
+	var response []responses.APIKeyResponse
+	var appErr *errs.AppError
+	// calls use case to list API keys
+	if response, appErr = h.Service.FindAPIKeys(middlewares.CurrentUser(c).UserID); appErr != nil {
+		return c.Status(appErr.Code).JSON(appErr.AsMessage())
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+// RevokeAPIKey godoc
+// @Summary revoke API key.
+// @Description endpoint for revoke an API key of the authenticated user.
+// @Tags API Keys
+// @Accept json
+// @Produce json
+// @Param id path int true "API key ID"
+// @Success 200 {object} responses.UserResponse
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Failure 500 {object} responses.ErrorResponse
+// @Security Bearer
+// @Router /auth/api-keys/{id} [delete]
+// RevokeAPIKey controller to revoke API key
+func (h APIKeyHandler) RevokeAPIKey(c *fiber.Ctx) error {
+	var id int
+	var err error
+	// get ID parameter from url
+	if id, err = c.ParamsInt("id"); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Invalid API key id",
+		})
+	}
+
+	// calls use case to revoke API key
+	if appErr := h.Service.RevokeAPIKey(middlewares.CurrentUser(c).UserID, uint(id)); appErr != nil {
+		return c.Status(appErr.Code).JSON(appErr.AsMessage())
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "API key revoked",
+	})
+}
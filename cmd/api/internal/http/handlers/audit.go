@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"fmt"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/karlbehrensg/go-fiber-template/internal/http/requests"
+	"github.com/karlbehrensg/go-fiber-template/internal/http/responses"
+	"github.com/karlbehrensg/go-fiber-template/internal/service"
+	"github.com/karlbehrensg/go-fiber-template/pkg/errs"
+	"github.com/karlbehrensg/go-fiber-template/pkg/logger"
+	"github.com/karlbehrensg/go-fiber-template/pkg/utils"
+)
+
+type AuditHandler struct {
+	Service service.AuditService
+}
+
+// GetAuditEntries godoc
+// @Summary list audit entries.
+// @Description endpoint for list a page of the audit log, newest first.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param action query string false "action" example(author.update)
+// @Param actor_id query int false "user ID of the actor"
+// @Param impersonator_id query int false "user ID of the admin impersonating the actor"
+// @Param target_type query string false "target type" Enums(author, book, user, session)
+// @Param target_id query string false "target ID"
+// @Param request_id query string false "request ID"
+// @Param from query string false "RFC 3339 date, inclusive"
+// @Param to query string false "RFC 3339 date, exclusive"
+// @Param page query int false "page, starting at 1"
+// @Param limit query int false "entries per page, max 100"
+// @Success 200 {object} responses.AuditListResponse
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 403 {object} responses.ErrorResponse
+// @Failure 500 {object} responses.ErrorResponse
+// @Security Bearer
+// @Router /admin/audit [get]
+// GetAuditEntries controller to list audit entries
+func (h AuditHandler) GetAuditEntries(c *fiber.Ctx) error {
+	// Convert the query string to the structure
+	data := requests.AuditListRequest{}
+	if err := c.QueryParser(&data); err != nil {
+		logger.Error(fmt.Sprintf("Error decode: %s", err.Error()))
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Invalid data",
+		})
+	}
+
+	// validates the structure
+	if err := utils.GetValidator().Struct(data); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": err.Error(),
+		})
+	}
+
+	var response *responses.AuditListResponse
+	var appErr *errs.AppError
+	// calls use case to list audit entries
+	if response, appErr = h.Service.FindAuditEntries(data); appErr != nil {
+		return c.Status(appErr.Code).JSON(appErr.AsMessage())
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
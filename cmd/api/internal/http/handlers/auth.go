@@ -5,6 +5,8 @@ import (
 
 	"github.com/gofiber/fiber/v2"
 
+	"github.com/karlbehrensg/go-fiber-template/cmd/api/internal/http/middlewares"
+
 	"github.com/karlbehrensg/go-fiber-template/internal/http/requests"
 	"github.com/karlbehrensg/go-fiber-template/internal/http/responses"
 	"github.com/karlbehrensg/go-fiber-template/internal/service"
@@ -14,8 +16,11 @@ import (
 )
 
 type AuthHandler struct {
-	UserSrv service.UserService
-	AuthSrv service.AuthService
+	UserSrv         service.UserService
+	AuthSrv         service.AuthService
+	TokenSrv        service.TokenService
+	PasswordSrv     service.PasswordService
+	VerificationSrv service.VerificationService
 }
 
 // SignUp godoc
@@ -52,7 +57,7 @@ func (h AuthHandler) SignUp(c *fiber.Ctx) error {
 	}
 
 	// calls use case to create user
-	if err := h.UserSrv.CreateUser(*data); err != nil {
+	if err := h.UserSrv.CreateUser(middlewares.CurrentActor(c), *data); err != nil {
 		return c.Status(err.Code).JSON(err.AsMessage())
 	}
 
@@ -72,6 +77,9 @@ func (h AuthHandler) SignUp(c *fiber.Ctx) error {
 // @Success 201 {object} responses.LoginResponse
 // @Failure 400 {object} responses.ErrorResponse
 // @Failure 401 {object} responses.ErrorResponse
+// @Failure 403 {object} responses.ErrorResponse
+// @Failure 423 {object} responses.ErrorResponse
+// @Failure 429 {object} responses.ErrorResponse
 // @Failure 500 {object} responses.ErrorResponse
 // @Router /auth/login [post]
 // Login controller to generate token
@@ -91,6 +99,9 @@ func (h AuthHandler) Login(c *fiber.Ctx) error {
 			"message": err.Error(),
 		})
 	}
+	data.IP = c.IP()
+	data.UserAgent = c.Get(fiber.HeaderUserAgent)
+	data.RequestID = middlewares.RequestID(c)
 
 	var response *responses.LoginResponse
 	var appErr *errs.AppError
@@ -101,3 +112,340 @@ func (h AuthHandler) Login(c *fiber.Ctx) error {
 
 	return c.Status(fiber.StatusCreated).JSON(response)
 }
+
+// Refresh godoc
+// @Summary rotate refresh token.
+// @Description endpoint for exchange a refresh token for a new access token and refresh token.
+// @Tags Auth
+// @Accept x-www-form-urlencoded
+// @Produce json
+// @Param refresh_token formData string true "refresh token"
+// @Success 201 {object} responses.LoginResponse
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 500 {object} responses.ErrorResponse
+// @Router /auth/refresh [post]
+// Refresh controller to rotate refresh token
+func (h AuthHandler) Refresh(c *fiber.Ctx) error {
+	// Convert the request data to the structure
+	data := requests.RefreshTokenRequest{}
+	if err := c.BodyParser(&data); err != nil {
+		logger.Error(fmt.Sprintf("Error decode: %s", err.Error()))
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Invalid data",
+		})
+	}
+
+	// validates the structure
+	if err := utils.GetValidator().Struct(data); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": err.Error(),
+		})
+	}
+	data.IP = c.IP()
+
+	var response *responses.LoginResponse
+	var appErr *errs.AppError
+	// calls use case to rotate refresh token
+	if response, appErr = h.TokenSrv.RefreshTokens(data); appErr != nil {
+		return c.Status(appErr.Code).JSON(appErr.AsMessage())
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(response)
+}
+
+// Logout godoc
+// @Summary revoke tokens.
+// @Description endpoint for revoke the access token and, when given, the refresh token.
+// @Tags Auth
+// @Accept x-www-form-urlencoded
+// @Produce json
+// @Param refresh_token formData string false "refresh token"
+// @Success 200 {object} responses.UserResponse
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 500 {object} responses.ErrorResponse
+// @Security Bearer
+// @Router /auth/logout [post]
+// Logout controller to revoke tokens
+func (h AuthHandler) Logout(c *fiber.Ctx) error {
+	// Convert the request data to the structure
+	data := requests.LogoutRequest{}
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&data); err != nil {
+			logger.Error(fmt.Sprintf("Error decode: %s", err.Error()))
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"message": "Invalid data",
+			})
+		}
+	}
+
+	// calls use case to revoke tokens
+	if appErr := h.TokenSrv.RevokeTokens(middlewares.CurrentUser(c), data); appErr != nil {
+		return c.Status(appErr.Code).JSON(appErr.AsMessage())
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Logged out",
+	})
+}
+
+// GetMe godoc
+// @Summary get profile.
+// @Description endpoint for get the profile of the authenticated user.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Success 200 {object} responses.ProfileResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Failure 500 {object} responses.ErrorResponse
+// @Security Bearer
+// @Router /auth/me [get]
+// GetMe controller to get the profile of the authenticated user
+func (h AuthHandler) GetMe(c *fiber.Ctx) error {
+	var response *responses.ProfileResponse
+	var appErr *errs.AppError
+	// calls use case to find the authenticated user
+	if response, appErr = h.UserSrv.FindProfile(middlewares.CurrentUser(c).UserID); appErr != nil {
+		return c.Status(appErr.Code).JSON(appErr.AsMessage())
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+// UpdateMe godoc
+// @Summary update profile.
+// @Description endpoint for update the profile of the authenticated user.
+// @Tags Auth
+// @Accept x-www-form-urlencoded
+// @Produce json
+// @Param full_name formData string false "Full name"
+// @Param email formData string false "email"
+// @Success 200 {object} responses.ProfileResponse
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Failure 500 {object} responses.ErrorResponse
+// @Security Bearer
+// @Router /auth/me [put]
+// UpdateMe controller to update the profile of the authenticated user
+func (h AuthHandler) UpdateMe(c *fiber.Ctx) error {
+	// Convert the request data to the structure
+	data := &requests.ProfileRequest{}
+	if err := c.BodyParser(data); err != nil {
+		logger.Error(fmt.Sprintf("Error decode: %s", err.Error()))
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Invalid data",
+		})
+	}
+
+	// validates the structure
+	if err := utils.GetValidator().Struct(data); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": err.Error(),
+		})
+	}
+
+	var response *responses.ProfileResponse
+	var appErr *errs.AppError
+	// calls use case to update the authenticated user
+	if response, appErr = h.UserSrv.UpdateProfile(middlewares.CurrentUser(c).UserID, data); appErr != nil {
+		return c.Status(appErr.Code).JSON(appErr.AsMessage())
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+// ForgotPassword godoc
+// @Summary request password reset.
+// @Description endpoint for send a password reset link, the response is the same whether the account exists or not.
+// @Tags Auth
+// @Accept x-www-form-urlencoded
+// @Produce json
+// @Param email formData string true "email"
+// @Success 202 {object} responses.UserResponse
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 500 {object} responses.ErrorResponse
+// @Router /auth/password/forgot [post]
+// ForgotPassword controller to send a password reset link
+func (h AuthHandler) ForgotPassword(c *fiber.Ctx) error {
+	// Convert the request data to the structure
+	data := requests.ForgotPasswordRequest{}
+	if err := c.BodyParser(&data); err != nil {
+		logger.Error(fmt.Sprintf("Error decode: %s", err.Error()))
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Invalid data",
+		})
+	}
+
+	// validates the structure
+	if err := utils.GetValidator().Struct(data); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": err.Error(),
+		})
+	}
+
+	// calls use case to send the reset link
+	if appErr := h.PasswordSrv.ForgotPassword(data); appErr != nil {
+		return c.Status(appErr.Code).JSON(appErr.AsMessage())
+	}
+
+	return c.Status(fiber.StatusAccepted).JSON(fiber.Map{
+		"message": "If the account exists, a password reset link has been sent",
+	})
+}
+
+// ResetPassword godoc
+// @Summary reset password.
+// @Description endpoint for change the password using a reset token.
+// @Tags Auth
+// @Accept x-www-form-urlencoded
+// @Produce json
+// @Param token formData string true "reset token"
+// @Param password formData string true "password"
+// @Param password_confirmation formData string true "password"
+// @Success 200 {object} responses.UserResponse
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 500 {object} responses.ErrorResponse
+// @Router /auth/password/reset [post]
+// ResetPassword controller to change the password using a reset token
+func (h AuthHandler) ResetPassword(c *fiber.Ctx) error {
+	// Convert the request data to the structure
+	data := requests.ResetPasswordRequest{}
+	if err := c.BodyParser(&data); err != nil {
+		logger.Error(fmt.Sprintf("Error decode: %s", err.Error()))
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Invalid data",
+		})
+	}
+
+	// validates the structure
+	if err := utils.GetValidator().Struct(data); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": err.Error(),
+		})
+	}
+
+	// calls use case to reset the password
+	if appErr := h.PasswordSrv.ResetPassword(data); appErr != nil {
+		return c.Status(appErr.Code).JSON(appErr.AsMessage())
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Password updated",
+	})
+}
+
+// ChangePassword godoc
+// @Summary change password.
+// @Description endpoint for change the password of the authenticated user, every token issued before is rejected.
+// @Tags Auth
+// @Accept x-www-form-urlencoded
+// @Produce json
+// @Param current_password formData string true "current password"
+// @Param password formData string true "password"
+// @Param password_confirmation formData string true "password"
+// @Success 200 {object} responses.UserResponse
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 500 {object} responses.ErrorResponse
+// @Security Bearer
+// @Router /auth/password/change [post]
+// ChangePassword controller to change the password of the authenticated user
+func (h AuthHandler) ChangePassword(c *fiber.Ctx) error {
+	// Convert the request data to the structure
+	data := requests.ChangePasswordRequest{}
+	if err := c.BodyParser(&data); err != nil {
+		logger.Error(fmt.Sprintf("Error decode: %s", err.Error()))
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Invalid data",
+		})
+	}
+
+	// validates the structure
+	if err := utils.GetValidator().Struct(data); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": err.Error(),
+		})
+	}
+
+	// calls use case to change the password
+	if appErr := h.PasswordSrv.ChangePassword(middlewares.CurrentUser(c).UserID, data); appErr != nil {
+		return c.Status(appErr.Code).JSON(appErr.AsMessage())
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Password updated, please login again",
+	})
+}
+
+// VerifyEmail godoc
+// @Summary verify email.
+// @Description endpoint for confirm the email address with the link sent on signup.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param token query string true "verification token"
+// @Success 200 {object} responses.UserResponse
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 500 {object} responses.ErrorResponse
+// @Router /auth/verify [get]
+// VerifyEmail controller to confirm the email address
+func (h AuthHandler) VerifyEmail(c *fiber.Ctx) error {
+	token := c.Query("token")
+	if token == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Missing verification token",
+		})
+	}
+
+	// calls use case to verify the email
+	if appErr := h.VerificationSrv.VerifyEmail(token); appErr != nil {
+		return c.Status(appErr.Code).JSON(appErr.AsMessage())
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Email verified",
+	})
+}
+
+// ResendVerification godoc
+// @Summary resend verification email.
+// @Description endpoint for send again the verification link, it can only be requested once per interval.
+// @Tags Auth
+// @Accept x-www-form-urlencoded
+// @Produce json
+// @Param email formData string true "email"
+// @Success 202 {object} responses.UserResponse
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 429 {object} responses.ErrorResponse
+// @Failure 500 {object} responses.ErrorResponse
+// @Router /auth/verify/resend [post]
+// ResendVerification controller to send again the verification link
+func (h AuthHandler) ResendVerification(c *fiber.Ctx) error {
+	// Convert the request data to the structure
+	data := requests.ResendVerificationRequest{}
+	if err := c.BodyParser(&data); err != nil {
+		logger.Error(fmt.Sprintf("Error decode: %s", err.Error()))
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Invalid data",
+		})
+	}
+
+	// validates the structure
+	if err := utils.GetValidator().Struct(data); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": err.Error(),
+		})
+	}
+
+	// calls use case to send the verification link
+	if appErr := h.VerificationSrv.ResendVerification(data); appErr != nil {
+		return c.Status(appErr.Code).JSON(appErr.AsMessage())
+	}
+
+	return c.Status(fiber.StatusAccepted).JSON(fiber.Map{
+		"message": "If the account exists and is not verified, a verification link has been sent",
+	})
+}
@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"github.com/gofiber/fiber/v2"
+	"github.com/karlbehrensg/go-fiber-template/cmd/api/internal/http/middlewares"
 	"github.com/karlbehrensg/go-fiber-template/internal/http/requests"
 	"github.com/karlbehrensg/go-fiber-template/internal/http/responses"
 	"github.com/karlbehrensg/go-fiber-template/internal/service"
@@ -12,6 +13,7 @@ import (
 
 type AuthorHandler struct {
 	Service service.AuthorService
+	BookSrv service.BookService
 }
 
 // CreateAuthor godoc
@@ -24,6 +26,7 @@ type AuthorHandler struct {
 // @Success 201 {object} responses.AuthorResponse
 // @Failure 400 {object} responses.ErrorResponse
 // @Failure 401 {object} responses.ErrorResponse
+// @Failure 403 {object} responses.ErrorResponse
 // @Failure 500 {object} responses.ErrorResponse
 // @Security Bearer
 // @Router /author [post]
@@ -46,7 +49,7 @@ func (h AuthorHandler) CreateAuthor(c *fiber.Ctx) error {
 	}
 
 	// calls use case to create author
-	if err := h.Service.CreateAuthor(*data); err != nil {
+	if err := h.Service.CreateAuthor(middlewares.CurrentActor(c), *data); err != nil {
 		return c.Status(err.Code).JSON(err.AsMessage())
 	}
 
@@ -56,24 +59,40 @@ func (h AuthorHandler) CreateAuthor(c *fiber.Ctx) error {
 }
 
 // GetAllAuthor godoc
-// @Description Get all exists authors.
-// @Summary get all exists authors
+// @Description Get a page of authors, sorted and filtered.
+// @Summary get a page of authors
 // @Tags Author
 // @Accept json
 // @Produce json
-// @Success 200 {array} responses.AuthorResponse
+// @Param page query int false "page, starting at 1, can not be used with cursor"
+// @Param limit query int false "authors per page, max 100"
+// @Param cursor query string false "cursor of the next or prev link"
+// @Param sort query string false "comma separated fields, descending with -" example(-full_name)
+// @Param full_name query string false "equal to"
+// @Param full_name[contains] query string false "contains, case insensitive"
+// @Param include query string false "associations returned with each author" Enums(books)
+// @Success 200 {object} responses.AuthorListResponse
 // @Failure 400 {object} responses.ErrorResponse
 // @Failure 401 {object} responses.ErrorResponse
 // @Failure 500 {object} responses.ErrorResponse
 // @Security Bearer
 // @Router /author [get]
-// GetAllAuthor controller to get all author
+// GetAllAuthor controller to get a page of authors
 func (h AuthorHandler) GetAllAuthor(c *fiber.Ctx) error {
-	var response []responses.AuthorResponse
-	var err *errs.AppError
-	// calls use case to get all author
-	if response, err = h.Service.FindAllAuthor(); err != nil {
-		return c.Status(err.Code).JSON(err.AsMessage())
+	// Convert the query string to the structure
+	data, err := parseListRequest(c)
+	if err != nil {
+		logger.Error(err.Error())
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": err.Error(),
+		})
+	}
+
+	var response *responses.AuthorListResponse
+	var appErr *errs.AppError
+	// calls use case to get a page of authors
+	if response, appErr = h.Service.FindAllAuthor(*data); appErr != nil {
+		return c.Status(appErr.Code).JSON(appErr.AsMessage())
 	}
 
 	return c.Status(fiber.StatusOK).JSON(response)
@@ -86,6 +105,7 @@ func (h AuthorHandler) GetAllAuthor(c *fiber.Ctx) error {
 // @Accept json
 // @Produce json
 // @Param id path integer true "Author ID"
+// @Param include query string false "associations returned with the author" Enums(books)
 // @Success 200 {object} responses.AuthorResponse
 // @Failure 400 {object} responses.ErrorResponse
 // @Failure 401 {object} responses.ErrorResponse
@@ -107,7 +127,61 @@ func (h AuthorHandler) GetAuthorById(c *fiber.Ctx) error {
 	var response *responses.AuthorResponse
 	var appErr *errs.AppError
 	// calls use case to find author by ID
-	if response, appErr = h.Service.FindAuthorById(uint(id)); appErr != nil {
+	if response, appErr = h.Service.FindAuthorById(uint(id), c.Query("include")); appErr != nil {
+		return c.Status(appErr.Code).JSON(appErr.AsMessage())
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+// GetAuthorBooks godoc
+// @Description Get a page of the books of the author, sorted and filtered.
+// @Summary get a page of the books of the author
+// @Tags Author
+// @Accept json
+// @Produce json
+// @Param id path integer true "Author ID"
+// @Param page query int false "page, starting at 1, can not be used with cursor"
+// @Param limit query int false "books per page, max 100"
+// @Param cursor query string false "cursor of the next or prev link"
+// @Param sort query string false "comma separated fields, descending with -" example(-publication_year,title)
+// @Param title query string false "equal to"
+// @Param title[contains] query string false "contains, case insensitive"
+// @Param publication_year query int false "equal to"
+// @Param publication_year[gte] query int false "greater or equal to"
+// @Param publication_year[lte] query int false "less or equal to"
+// @Success 200 {object} responses.BookListResponse
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Failure 500 {object} responses.ErrorResponse
+// @Security Bearer
+// @Router /author/{id}/books [get]
+// GetAuthorBooks controller to get a page of the books of the author
+func (h AuthorHandler) GetAuthorBooks(c *fiber.Ctx) error {
+	var id int
+	var err error
+	// get ID parameter from url
+	if id, err = c.ParamsInt("id"); err != nil {
+		logger.Error(err.Error())
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Invalid author id",
+		})
+	}
+
+	// Convert the query string to the structure
+	data, err := parseListRequest(c)
+	if err != nil {
+		logger.Error(err.Error())
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": err.Error(),
+		})
+	}
+
+	var response *responses.BookListResponse
+	var appErr *errs.AppError
+	// calls use case to get a page of the books of the author
+	if response, appErr = h.BookSrv.FindBooksByAuthor(uint(id), *data); appErr != nil {
 		return c.Status(appErr.Code).JSON(appErr.AsMessage())
 	}
 
@@ -125,6 +199,7 @@ func (h AuthorHandler) GetAuthorById(c *fiber.Ctx) error {
 // @Success 200 {object} responses.AuthorResponse
 // @Failure 400 {object} responses.ErrorResponse
 // @Failure 401 {object} responses.ErrorResponse
+// @Failure 403 {object} responses.ErrorResponse
 // @Failure 500 {object} responses.ErrorResponse
 // @Security Bearer
 // @Router /author/{id} [put]
@@ -160,7 +235,7 @@ func (h AuthorHandler) UpdateAuthor(c *fiber.Ctx) error {
 	var response *responses.AuthorResponse
 	var appErr *errs.AppError
 	// calls use case to update author
-	if response, appErr = h.Service.UpdateAuthor(data); appErr != nil {
+	if response, appErr = h.Service.UpdateAuthor(middlewares.CurrentActor(c), data); appErr != nil {
 		return c.Status(appErr.Code).JSON(appErr.AsMessage())
 	}
 
@@ -169,14 +244,20 @@ func (h AuthorHandler) UpdateAuthor(c *fiber.Ctx) error {
 
 // DeleteAuthor godoc
 // @Summary delete author.
-// @Description endpoint for delete authors.
+// @Description endpoint for delete authors, on_books decides what happens to the books of the author.
 // @Tags Author
 // @Accept json
 // @Produce json
 // @Param id path integer true "Author ID"
+// @Param on_books query string false "policy for the books, defaults to AUTHOR_DELETE_POLICY" Enums(restrict, cascade, reassign)
+// @Param reassign_to query integer false "author receiving the books, required by reassign"
 // @Success 204
 // @Failure 400 {object} responses.ErrorResponse
 // @Failure 401 {object} responses.ErrorResponse
+// @Failure 403 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Failure 409 {object} responses.ErrorResponse
+// @Failure 422 {object} responses.ErrorResponse
 // @Failure 500 {object} responses.ErrorResponse
 // @Security Bearer
 // @Router /author/{id} [delete]
@@ -192,9 +273,26 @@ func (h AuthorHandler) DeleteAuthor(c *fiber.Ctx) error {
 		})
 	}
 
+	// Convert the query string to the structure
+	data := requests.DeleteAuthorRequest{}
+	if err = c.QueryParser(&data); err != nil {
+		logger.Error(err.Error())
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Invalid data",
+		})
+	}
+	data.Id = uint(id)
+
+	// validates the structure
+	if err = utils.GetValidator().Struct(data); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": err.Error(),
+		})
+	}
+
 	var appErr *errs.AppError
 	// calls use case to delete author
-	if appErr = h.Service.DeleteAuthor(uint(id)); appErr != nil {
+	if appErr = h.Service.DeleteAuthor(middlewares.CurrentActor(c), data); appErr != nil {
 		return c.Status(appErr.Code).JSON(appErr.AsMessage())
 	}
 
@@ -42,7 +42,7 @@ func Test_should_return_author_with_status_code_201(t *testing.T) {
 	authorBytes := new(bytes.Buffer)
 	json.NewEncoder(authorBytes).Encode(author)
 
-	mockAuthorService.EXPECT().CreateAuthor(author).Return(nil)
+	mockAuthorService.EXPECT().CreateAuthor(gomock.Any(), author).Return(nil)
 	router.Post("/author", ah.CreateAuthor)
 	request, _ := http.NewRequest(http.MethodPost, "/author", authorBytes)
 	request.Header.Add("Content-Type", "application/json")
@@ -133,7 +133,7 @@ func Test_should_return_status_code_500_with_error_message_when_call_CreateAutho
 
 	rawPayload := []byte(`{"full_name": "J. J. Benitez"}`)
 
-	mockAuthorService.EXPECT().CreateAuthor(author).Return(errs.NewUnexpectedError("Unexpected error from database"))
+	mockAuthorService.EXPECT().CreateAuthor(gomock.Any(), author).Return(errs.NewUnexpectedError("Unexpected error from database"))
 	router.Post("/author", ah.CreateAuthor)
 	request, _ := http.NewRequest(
 		http.MethodPost,
@@ -165,7 +165,7 @@ func Test_should_return_status_code_500_with_error_message_when_call_GetAllAutho
 	teardown := authorSetup(t)
 	defer teardown()
 
-	mockAuthorService.EXPECT().FindAllAuthor().Return(nil, errs.NewUnexpectedError("Unexpected error from database"))
+	mockAuthorService.EXPECT().FindAllAuthor(gomock.Any()).Return(nil, errs.NewUnexpectedError("Unexpected error from database"))
 	router.Get("/author", ah.GetAllAuthor)
 	request, _ := http.NewRequest(http.MethodGet, "/author", nil)
 
@@ -187,26 +187,50 @@ func Test_should_return_authors_with_status_code_200(t *testing.T) {
 	teardown := authorSetup(t)
 	defer teardown()
 
-	authors := []responses.AuthorResponse{
-		{Id: 1, FullName: "J. J. Benitez"},
-		{Id: 2, FullName: "Gabriel Garcia Marquez"},
+	authors := &responses.AuthorListResponse{
+		Items: []responses.AuthorResponse{
+			{Id: 1, FullName: "J. J. Benitez"},
+			{Id: 2, FullName: "Gabriel Garcia Marquez"},
+		},
+		Total: 2,
+		Limit: 20,
+	}
+	expected := requests.ListRequest{
+		Sort:    "-full_name",
+		Filters: map[string]string{"full_name[contains]": "garcia"},
+		Path:    "/author",
 	}
 
-	mockAuthorService.EXPECT().FindAllAuthor().Return(authors, nil)
+	mockAuthorService.EXPECT().FindAllAuthor(expected).Return(authors, nil)
 	router.Get("/author", ah.GetAllAuthor)
-	request, _ := http.NewRequest(http.MethodGet, "/author", nil)
+	request, _ := http.NewRequest(http.MethodGet, "/author?sort=-full_name&full_name%5Bcontains%5D=garcia", nil)
 
 	// Act
 	resp, _ := router.Test(request, -1)
 	decoder := json.NewDecoder(resp.Body)
-	var data []responses.AuthorResponse
+	var data responses.AuthorListResponse
 	if err := decoder.Decode(&data); err != nil {
 		t.Errorf("Test failed while ummarshal resp.Body")
 	}
 
 	// Assert
 	assert.Equal(t, http.StatusOK, resp.StatusCode, "Test failed with different status code")
-	assert.Len(t, authors, 2, "Failed test Len is less than 2")
+	assert.Len(t, data.Items, 2, "Failed test Len is less than 2")
+}
+
+func Test_should_return_status_code_400_when_call_GetAllAuthor_with_page_and_cursor(t *testing.T) {
+	// Arrange
+	teardown := authorSetup(t)
+	defer teardown()
+
+	router.Get("/author", ah.GetAllAuthor)
+	request, _ := http.NewRequest(http.MethodGet, "/author?page=2&cursor=abc", nil)
+
+	// Act
+	resp, _ := router.Test(request, -1)
+
+	// Assert
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode, "Test failed with different status code")
 }
 
 func Test_should_return_author_with_status_code_200_when_call_GetAuthorById(t *testing.T) {
@@ -219,7 +243,7 @@ func Test_should_return_author_with_status_code_200_when_call_GetAuthorById(t *t
 		FullName: "J. J. Benitez",
 	}
 
-	mockAuthorService.EXPECT().FindAuthorById(uint(1)).Return(&author, nil)
+	mockAuthorService.EXPECT().FindAuthorById(uint(1), "").Return(&author, nil)
 	router.Get("/author/:id", ah.GetAuthorById)
 	request, _ := http.NewRequest(http.MethodGet, "/author/1", nil)
 
@@ -261,7 +285,7 @@ func Test_should_return_status_code_500_when_call_GetAuthorById(t *testing.T) {
 	teardown := authorSetup(t)
 	defer teardown()
 
-	mockAuthorService.EXPECT().FindAuthorById(uint(1)).Return(nil, errs.NewNotFoundError("record not found"))
+	mockAuthorService.EXPECT().FindAuthorById(uint(1), "").Return(nil, errs.NewNotFoundError("record not found"))
 	router.Get("/author/:id", ah.GetAuthorById)
 	request, _ := http.NewRequest(http.MethodGet, "/author/1", nil)
 
@@ -296,7 +320,7 @@ func Test_should_return_author_with_status_code_200_when_updated(t *testing.T) {
 	authorBytes := new(bytes.Buffer)
 	json.NewEncoder(authorBytes).Encode(author)
 
-	mockAuthorService.EXPECT().UpdateAuthor(&author).Return(&authorResp, nil)
+	mockAuthorService.EXPECT().UpdateAuthor(gomock.Any(), &author).Return(&authorResp, nil)
 	router.Put("/author/:id", ah.UpdateAuthor)
 	request, _ := http.NewRequest(http.MethodPut, "/author/1", authorBytes)
 	request.Header.Add("Content-Type", "application/json")
@@ -400,7 +424,7 @@ func Test_should_return_status_code_500_when_call_UpdateAuthor(t *testing.T) {
 	authorBytes := new(bytes.Buffer)
 	json.NewEncoder(authorBytes).Encode(author)
 
-	mockAuthorService.EXPECT().UpdateAuthor(&author).Return(nil, errs.NewUnexpectedError("key full_name duplicate value"))
+	mockAuthorService.EXPECT().UpdateAuthor(gomock.Any(), &author).Return(nil, errs.NewUnexpectedError("key full_name duplicate value"))
 	router.Put("/author/:id", ah.UpdateAuthor)
 	request, _ := http.NewRequest(
 		http.MethodPut,
@@ -427,7 +451,7 @@ func Test_should_deleted_author_and_return_status_code_204(t *testing.T) {
 	teardown := authorSetup(t)
 	defer teardown()
 
-	mockAuthorService.EXPECT().DeleteAuthor(uint(1)).Return(nil)
+	mockAuthorService.EXPECT().DeleteAuthor(gomock.Any(), requests.DeleteAuthorRequest{Id: 1}).Return(nil)
 	router.Delete("/author/:id", ah.DeleteAuthor)
 	request, _ := http.NewRequest(http.MethodDelete, "/author/1", nil)
 
@@ -464,7 +488,7 @@ func Test_should_return_status_code_500_when_call_DeleteAuthor(t *testing.T) {
 	teardown := authorSetup(t)
 	defer teardown()
 
-	mockAuthorService.EXPECT().DeleteAuthor(uint(1)).Return(errs.NewUnexpectedError("Unexpected error from database"))
+	mockAuthorService.EXPECT().DeleteAuthor(gomock.Any(), requests.DeleteAuthorRequest{Id: 1}).Return(errs.NewUnexpectedError("Unexpected error from database"))
 	router.Put("/author/:id", ah.DeleteAuthor)
 	request, _ := http.NewRequest(
 		http.MethodPut,
@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"github.com/gofiber/fiber/v2"
+	"github.com/karlbehrensg/go-fiber-template/cmd/api/internal/http/middlewares"
 	"github.com/karlbehrensg/go-fiber-template/internal/http/requests"
 	"github.com/karlbehrensg/go-fiber-template/internal/http/responses"
 	"github.com/karlbehrensg/go-fiber-template/internal/service"
@@ -24,6 +25,9 @@ type BookHandler struct {
 // @Success 201 {object} responses.BookResponse
 // @Failure 400 {object} responses.ErrorResponse
 // @Failure 401 {object} responses.ErrorResponse
+// @Failure 403 {object} responses.ErrorResponse
+// @Failure 409 {object} responses.ErrorResponse
+// @Failure 422 {object} responses.ErrorResponse
 // @Failure 500 {object} responses.ErrorResponse
 // @Security Bearer
 // @Router /book [post]
@@ -46,7 +50,7 @@ func (h BookHandler) CreateBook(c *fiber.Ctx) error {
 	}
 
 	// calls use case to create book
-	if err := h.Service.CreateBook(*data); err != nil {
+	if err := h.Service.CreateBook(middlewares.CurrentActor(c), *data); err != nil {
 		return c.Status(err.Code).JSON(err.AsMessage())
 	}
 
@@ -56,25 +60,43 @@ func (h BookHandler) CreateBook(c *fiber.Ctx) error {
 }
 
 // GetAllBook godoc
-// @Description Get all exists books.
-// @Summary get all exists books
+// @Description Get a page of books, sorted and filtered.
+// @Summary get a page of books
 // @Tags Book
 // @Accept json
 // @Produce json
-// @Success 200 {array} responses.BookResponse
+// @Param page query int false "page, starting at 1, can not be used with cursor"
+// @Param limit query int false "books per page, max 100"
+// @Param cursor query string false "cursor of the next or prev link"
+// @Param sort query string false "comma separated fields, descending with -" example(-publication_year,title)
+// @Param author_id query int false "equal to"
+// @Param title query string false "equal to"
+// @Param title[contains] query string false "contains, case insensitive"
+// @Param publication_year query int false "equal to"
+// @Param publication_year[gte] query int false "greater or equal to"
+// @Param publication_year[lte] query int false "less or equal to"
+// @Success 200 {object} responses.BookListResponse
 // @Failure 400 {object} responses.ErrorResponse
 // @Failure 401 {object} responses.ErrorResponse
 // @Failure 500 {object} responses.ErrorResponse
 // @Security Bearer
 // @Router /book [get]
-// GetAllAuthor controller to get all exists book
+// GetAllBook controller to get a page of books
 func (h BookHandler) GetAllBook(c *fiber.Ctx) error {
-	var response []responses.BookResponse
-	var err *errs.AppError
+	// Convert the query string to the structure
+	data, err := parseListRequest(c)
+	if err != nil {
+		logger.Error(err.Error())
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": err.Error(),
+		})
+	}
 
-	// calls use case to get all book
-	if response, err = h.Service.FindAllBook(); err != nil {
-		return c.Status(err.Code).JSON(err.AsMessage())
+	var response *responses.BookListResponse
+	var appErr *errs.AppError
+	// calls use case to get a page of books
+	if response, appErr = h.Service.FindAllBook(*data); appErr != nil {
+		return c.Status(appErr.Code).JSON(appErr.AsMessage())
 	}
 
 	return c.Status(fiber.StatusOK).JSON(response)
@@ -114,6 +136,32 @@ func (h BookHandler) GetBookById(c *fiber.Ctx) error {
 	return c.Status(fiber.StatusOK).JSON(response)
 }
 
+// GetBookByISBN godoc
+// @Description Get book by given ISBN-10 or ISBN-13, hyphens are allowed.
+// @Summary get book by given ISBN
+// @Tags Book
+// @Accept json
+// @Produce json
+// @Param isbn path string true "ISBN-10 or ISBN-13" example(978-0-306-40615-7)
+// @Success 200 {object} responses.BookResponse
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Failure 500 {object} responses.ErrorResponse
+// @Security Bearer
+// @Router /book/isbn/{isbn} [get]
+// GetBookByISBN controller to find book by ISBN
+func (h BookHandler) GetBookByISBN(c *fiber.Ctx) error {
+	var response *responses.BookResponse
+	var appErr *errs.AppError
+	// calls use case to find book by ISBN
+	if response, appErr = h.Service.FindBookByISBN(c.Params("isbn")); appErr != nil {
+		return c.Status(appErr.Code).JSON(appErr.AsMessage())
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
 // UpdateBook godoc
 // @Summary update book.
 // @Description endpoint for update books.
@@ -125,6 +173,9 @@ func (h BookHandler) GetBookById(c *fiber.Ctx) error {
 // @Success 200 {object} responses.BookResponse
 // @Failure 400 {object} responses.ErrorResponse
 // @Failure 401 {object} responses.ErrorResponse
+// @Failure 403 {object} responses.ErrorResponse
+// @Failure 409 {object} responses.ErrorResponse
+// @Failure 422 {object} responses.ErrorResponse
 // @Failure 500 {object} responses.ErrorResponse
 // @Security Bearer
 // @Router /book/{id} [put]
@@ -158,7 +209,7 @@ func (h BookHandler) UpdateBook(c *fiber.Ctx) error {
 	var response *responses.BookResponse
 	var appErr *errs.AppError
 	// calls use case to update book
-	if response, appErr = h.Service.UpdateBook(data); appErr != nil {
+	if response, appErr = h.Service.UpdateBook(middlewares.CurrentActor(c), data); appErr != nil {
 		return c.Status(appErr.Code).JSON(appErr.AsMessage())
 	}
 
@@ -175,6 +226,7 @@ func (h BookHandler) UpdateBook(c *fiber.Ctx) error {
 // @Success 204
 // @Failure 400 {object} responses.ErrorResponse
 // @Failure 401 {object} responses.ErrorResponse
+// @Failure 403 {object} responses.ErrorResponse
 // @Failure 500 {object} responses.ErrorResponse
 // @Security Bearer
 // @Router /book/{id} [delete]
@@ -191,7 +243,7 @@ func (h BookHandler) DeleteBook(c *fiber.Ctx) error {
 
 	var appErr *errs.AppError
 	// calls use case to delete author
-	if appErr = h.Service.DeleteBook(uint(id)); appErr != nil {
+	if appErr = h.Service.DeleteBook(middlewares.CurrentActor(c), uint(id)); appErr != nil {
 		return c.Status(appErr.Code).JSON(appErr.AsMessage())
 	}
 
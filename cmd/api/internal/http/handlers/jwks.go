@@ -0,0 +1,22 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/karlbehrensg/go-fiber-template/internal/http/responses"
+	"github.com/karlbehrensg/go-fiber-template/pkg/utils"
+)
+
+type JWKSHandler struct{}
+
+// GetJWKS godoc
+// @Summary get public keys.
+// @Description endpoint for get the public keys used to verify tokens, empty when tokens are signed with HS256.
+// @Tags Auth
+// @Produce json
+// @Success 200 {object} responses.JWKSResponse
+// @Router /.well-known/jwks.json [get]
+// GetJWKS controller to publish the JWT public keys
+func (h JWKSHandler) GetJWKS(c *fiber.Ctx) error {
+	return c.JSON(responses.JWKSResponse{Keys: utils.GetKeyRing().PublicJWKs()})
+}
@@ -0,0 +1,31 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/karlbehrensg/go-fiber-template/internal/http/requests"
+	"github.com/karlbehrensg/go-fiber-template/pkg/utils"
+)
+
+// parseListRequest convert the query string of a list endpoint, the parameters other than page, limit,
+// cursor, sort and include are the filters, they are validated by the use case
+func parseListRequest(c *fiber.Ctx) (*requests.ListRequest, error) {
+	data := &requests.ListRequest{Filters: map[string]string{}, Path: c.Path()}
+	if err := c.QueryParser(data); err != nil {
+		return nil, err
+	}
+
+	c.Context().QueryArgs().VisitAll(func(key []byte, value []byte) {
+		switch name := string(key); name {
+		case "page", "limit", "cursor", "sort", "include":
+		default:
+			data.Filters[name] = string(value)
+		}
+	})
+
+	// validates the structure
+	if err := utils.GetValidator().Struct(data); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
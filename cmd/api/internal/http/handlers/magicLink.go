@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"fmt"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/karlbehrensg/go-fiber-template/cmd/api/internal/http/middlewares"
+	"github.com/karlbehrensg/go-fiber-template/internal/http/requests"
+	"github.com/karlbehrensg/go-fiber-template/internal/http/responses"
+	"github.com/karlbehrensg/go-fiber-template/internal/service"
+	"github.com/karlbehrensg/go-fiber-template/pkg/errs"
+	"github.com/karlbehrensg/go-fiber-template/pkg/logger"
+	"github.com/karlbehrensg/go-fiber-template/pkg/utils"
+)
+
+type MagicLinkHandler struct {
+	Service service.MagicLinkService
+}
+
+// RequestMagicLink godoc
+// @Summary request login link.
+// @Description endpoint for send a single-use login link, the response is the same whether the account exists or not.
+// @Tags Auth
+// @Accept x-www-form-urlencoded
+// @Produce json
+// @Param email formData string true "email"
+// @Success 202 {object} responses.UserResponse
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 429 {object} responses.ErrorResponse
+// @Failure 500 {object} responses.ErrorResponse
+// @Router /auth/magic-link [post]
+// RequestMagicLink controller to send a login link
+func (h MagicLinkHandler) RequestMagicLink(c *fiber.Ctx) error {
+	// Convert the request data to the structure
+	data := requests.MagicLinkRequest{}
+	if err := c.BodyParser(&data); err != nil {
+		logger.Error(fmt.Sprintf("Error decode: %s", err.Error()))
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Invalid data",
+		})
+	}
+
+	// validates the structure
+	if err := utils.GetValidator().Struct(data); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": err.Error(),
+		})
+	}
+
+	// calls use case to send the login link
+	if appErr := h.Service.RequestMagicLink(data); appErr != nil {
+		return c.Status(appErr.Code).JSON(appErr.AsMessage())
+	}
+
+	return c.Status(fiber.StatusAccepted).JSON(fiber.Map{
+		"message": "If the account exists, a login link has been sent",
+	})
+}
+
+// MagicLinkCallback godoc
+// @Summary login with link.
+// @Description endpoint for exchange the login link for the same tokens as login.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param token query string true "login link token"
+// @Success 201 {object} responses.LoginResponse
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 500 {object} responses.ErrorResponse
+// @Router /auth/magic-link/callback [get]
+// MagicLinkCallback controller to login with the link
+func (h MagicLinkHandler) MagicLinkCallback(c *fiber.Ctx) error {
+	data := requests.MagicLinkLoginRequest{Token: c.Query("token")}
+	if data.Token == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Missing login link token",
+		})
+	}
+	data.IP = c.IP()
+	data.UserAgent = c.Get(fiber.HeaderUserAgent)
+	data.RequestID = middlewares.RequestID(c)
+
+	var response *responses.LoginResponse
+	var appErr *errs.AppError
+	// calls use case to generate token
+	if response, appErr = h.Service.LoginWithMagicLink(data); appErr != nil {
+		return c.Status(appErr.Code).JSON(appErr.AsMessage())
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(response)
+}
@@ -0,0 +1,176 @@
+package handlers
+
+import (
+	"fmt"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/karlbehrensg/go-fiber-template/cmd/api/internal/http/middlewares"
+
+	"github.com/karlbehrensg/go-fiber-template/internal/http/requests"
+	"github.com/karlbehrensg/go-fiber-template/internal/http/responses"
+	"github.com/karlbehrensg/go-fiber-template/internal/service"
+	"github.com/karlbehrensg/go-fiber-template/pkg/errs"
+	"github.com/karlbehrensg/go-fiber-template/pkg/logger"
+	"github.com/karlbehrensg/go-fiber-template/pkg/utils"
+)
+
+type MFAHandler struct {
+	MFASrv service.MFAService
+}
+
+// EnrollTOTP godoc
+// @Summary start TOTP enrollment.
+// @Description endpoint for create the TOTP secret and the otpauth URI for authenticator apps.
+// @Tags MFA
+// @Accept json
+// @Produce json
+// @Success 201 {object} responses.TOTPEnrollmentResponse
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 500 {object} responses.ErrorResponse
+// @Security Bearer
+// @Router /auth/mfa/totp/enroll [post]
+// EnrollTOTP controller to start the TOTP enrollment
+func (h MFAHandler) EnrollTOTP(c *fiber.Ctx) error {
+	var response *responses.TOTPEnrollmentResponse
+	var appErr *errs.AppError
+	// calls use case to create the TOTP secret
+	if response, appErr = h.MFASrv.EnrollTOTP(middlewares.CurrentUser(c).UserID); appErr != nil {
+		return c.Status(appErr.Code).JSON(appErr.AsMessage())
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(response)
+}
+
+// ConfirmTOTP godoc
+// @Summary confirm TOTP enrollment.
+// @Description endpoint for enable two-factor authentication with a first code, returns the recovery codes only once.
+// @Tags MFA
+// @Accept x-www-form-urlencoded
+// @Produce json
+// @Param code formData string true "TOTP code"
+// @Success 200 {object} responses.RecoveryCodesResponse
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 423 {object} responses.ErrorResponse
+// @Failure 429 {object} responses.ErrorResponse
+// @Failure 500 {object} responses.ErrorResponse
+// @Security Bearer
+// @Router /auth/mfa/totp/confirm [post]
+// ConfirmTOTP controller to enable two-factor authentication
+func (h MFAHandler) ConfirmTOTP(c *fiber.Ctx) error {
+	// Convert the request data to the structure
+	data := requests.MFACodeRequest{}
+	if err := c.BodyParser(&data); err != nil {
+		logger.Error(fmt.Sprintf("Error decode: %s", err.Error()))
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Invalid data",
+		})
+	}
+
+	// validates the structure
+	if err := utils.GetValidator().Struct(data); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": err.Error(),
+		})
+	}
+
+	var response *responses.RecoveryCodesResponse
+	var appErr *errs.AppError
+	// calls use case to enable two-factor authentication
+	if response, appErr = h.MFASrv.ConfirmTOTP(middlewares.CurrentUser(c).UserID, data); appErr != nil {
+		return c.Status(appErr.Code).JSON(appErr.AsMessage())
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+// DisableTOTP godoc
+// @Summary disable TOTP.
+// @Description endpoint for disable two-factor authentication with a TOTP or recovery code.
+// @Tags MFA
+// @Accept x-www-form-urlencoded
+// @Produce json
+// @Param code formData string true "TOTP or recovery code"
+// @Success 200 {object} responses.UserResponse
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 423 {object} responses.ErrorResponse
+// @Failure 429 {object} responses.ErrorResponse
+// @Failure 500 {object} responses.ErrorResponse
+// @Security Bearer
+// @Router /auth/mfa/totp [delete]
+// DisableTOTP controller to disable two-factor authentication
+func (h MFAHandler) DisableTOTP(c *fiber.Ctx) error {
+	// Convert the request data to the structure
+	data := requests.MFACodeRequest{}
+	if err := c.BodyParser(&data); err != nil {
+		logger.Error(fmt.Sprintf("Error decode: %s", err.Error()))
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Invalid data",
+		})
+	}
+
+	// validates the structure
+	if err := utils.GetValidator().Struct(data); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": err.Error(),
+		})
+	}
+
+	// calls use case to disable two-factor authentication
+	if appErr := h.MFASrv.DisableTOTP(middlewares.CurrentUser(c).UserID, data); appErr != nil {
+		return c.Status(appErr.Code).JSON(appErr.AsMessage())
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Two-factor authentication disabled",
+	})
+}
+
+// VerifyMFA godoc
+// @Summary complete login with the second factor.
+// @Description endpoint for exchange the MFA challenge returned by login and a TOTP or recovery code for the tokens.
+// @Tags MFA
+// @Accept x-www-form-urlencoded
+// @Produce json
+// @Param mfa_token formData string true "MFA challenge"
+// @Param code formData string true "TOTP or recovery code"
+// @Success 201 {object} responses.LoginResponse
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 423 {object} responses.ErrorResponse
+// @Failure 429 {object} responses.ErrorResponse
+// @Failure 500 {object} responses.ErrorResponse
+// @Router /auth/mfa/verify [post]
+// VerifyMFA controller to complete the login with the second factor
+func (h MFAHandler) VerifyMFA(c *fiber.Ctx) error {
+	// Convert the request data to the structure
+	data := requests.MFAVerifyRequest{}
+	if err := c.BodyParser(&data); err != nil {
+		logger.Error(fmt.Sprintf("Error decode: %s", err.Error()))
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Invalid data",
+		})
+	}
+
+	// validates the structure
+	if err := utils.GetValidator().Struct(data); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": err.Error(),
+		})
+	}
+	data.IP = c.IP()
+	data.UserAgent = c.Get(fiber.HeaderUserAgent)
+	data.RequestID = middlewares.RequestID(c)
+
+	var response *responses.LoginResponse
+	var appErr *errs.AppError
+	// calls use case to generate token
+	if response, appErr = h.MFASrv.VerifyMFA(data); appErr != nil {
+		return c.Status(appErr.Code).JSON(appErr.AsMessage())
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(response)
+}
@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/karlbehrensg/go-fiber-template/cmd/api/internal/http/middlewares"
+	"github.com/karlbehrensg/go-fiber-template/internal/http/requests"
+	"github.com/karlbehrensg/go-fiber-template/internal/http/responses"
+	"github.com/karlbehrensg/go-fiber-template/internal/service"
+	"github.com/karlbehrensg/go-fiber-template/pkg/errs"
+	"github.com/karlbehrensg/go-fiber-template/pkg/logger"
+)
+
+// oidcFlowCookie cookie keeping the state, nonce and PKCE verifier between login and callback
+const oidcFlowCookie = "oidc_flow"
+
+type OIDCHandler struct {
+	Service service.OIDCService
+}
+
+// OIDCLogin godoc
+// @Summary login with the identity provider.
+// @Description endpoint for redirect to the identity provider using the authorization code flow with PKCE.
+// @Tags Auth
+// @Success 302
+// @Failure 500 {object} responses.ErrorResponse
+// @Router /auth/oidc/login [get]
+// OIDCLogin controller to start the OIDC login
+func (h OIDCHandler) OIDCLogin(c *fiber.Ctx) error {
+	redirectURL, flow, appErr := h.Service.BeginLogin()
+	if appErr != nil {
+		return c.Status(appErr.Code).JSON(appErr.AsMessage())
+	}
+
+	c.Cookie(&fiber.Cookie{
+		Name:     oidcFlowCookie,
+		Value:    flow,
+		Path:     "/auth/oidc",
+		MaxAge:   600,
+		Secure:   os.Getenv("ENV") == "production",
+		HTTPOnly: true,
+		SameSite: fiber.CookieSameSiteLaxMode,
+	})
+
+	return c.Redirect(redirectURL, fiber.StatusFound)
+}
+
+// OIDCCallback godoc
+// @Summary complete login with the identity provider.
+// @Description endpoint where the identity provider redirects back, it returns the same tokens as login.
+// @Tags Auth
+// @Produce json
+// @Param code query string true "authorization code"
+// @Param state query string true "state"
+// @Success 201 {object} responses.LoginResponse
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 500 {object} responses.ErrorResponse
+// @Router /auth/oidc/callback [get]
+// OIDCCallback controller to complete the OIDC login
+func (h OIDCHandler) OIDCCallback(c *fiber.Ctx) error {
+	// Convert the request data to the structure
+	data := requests.OIDCCallbackRequest{}
+	if err := c.QueryParser(&data); err != nil {
+		logger.Error(fmt.Sprintf("Error decode: %s", err.Error()))
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Invalid data",
+		})
+	}
+	if data.Error == "" && (data.Code == "" || data.State == "") {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "code and state are required",
+		})
+	}
+	data.Flow = c.Cookies(oidcFlowCookie)
+	data.IP = c.IP()
+	data.UserAgent = c.Get(fiber.HeaderUserAgent)
+	data.RequestID = middlewares.RequestID(c)
+	// the flow can only be used once
+	c.ClearCookie(oidcFlowCookie)
+
+	var response *responses.LoginResponse
+	var appErr *errs.AppError
+	// calls use case to generate token
+	if response, appErr = h.Service.CompleteLogin(data); appErr != nil {
+		return c.Status(appErr.Code).JSON(appErr.AsMessage())
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(response)
+}
@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"fmt"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/karlbehrensg/go-fiber-template/internal/http/requests"
+	"github.com/karlbehrensg/go-fiber-template/internal/http/responses"
+	"github.com/karlbehrensg/go-fiber-template/internal/service"
+	"github.com/karlbehrensg/go-fiber-template/pkg/errs"
+	"github.com/karlbehrensg/go-fiber-template/pkg/logger"
+	"github.com/karlbehrensg/go-fiber-template/pkg/utils"
+)
+
+type SearchHandler struct {
+	Service service.SearchService
+}
+
+// Search godoc
+// @Summary search books and authors.
+// @Description endpoint for search book titles and author names, ignoring accents and case, matching prefixes and tolerating typos.
+// @Tags Search
+// @Accept json
+// @Produce json
+// @Param q query string true "words to search" example(benitez caballo)
+// @Param type query string false "only hits of this type" Enums(book, author)
+// @Param limit query int false "hits returned, max 50"
+// @Success 200 {object} responses.SearchResponse
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 500 {object} responses.ErrorResponse
+// @Security Bearer
+// @Router /search [get]
+// Search controller to search books and authors
+func (h SearchHandler) Search(c *fiber.Ctx) error {
+	// Convert the query string to the structure
+	data := requests.SearchRequest{}
+	if err := c.QueryParser(&data); err != nil {
+		logger.Error(fmt.Sprintf("Error decode: %s", err.Error()))
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Invalid data",
+		})
+	}
+
+	// validates the structure
+	if err := utils.GetValidator().Struct(data); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": err.Error(),
+		})
+	}
+
+	var response *responses.SearchResponse
+	var appErr *errs.AppError
+	// calls use case to search
+	if response, appErr = h.Service.Search(data); appErr != nil {
+		return c.Status(appErr.Code).JSON(appErr.AsMessage())
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+// RebuildSearchIndex godoc
+// @Summary rebuild the search index.
+// @Description endpoint for rebuild the search index from the authors and books of the database.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Success 200 {object} responses.SearchRebuildResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 403 {object} responses.ErrorResponse
+// @Failure 500 {object} responses.ErrorResponse
+// @Security Bearer
+// @Router /admin/search/rebuild [post]
+// RebuildSearchIndex controller to rebuild the search index
+func (h SearchHandler) RebuildSearchIndex(c *fiber.Ctx) error {
+	var response *responses.SearchRebuildResponse
+	var appErr *errs.AppError
+	// calls use case to rebuild the index
+	if response, appErr = h.Service.RebuildIndex(); appErr != nil {
+		return c.Status(appErr.Code).JSON(appErr.AsMessage())
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
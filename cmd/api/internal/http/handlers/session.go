@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/karlbehrensg/go-fiber-template/cmd/api/internal/http/middlewares"
+	"github.com/karlbehrensg/go-fiber-template/internal/http/responses"
+	"github.com/karlbehrensg/go-fiber-template/internal/service"
+	"github.com/karlbehrensg/go-fiber-template/pkg/errs"
+)
+
+type SessionHandler struct {
+	Service service.SessionService
+}
+
+// GetSessions godoc
+// @Summary list sessions.
+// @Description endpoint for list where the authenticated user is logged in, the session of the token is marked as current.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Success 200 {array} responses.SessionResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 500 {object} responses.ErrorResponse
+// @Security Bearer
+// @Router /auth/sessions [get]
+// GetSessions controller to list sessions
+func (h SessionHandler) GetSessions(c *fiber.Ctx) error {
+	var response []responses.SessionResponse
+	var appErr *errs.AppError
+	// calls use case to list sessions
+	if response, appErr = h.Service.FindSessions(middlewares.CurrentUser(c)); appErr != nil {
+		return c.Status(appErr.Code).JSON(appErr.AsMessage())
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+// RevokeSession godoc
+// @Summary sign out session.
+// @Description endpoint for sign out one session of the authenticated user, its tokens are rejected right away.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param id path int true "Session ID"
+// @Success 200 {object} responses.UserResponse
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Failure 500 {object} responses.ErrorResponse
+// @Security Bearer
+// @Router /auth/sessions/{id} [delete]
+// RevokeSession controller to sign out a session
+func (h SessionHandler) RevokeSession(c *fiber.Ctx) error {
+	var id int
+	var err error
+	// get ID parameter from url
+	if id, err = c.ParamsInt("id"); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Invalid session id",
+		})
+	}
+
+	// calls use case to sign out the session
+	if appErr := h.Service.RevokeSession(middlewares.CurrentUser(c), uint(id)); appErr != nil {
+		return c.Status(appErr.Code).JSON(appErr.AsMessage())
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Session signed out",
+	})
+}
+
+// RevokeOtherSessions godoc
+// @Summary sign out everywhere else.
+// @Description endpoint for sign out every session of the authenticated user except the current one.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Success 200 {object} responses.UserResponse
+// @Failure 401 {object} responses.ErrorResponse
+// @Failure 500 {object} responses.ErrorResponse
+// @Security Bearer
+// @Router /auth/sessions [delete]
+// RevokeOtherSessions controller to sign out everywhere else
+func (h SessionHandler) RevokeOtherSessions(c *fiber.Ctx) error {
+	// calls use case to sign out the other sessions
+	if appErr := h.Service.RevokeOtherSessions(middlewares.CurrentUser(c)); appErr != nil {
+		return c.Status(appErr.Code).JSON(appErr.AsMessage())
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Signed out from every other session",
+	})
+}
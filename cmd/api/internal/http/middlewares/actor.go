@@ -0,0 +1,36 @@
+package middlewares
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/karlbehrensg/go-fiber-template/internal/domain"
+)
+
+// requestIDKey key used by the requestid middleware to store the request ID in the context
+const requestIDKey = "requestid"
+
+// RequestID return the ID of the request, empty when the requestid middleware is not used
+func RequestID(c *fiber.Ctx) string {
+	id, _ := c.Locals(requestIDKey).(string)
+	return id
+}
+
+// CurrentActor return the authenticated user and the connection behind the request, the user is empty when
+// the route is not protected by ValidateJWT
+func CurrentActor(c *fiber.Ctx) domain.Actor {
+	actor := domain.Actor{
+		ClientInfo: domain.ClientInfo{
+			IP:        c.IP(),
+			UserAgent: c.Get(fiber.HeaderUserAgent),
+			RequestID: RequestID(c),
+		},
+	}
+	if claims := CurrentUser(c); claims != nil {
+		actor.UserID = claims.UserID
+		actor.Email = claims.Email
+		if claims.IsImpersonated() {
+			actor.ImpersonatorID = claims.Actor.UserID
+		}
+	}
+
+	return actor
+}
@@ -0,0 +1,71 @@
+package middlewares
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/karlbehrensg/go-fiber-template/internal/domain"
+	"github.com/karlbehrensg/go-fiber-template/pkg/logger"
+	"github.com/karlbehrensg/go-fiber-template/pkg/utils"
+)
+
+// DenyImpersonation middleware to block the route for impersonation tokens, it must be used after ValidateJWT
+func DenyImpersonation() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if IsImpersonating(c) {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"message": "Not allowed while impersonating",
+			})
+		}
+
+		return c.Next()
+	}
+}
+
+// IsImpersonating validate if the request was made by an admin acting as the user
+func IsImpersonating(c *fiber.Ctx) bool {
+	claims := CurrentUser(c)
+	return claims != nil && claims.IsImpersonated()
+}
+
+// validateImpersonator reject the token when its admin was disabled, deleted or lost the admin role
+func validateImpersonator(c *fiber.Ctx, config JWTConfig, claims *utils.JWTClaims) error {
+	admin, appErr := config.Users.FindUserById(claims.Actor.UserID)
+	if appErr != nil && !strings.Contains(appErr.Message, "record not found") {
+		return c.Status(appErr.Code).JSON(appErr.AsMessage())
+	}
+	if admin == nil || admin.IsDisabled() || admin.Role != domain.RoleAdmin {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"message": "Impersonation is no longer allowed",
+		})
+	}
+
+	return nil
+}
+
+// logImpersonatedRequest record the request made by the admin as the user
+func logImpersonatedRequest(c *fiber.Ctx, config JWTConfig, claims *utils.JWTClaims) {
+	request := fmt.Sprintf("%s %s", c.Method(), c.Path())
+	logger.Info(fmt.Sprintf("Admin id=%d impersonating user id=%d: %s", claims.Actor.UserID, claims.UserID, request))
+
+	if config.Audit == nil {
+		return
+	}
+
+	actor := CurrentActor(c)
+	if appErr := config.Audit.SaveAuditEntry(&domain.AuditEntry{
+		Action:         domain.AuditImpersonatedRequest,
+		ActorID:        actor.UserID,
+		ActorEmail:     actor.Email,
+		ImpersonatorID: actor.ImpersonatorID,
+		TargetType:     "user",
+		TargetID:       strconv.FormatUint(uint64(claims.UserID), 10),
+		Detail:         request,
+		IP:             actor.IP,
+		RequestID:      actor.RequestID,
+	}); appErr != nil {
+		logger.Error(fmt.Sprintf("Error while saving audit entry %s: %s", domain.AuditImpersonatedRequest, appErr.Message))
+	}
+}
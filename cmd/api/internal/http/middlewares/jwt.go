@@ -1,29 +1,48 @@
 package middlewares
 
 import (
+	"errors"
 	"strings"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/karlbehrensg/go-fiber-template/internal/domain"
+	"github.com/karlbehrensg/go-fiber-template/internal/service"
 	"github.com/karlbehrensg/go-fiber-template/pkg/logger"
 	"github.com/karlbehrensg/go-fiber-template/pkg/utils"
 )
 
-// ValidateJWT middleware to validate JWT
-func ValidateJWT() fiber.Handler {
+// claimsKey key used to store the validated claims in the context
+const claimsKey = "jwtClaims"
+
+// JWTConfig dependencies used to validate JWT
+type JWTConfig struct {
+	RevokedTokens domain.RevokedTokenRepository
+	// Users rejects tokens of disabled or deleted users and tokens issued before the last password change,
+	// the check is skipped when it is nil
+	Users domain.UserRepository
+	// Sessions rejects tokens of signed out sessions, the check is skipped when it is nil
+	Sessions domain.SessionRepository
+	// APIKeys validates "Authorization: ApiKey <key>", API keys are rejected when it is nil
+	APIKeys service.APIKeyService
+	// Audit records every request made with an impersonation token, they are only logged when it is nil
+	Audit domain.AuditRepository
+}
+
+// ValidateJWT middleware to validate JWT, or API keys when they are enabled in the config
+func ValidateJWT(config JWTConfig) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		// Get token from header
-		authHeader := c.Get("Authorization")
-		if authHeader == "" {
+		scheme, token, _ := strings.Cut(c.Get("Authorization"), " ")
+		token = strings.TrimSpace(token)
+		if token == "" {
 			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
 				"message": "Missing or malformed token",
 			})
 		}
 
-		token := strings.TrimSpace(strings.Split(authHeader, " ")[1])
-		if token == "" {
-			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-				"message": "Missing or malformed token",
-			})
+		if strings.EqualFold(scheme, "ApiKey") {
+			return validateAPIKey(c, config, token)
 		}
 
 		// Validate token
@@ -31,10 +50,119 @@ func ValidateJWT() fiber.Handler {
 		if err := claims.ValidateToken(token); err != nil {
 			logger.Error(err.Error())
 			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-				"message": "Invalid or expired token",
+				"message": tokenErrorMessage(err),
+			})
+		}
+
+		// Validate the token was not revoked by logout
+		revoked, appErr := config.RevokedTokens.IsTokenRevoked(claims.Id)
+		if appErr != nil {
+			return c.Status(appErr.Code).JSON(appErr.AsMessage())
+		}
+		if revoked {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"message": "Token has been revoked",
 			})
 		}
 
+		// Validate the user is still active and the token was issued after the last password change
+		if config.Users != nil {
+			u, appErr := config.Users.FindUserById(claims.UserID)
+			if appErr != nil && !strings.Contains(appErr.Message, "record not found") {
+				return c.Status(appErr.Code).JSON(appErr.AsMessage())
+			}
+			if u == nil || u.TokenVersion != claims.TokenVersion {
+				return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+					"message": "Token has been revoked",
+				})
+			}
+			if u.IsDisabled() {
+				return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+					"message": "Account is disabled",
+				})
+			}
+			// the role can be changed by an admin after the token was issued
+			claims.Role = u.Role
+
+			// the admin behind an impersonation token must still be an active admin
+			if claims.IsImpersonated() {
+				if err := validateImpersonator(c, config, claims); err != nil {
+					return err
+				}
+			}
+		}
+
+		// Validate the session of the token was not signed out
+		if config.Sessions != nil && claims.SessionID != 0 {
+			session, appErr := config.Sessions.FindSessionById(claims.SessionID)
+			if appErr != nil && !strings.Contains(appErr.Message, "record not found") {
+				return c.Status(appErr.Code).JSON(appErr.AsMessage())
+			}
+			if session == nil || !session.IsActive() || session.UserID != claims.UserID {
+				return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+					"message": "Session has been signed out",
+				})
+			}
+			// the last use is tracked with minute precision to avoid a write per request
+			if now := time.Now(); now.Sub(session.LastSeenAt) > time.Minute {
+				config.Sessions.TouchSession(session.ID, c.IP(), now)
+			}
+		}
+
+		c.Locals(claimsKey, claims)
+
+		if claims.IsImpersonated() {
+			logImpersonatedRequest(c, config, claims)
+		}
+
 		return c.Next()
 	}
 }
+
+// validateAPIKey authenticate the request with an API key
+func validateAPIKey(c *fiber.Ctx, config JWTConfig, key string) error {
+	if config.APIKeys == nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"message": "API keys are not accepted on this endpoint",
+		})
+	}
+
+	claims, appErr := config.APIKeys.AuthenticateAPIKey(key)
+	if appErr != nil {
+		return c.Status(appErr.Code).JSON(appErr.AsMessage())
+	}
+
+	c.Locals(claimsKey, claims)
+
+	return c.Next()
+}
+
+// tokenErrorMessage message returned to the client for each validation error
+func tokenErrorMessage(err error) string {
+	switch {
+	case errors.Is(err, utils.ErrTokenExpired):
+		return "Token has expired"
+	case errors.Is(err, utils.ErrTokenNotValidYet):
+		return "Token is not valid yet"
+	case errors.Is(err, utils.ErrTokenIssuedInFuture):
+		return "Token was issued in the future"
+	case errors.Is(err, utils.ErrInvalidIssuer):
+		return "Invalid token issuer"
+	case errors.Is(err, utils.ErrInvalidAudience):
+		return "Invalid token audience"
+	case errors.Is(err, utils.ErrTokenMissingClaims):
+		return "Token is missing required claims"
+	}
+
+	return "Invalid token signature or format"
+}
+
+// CurrentUser return the claims of the authenticated user, nil when the route is not protected by ValidateJWT
+func CurrentUser(c *fiber.Ctx) *utils.JWTClaims {
+	claims, ok := c.Locals(claimsKey).(*utils.JWTClaims)
+	if !ok {
+		return nil
+	}
+
+	return claims
+}
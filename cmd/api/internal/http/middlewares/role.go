@@ -0,0 +1,27 @@
+package middlewares
+
+import (
+	"github.com/gofiber/fiber/v2"
+)
+
+// RequireRole middleware to allow only the given roles, it must be used after ValidateJWT
+func RequireRole(roles ...string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		claims := CurrentUser(c)
+		if claims == nil {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"message": "Missing or malformed token",
+			})
+		}
+
+		for _, role := range roles {
+			if claims.Role == role {
+				return c.Next()
+			}
+		}
+
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"message": "Insufficient permissions",
+		})
+	}
+}
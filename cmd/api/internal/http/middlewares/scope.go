@@ -0,0 +1,25 @@
+package middlewares
+
+import (
+	"github.com/gofiber/fiber/v2"
+)
+
+// RequireScope middleware to allow only API keys granted with the scope, it must be used after ValidateJWT
+func RequireScope(scope string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		claims := CurrentUser(c)
+		if claims == nil {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"message": "Missing or malformed token",
+			})
+		}
+
+		if !claims.HasScope(scope) {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"message": "API key is missing the " + scope + " scope",
+			})
+		}
+
+		return c.Next()
+	}
+}
@@ -0,0 +1,52 @@
+package routes
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/karlbehrensg/go-fiber-template/cmd/api/internal/http/handlers"
+	"github.com/karlbehrensg/go-fiber-template/cmd/api/internal/http/middlewares"
+	"github.com/karlbehrensg/go-fiber-template/internal/domain"
+	"github.com/karlbehrensg/go-fiber-template/internal/mailer"
+	"github.com/karlbehrensg/go-fiber-template/internal/repository"
+	"github.com/karlbehrensg/go-fiber-template/internal/service"
+
+	"gorm.io/gorm"
+)
+
+// AdminRoutes endpoints for the admin section
+func AdminRoutes(router *fiber.App, dbClient *gorm.DB, jwtConfig middlewares.JWTConfig, searchIndex domain.SearchIndex) {
+	userRepository := repository.NewUserRepositoryGorm(dbClient)
+	auditRepository := repository.NewAuditRepositoryGorm(dbClient)
+	u := handlers.AdminUserHandler{
+		Service: service.NewUserService(userRepository, mailer.NewMailer(), auditRepository),
+		TokenSrv: service.NewTokenService(
+			userRepository,
+			repository.NewRefreshTokenRepositoryGorm(dbClient),
+			jwtConfig.RevokedTokens,
+			jwtConfig.Sessions,
+			auditRepository,
+		),
+	}
+	a := handlers.AuditHandler{Service: service.NewAuditService(auditRepository)}
+	s := handlers.SearchHandler{
+		Service: service.NewSearchService(
+			searchIndex,
+			repository.NewAuthorRepositoryGorm(dbClient),
+			repository.NewBookRepositoryGorm(dbClient),
+		),
+	}
+	// admin endpoints only accept access tokens, never API keys
+	tokenOnly := jwtConfig
+	tokenOnly.APIKeys = nil
+	api := router.Group("/admin")
+	api.Use(middlewares.ValidateJWT(tokenOnly), middlewares.RequireRole(domain.RoleAdmin))
+	api.Get("/users", u.GetUsers)
+	api.Get("/users/:id", u.GetUser)
+	api.Put("/users/:id/role", u.UpdateUserRole)
+	api.Post("/users/:id/disable", u.DisableUser)
+	api.Post("/users/:id/enable", u.EnableUser)
+	api.Delete("/users/:id", u.DeleteUser)
+	api.Post("/users/:id/restore", u.RestoreUser)
+	api.Post("/users/:id/impersonate", u.ImpersonateUser)
+	api.Get("/audit", a.GetAuditEntries)
+	api.Post("/search/rebuild", s.RebuildSearchIndex)
+}
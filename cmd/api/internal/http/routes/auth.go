@@ -1,21 +1,135 @@
 package routes
 
 import (
+	"os"
+	"time"
+
 	"github.com/gofiber/fiber/v2"
 	"github.com/karlbehrensg/go-fiber-template/cmd/api/internal/http/handlers"
+	"github.com/karlbehrensg/go-fiber-template/cmd/api/internal/http/middlewares"
+	"github.com/karlbehrensg/go-fiber-template/internal/domain"
+	"github.com/karlbehrensg/go-fiber-template/internal/mailer"
+	"github.com/karlbehrensg/go-fiber-template/internal/oidc"
 	"github.com/karlbehrensg/go-fiber-template/internal/repository"
 	"github.com/karlbehrensg/go-fiber-template/internal/service"
+	"github.com/karlbehrensg/go-fiber-template/pkg/utils"
 
 	"gorm.io/gorm"
 )
 
 // AuthRoutes endpoints to authentication
-func AuthRoutes(router *fiber.App, dbClient *gorm.DB) {
+func AuthRoutes(router *fiber.App, dbClient *gorm.DB, jwtConfig middlewares.JWTConfig) {
+	userRepository := repository.NewUserRepositoryGorm(dbClient)
+	refreshTokenRepository := repository.NewRefreshTokenRepositoryGorm(dbClient)
+	auditRepository := repository.NewAuditRepositoryGorm(dbClient)
+	tokenService := service.NewTokenService(
+		userRepository,
+		refreshTokenRepository,
+		jwtConfig.RevokedTokens,
+		jwtConfig.Sessions,
+		auditRepository,
+	)
+	loginAttemptRepository := newLoginAttemptRepository(dbClient)
+	mfaChallengeRepository := repository.NewMFAChallengeRepositoryGorm(dbClient)
+	appMailer := mailer.NewMailer()
 	c := handlers.AuthHandler{
-		UserSrv: service.NewUserService(repository.NewUserRepositoryGorm(dbClient)),
-		AuthSrv: service.NewAuthService(repository.NewUserRepositoryGorm(dbClient)),
+		UserSrv: service.NewUserService(userRepository, appMailer, auditRepository),
+		AuthSrv: service.NewAuthService(
+			userRepository,
+			tokenService,
+			loginAttemptRepository,
+			auditRepository,
+			mfaChallengeRepository,
+		),
+		TokenSrv: tokenService,
+		PasswordSrv: service.NewPasswordService(
+			userRepository,
+			repository.NewPasswordResetTokenRepositoryGorm(dbClient),
+			refreshTokenRepository,
+			jwtConfig.Sessions,
+			appMailer,
+		),
+		VerificationSrv: service.NewVerificationService(userRepository, appMailer),
 	}
+	// account endpoints only accept access tokens, never API keys
+	tokenOnly := jwtConfig
+	tokenOnly.APIKeys = nil
+	authenticated := middlewares.ValidateJWT(tokenOnly)
+	// the credentials of the account can not be changed while an admin is impersonating
+	notImpersonated := middlewares.DenyImpersonation()
 	api := router.Group("/auth")
 	api.Post("/signup", c.SignUp)
 	api.Post("login", c.Login)
+	api.Post("/refresh", c.Refresh)
+	api.Post("/logout", authenticated, c.Logout)
+	api.Get("/me", authenticated, c.GetMe)
+	api.Put("/me", authenticated, notImpersonated, c.UpdateMe)
+	api.Post("/password/forgot", c.ForgotPassword)
+	api.Post("/password/reset", c.ResetPassword)
+	api.Post("/password/change", authenticated, notImpersonated, c.ChangePassword)
+	api.Get("/verify", c.VerifyEmail)
+	api.Post("/verify/resend", c.ResendVerification)
+
+	l := handlers.MagicLinkHandler{
+		Service: service.NewMagicLinkService(
+			userRepository,
+			repository.NewMagicLinkTokenRepositoryGorm(dbClient),
+			tokenService,
+			appMailer,
+			loginAttemptRepository,
+			mfaChallengeRepository,
+		),
+	}
+	api.Post("/magic-link", l.RequestMagicLink)
+	api.Get("/magic-link/callback", l.MagicLinkCallback)
+
+	m := handlers.MFAHandler{
+		MFASrv: service.NewMFAService(
+			userRepository,
+			repository.NewRecoveryCodeRepositoryGorm(dbClient),
+			tokenService,
+			loginAttemptRepository,
+			mfaChallengeRepository,
+		),
+	}
+	api.Post("/mfa/verify", m.VerifyMFA)
+	api.Post("/mfa/totp/enroll", authenticated, notImpersonated, m.EnrollTOTP)
+	api.Post("/mfa/totp/confirm", authenticated, notImpersonated, m.ConfirmTOTP)
+	api.Delete("/mfa/totp", authenticated, notImpersonated, m.DisableTOTP)
+
+	s := handlers.SessionHandler{Service: service.NewSessionService(jwtConfig.Sessions)}
+	api.Get("/sessions", authenticated, s.GetSessions)
+	api.Delete("/sessions", authenticated, notImpersonated, s.RevokeOtherSessions)
+	api.Delete("/sessions/:id", authenticated, notImpersonated, s.RevokeSession)
+
+	k := handlers.APIKeyHandler{Service: jwtConfig.APIKeys}
+	api.Post("/api-keys", authenticated, notImpersonated, k.CreateAPIKey)
+	api.Get("/api-keys", authenticated, k.GetAPIKeys)
+	api.Delete("/api-keys/:id", authenticated, notImpersonated, k.RevokeAPIKey)
+
+	// login with the identity provider is only enabled when it is configured
+	if oidcConfig := oidc.ConfigFromEnv(); oidcConfig.Issuer != "" {
+		o := handlers.OIDCHandler{
+			Service: service.NewOIDCService(
+				oidc.NewProvider(oidcConfig),
+				userRepository,
+				tokenService,
+				mfaChallengeRepository,
+			),
+		}
+		api.Get("/oidc/login", o.OIDCLogin)
+		api.Get("/oidc/callback", o.OIDCCallback)
+	}
+}
+
+// newLoginAttemptRepository select the login attempt store from env
+func newLoginAttemptRepository(dbClient *gorm.DB) domain.LoginAttemptRepository {
+	if os.Getenv("LOGIN_ATTEMPT_STORE") == "memory" {
+		return repository.NewLoginAttemptRepositoryMemory(
+			utils.GetEnvDuration("LOGIN_ATTEMPT_PURGE_INTERVAL", time.Minute*5),
+			utils.GetEnvDuration("LOGIN_ATTEMPT_RETENTION", time.Hour),
+		)
+	}
+
+	return repository.NewLoginAttemptRepositoryGorm(dbClient)
 }
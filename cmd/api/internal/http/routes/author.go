@@ -4,6 +4,7 @@ import (
 	"github.com/gofiber/fiber/v2"
 	"github.com/karlbehrensg/go-fiber-template/cmd/api/internal/http/handlers"
 	"github.com/karlbehrensg/go-fiber-template/cmd/api/internal/http/middlewares"
+	"github.com/karlbehrensg/go-fiber-template/internal/domain"
 	"github.com/karlbehrensg/go-fiber-template/internal/repository"
 	"github.com/karlbehrensg/go-fiber-template/internal/service"
 
@@ -11,15 +12,31 @@ import (
 )
 
 // AuthorRoutes endpoints for the author section
-func AuthorRoutes(router *fiber.App, dbClient *gorm.DB) {
+func AuthorRoutes(router *fiber.App, dbClient *gorm.DB, jwtConfig middlewares.JWTConfig, searchIndex domain.SearchIndex) {
+	authorRepository := repository.NewAuthorRepositoryGorm(dbClient)
+	auditRepository := repository.NewAuditRepositoryGorm(dbClient)
 	h := handlers.AuthorHandler{
-		Service: service.NewAuthorService(repository.NewAuthorRepositoryGorm(dbClient)),
+		Service: service.NewAuthorService(authorRepository, auditRepository, searchIndex),
+		BookSrv: service.NewBookService(
+			repository.NewBookRepositoryGorm(dbClient),
+			authorRepository,
+			auditRepository,
+			searchIndex,
+		),
 	}
 	api := router.Group("/author")
-	api.Use(middlewares.ValidateJWT())
-	api.Post("", h.CreateAuthor)
-	api.Get("", h.GetAllAuthor)
-	api.Get("/:id", h.GetAuthorById)
-	api.Put("/:id", h.UpdateAuthor)
-	api.Delete("/:id", h.DeleteAuthor)
+	api.Use(middlewares.ValidateJWT(jwtConfig))
+	// only librarians and admins can change the catalog
+	canWrite := middlewares.RequireRole(domain.RoleLibrarian, domain.RoleAdmin)
+	// API keys can be restricted to read or write the catalog
+	readScope := middlewares.RequireScope(domain.ScopeCatalogRead)
+	writeScope := middlewares.RequireScope(domain.ScopeCatalogWrite)
+	// deleting from the catalog is blocked while an admin is impersonating
+	notImpersonated := middlewares.DenyImpersonation()
+	api.Post("", canWrite, writeScope, h.CreateAuthor)
+	api.Get("", readScope, h.GetAllAuthor)
+	api.Get("/:id", readScope, h.GetAuthorById)
+	api.Get("/:id/books", readScope, h.GetAuthorBooks)
+	api.Put("/:id", canWrite, writeScope, h.UpdateAuthor)
+	api.Delete("/:id", canWrite, writeScope, notImpersonated, h.DeleteAuthor)
 }
@@ -4,6 +4,7 @@ import (
 	"github.com/gofiber/fiber/v2"
 	"github.com/karlbehrensg/go-fiber-template/cmd/api/internal/http/handlers"
 	"github.com/karlbehrensg/go-fiber-template/cmd/api/internal/http/middlewares"
+	"github.com/karlbehrensg/go-fiber-template/internal/domain"
 	"github.com/karlbehrensg/go-fiber-template/internal/repository"
 	"github.com/karlbehrensg/go-fiber-template/internal/service"
 
@@ -11,17 +12,30 @@ import (
 )
 
 // BookRoutes endpoints for the book section
-func BookRoutes(router *fiber.App, dbClient *gorm.DB) {
+func BookRoutes(router *fiber.App, dbClient *gorm.DB, jwtConfig middlewares.JWTConfig, searchIndex domain.SearchIndex) {
 	h := handlers.BookHandler{
-		Service: service.NewBookService(repository.NewBookRepositoryGorm(dbClient)),
+		Service: service.NewBookService(
+			repository.NewBookRepositoryGorm(dbClient),
+			repository.NewAuthorRepositoryGorm(dbClient),
+			repository.NewAuditRepositoryGorm(dbClient),
+			searchIndex,
+		),
 	}
 	// Create routes group.
 	api := router.Group("/book")
 	// use middleware for validate JWT
-	api.Use(middlewares.ValidateJWT())
-	api.Post("", h.CreateBook)
-	api.Get("", h.GetAllBook)
-	api.Get("/:id", h.GetBookById)
-	api.Put("/:id", h.UpdateBook)
-	api.Delete("/:id", h.DeleteBook)
+	api.Use(middlewares.ValidateJWT(jwtConfig))
+	// only librarians and admins can change the catalog
+	canWrite := middlewares.RequireRole(domain.RoleLibrarian, domain.RoleAdmin)
+	// API keys can be restricted to read or write the catalog
+	readScope := middlewares.RequireScope(domain.ScopeCatalogRead)
+	writeScope := middlewares.RequireScope(domain.ScopeCatalogWrite)
+	// deleting from the catalog is blocked while an admin is impersonating
+	notImpersonated := middlewares.DenyImpersonation()
+	api.Post("", canWrite, writeScope, h.CreateBook)
+	api.Get("", readScope, h.GetAllBook)
+	api.Get("/isbn/:isbn", readScope, h.GetBookByISBN)
+	api.Get("/:id", readScope, h.GetBookById)
+	api.Put("/:id", canWrite, writeScope, h.UpdateBook)
+	api.Delete("/:id", canWrite, writeScope, notImpersonated, h.DeleteBook)
 }
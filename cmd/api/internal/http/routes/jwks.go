@@ -0,0 +1,12 @@
+package routes
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/karlbehrensg/go-fiber-template/cmd/api/internal/http/handlers"
+)
+
+// JWKSRoutes endpoints for publish the JWT public keys
+func JWKSRoutes(router *fiber.App) {
+	h := handlers.JWKSHandler{}
+	router.Get("/.well-known/jwks.json", h.GetJWKS)
+}
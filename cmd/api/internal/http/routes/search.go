@@ -0,0 +1,28 @@
+package routes
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/karlbehrensg/go-fiber-template/cmd/api/internal/http/handlers"
+	"github.com/karlbehrensg/go-fiber-template/cmd/api/internal/http/middlewares"
+	"github.com/karlbehrensg/go-fiber-template/internal/domain"
+	"github.com/karlbehrensg/go-fiber-template/internal/repository"
+	"github.com/karlbehrensg/go-fiber-template/internal/service"
+
+	"gorm.io/gorm"
+)
+
+// SearchRoutes endpoints for the search section
+func SearchRoutes(router *fiber.App, dbClient *gorm.DB, jwtConfig middlewares.JWTConfig, searchIndex domain.SearchIndex) {
+	h := handlers.SearchHandler{
+		Service: service.NewSearchService(
+			searchIndex,
+			repository.NewAuthorRepositoryGorm(dbClient),
+			repository.NewBookRepositoryGorm(dbClient),
+		),
+	}
+	api := router.Group("/search")
+	api.Use(middlewares.ValidateJWT(jwtConfig))
+	// API keys can be restricted to read or write the catalog
+	readScope := middlewares.RequireScope(domain.ScopeCatalogRead)
+	api.Get("", readScope, h.Search)
+}
@@ -3,15 +3,26 @@ package http
 import (
 	"fmt"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	fiberLogger "github.com/gofiber/fiber/v2/middleware/logger"
 	"github.com/gofiber/fiber/v2/middleware/recover"
+	"github.com/gofiber/fiber/v2/middleware/requestid"
 	"github.com/joho/godotenv"
+	"gorm.io/gorm"
 
 	"github.com/karlbehrensg/go-fiber-template/cmd/api/internal/config/database"
+	"github.com/karlbehrensg/go-fiber-template/cmd/api/internal/http/middlewares"
 	"github.com/karlbehrensg/go-fiber-template/cmd/api/internal/http/routes"
 	"github.com/karlbehrensg/go-fiber-template/internal/domain"
+	"github.com/karlbehrensg/go-fiber-template/internal/http/requests"
+	"github.com/karlbehrensg/go-fiber-template/internal/mailer"
+	"github.com/karlbehrensg/go-fiber-template/internal/repository"
+	"github.com/karlbehrensg/go-fiber-template/internal/search"
+	"github.com/karlbehrensg/go-fiber-template/internal/service"
 	"github.com/karlbehrensg/go-fiber-template/pkg/logger"
 	"github.com/karlbehrensg/go-fiber-template/pkg/utils"
 )
@@ -26,23 +37,70 @@ func Start() {
 
 	// validate env
 	utils.CheckEnv()
+	// load the JWT keyring, fails fast on misconfiguration
+	go watchKeyRing(utils.GetKeyRing(), utils.GetEnvDuration("JWT_KEYRING_RELOAD_INTERVAL", time.Second*30))
 
 	// get client db
 	dbClient := database.GetDbClient()
+	// accounts created before email verification existed are verified when the column is added
+	verificationMigrated := dbClient.Migrator().HasColumn(&domain.User{}, "VerifiedAt")
 	// run migration
-	database.Migrate(dbClient, &domain.Author{}, &domain.Book{}, &domain.User{})
+	database.Migrate(
+		dbClient,
+		&domain.Author{},
+		&domain.Book{},
+		&domain.User{},
+		&domain.RefreshToken{},
+		&domain.RevokedToken{},
+		&domain.PasswordResetToken{},
+		&domain.LoginAttempt{},
+		&domain.RecoveryCode{},
+		&domain.APIKey{},
+		&domain.MagicLinkToken{},
+		&domain.MFAChallenge{},
+		&domain.Session{},
+		&domain.AuditEntry{},
+	)
+
+	if !verificationMigrated {
+		backfillVerifiedUsers(dbClient)
+	}
+
+	// bootstrap the first admin from env
+	bootstrapAdmin(dbClient)
+
+	// stores shared by the JWT middleware
+	jwtConfig := middlewares.JWTConfig{
+		RevokedTokens: newRevokedTokenRepository(dbClient),
+		Users:         repository.NewUserRepositoryGorm(dbClient),
+		Sessions:      repository.NewSessionRepositoryGorm(dbClient),
+		Audit:         repository.NewAuditRepositoryGorm(dbClient),
+		APIKeys: service.NewAPIKeyService(
+			repository.NewAPIKeyRepositoryGorm(dbClient),
+			repository.NewUserRepositoryGorm(dbClient),
+		),
+	}
+	go purgeExpiredRevokedTokens(jwtConfig.RevokedTokens, utils.GetEnvDuration("REVOKED_TOKEN_PURGE_INTERVAL", time.Hour))
+
+	// the search index lives in memory, it is built from the database on start
+	searchIndex := search.NewMemoryIndex()
+	buildSearchIndex(dbClient, searchIndex)
 
 	// instantiating fiber
 	app := fiber.New()
 	// added middleware
 	app.Use(recover.New())
+	app.Use(requestid.New())
 	app.Use(fiberLogger.New())
 
 	// define routes
 	routes.SwaggerRoutes(app)
-	routes.AuthRoutes(app, dbClient)
-	routes.AuthorRoutes(app, dbClient)
-	routes.BookRoutes(app, dbClient)
+	routes.JWKSRoutes(app)
+	routes.AuthRoutes(app, dbClient, jwtConfig)
+	routes.AuthorRoutes(app, dbClient, jwtConfig, searchIndex)
+	routes.BookRoutes(app, dbClient, jwtConfig, searchIndex)
+	routes.SearchRoutes(app, dbClient, jwtConfig, searchIndex)
+	routes.AdminRoutes(app, dbClient, jwtConfig, searchIndex)
 	routes.NotFoundRoute(app)
 
 	// run server
@@ -51,3 +109,96 @@ func Start() {
 		logger.Fatal(err.Error())
 	}
 }
+
+// newRevokedTokenRepository select the revoked token store from env
+func newRevokedTokenRepository(dbClient *gorm.DB) domain.RevokedTokenRepository {
+	if os.Getenv("REVOKED_TOKEN_STORE") == "memory" {
+		return repository.NewRevokedTokenRepositoryMemory()
+	}
+
+	return repository.NewRevokedTokenRepositoryGorm(dbClient)
+}
+
+// purgeExpiredRevokedTokens delete expired revoked tokens periodically
+func purgeExpiredRevokedTokens(store domain.RevokedTokenRepository, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		store.DeleteExpiredRevokedTokens()
+	}
+}
+
+// watchKeyRing reload the JWT keyring on SIGHUP or when its file changes
+func watchKeyRing(ring *utils.KeyRing, interval time.Duration) {
+	hangup := make(chan os.Signal, 1)
+	signal.Notify(hangup, syscall.SIGHUP)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-hangup:
+		case <-ticker.C:
+			if !ring.Modified() {
+				continue
+			}
+		}
+
+		// a broken file keeps the keys already loaded
+		if err := ring.Reload(); err != nil {
+			logger.Error(fmt.Sprintf("Error reloading JWT keyring: %s", err.Error()))
+			continue
+		}
+		logger.Info("JWT keyring reloaded")
+	}
+}
+
+// buildSearchIndex fill the search index with the authors and books of the database
+func buildSearchIndex(dbClient *gorm.DB, searchIndex domain.SearchIndex) {
+	searchService := service.NewSearchService(
+		searchIndex,
+		repository.NewAuthorRepositoryGorm(dbClient),
+		repository.NewBookRepositoryGorm(dbClient),
+	)
+	// the API starts anyway, searches return no hits until POST /admin/search/rebuild succeeds
+	if _, appErr := searchService.RebuildIndex(); appErr != nil {
+		logger.Error(fmt.Sprintf("Error building the search index, it starts empty: %s", appErr.Message))
+	}
+}
+
+// backfillVerifiedUsers mark the existing accounts as verified at their creation date
+func backfillVerifiedUsers(dbClient *gorm.DB) {
+	result := dbClient.Unscoped().Model(&domain.User{}).
+		Where("verified_at IS NULL").
+		UpdateColumn("verified_at", gorm.Expr("created_at"))
+	if result.Error != nil {
+		logger.Fatal(fmt.Sprintf("Error while verifying existing users: %s", result.Error.Error()))
+	}
+	logger.Info(fmt.Sprintf("%d existing users marked as verified", result.RowsAffected))
+}
+
+// bootstrapAdmin create or promote the admin defined by ADMIN_EMAIL and ADMIN_PASSWORD
+func bootstrapAdmin(dbClient *gorm.DB) {
+	if os.Getenv("ADMIN_EMAIL") == "" {
+		return
+	}
+
+	request := requests.UserRequest{
+		Name:     os.Getenv("ADMIN_NAME"),
+		Email:    os.Getenv("ADMIN_EMAIL"),
+		Password: os.Getenv("ADMIN_PASSWORD"),
+	}
+	if request.Password == "" {
+		logger.Fatal("Environment variable ADMIN_PASSWORD not defined. Terminating application...")
+	}
+
+	userService := service.NewUserService(
+		repository.NewUserRepositoryGorm(dbClient),
+		mailer.NewMailer(),
+		repository.NewAuditRepositoryGorm(dbClient),
+	)
+	if appErr := userService.BootstrapAdmin(request); appErr != nil {
+		logger.Fatal(appErr.Message)
+	}
+}
@@ -20,7 +20,7 @@ import (
 // @securityDefinitions.apikey Bearer
 // @in header
 // @name Authorization
-// @description "Type 'Bearer TOKEN' to correctly set the API Key"
+// @description "Type 'Bearer TOKEN' to correctly set the API Key, catalog endpoints also accept 'ApiKey KEY'"
 
 // @host localhost:8080
 // @BasePath /
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/joho/godotenv"
+
+	"github.com/karlbehrensg/go-fiber-template/internal/http/responses"
+	"github.com/karlbehrensg/go-fiber-template/pkg/logger"
+)
+
+// search-rebuild asks a running API to rebuild its search index from the database, the index lives in the
+// memory of each instance so every instance must be called, e.g.
+//
+//	go run cmd/search-rebuild/main.go -url http://localhost:8080 -token $ADMIN_TOKEN
+func main() {
+	if os.Getenv("ENV") != "production" {
+		// the .env file is optional, the flags can be used instead
+		_ = godotenv.Load()
+	}
+
+	url := flag.String("url", defaultURL(), "base URL of the API instance, SEARCH_REBUILD_URL by default")
+	token := flag.String("token", os.Getenv("SEARCH_REBUILD_TOKEN"), "access token of an admin, SEARCH_REBUILD_TOKEN by default")
+	timeout := flag.Duration("timeout", time.Minute*5, "time to wait for the rebuild")
+	flag.Parse()
+
+	if *token == "" {
+		logger.Fatal("an admin access token is required, use -token or SEARCH_REBUILD_TOKEN")
+	}
+
+	response, err := rebuild(*url, *token, *timeout)
+	if err != nil {
+		logger.Fatal(fmt.Sprintf("Error rebuilding the search index of %s: %s", *url, err.Error()))
+	}
+	logger.Info(fmt.Sprintf("Search index of %s rebuilt with %d authors and %d books", *url, response.Authors, response.Books))
+}
+
+// rebuild call POST /admin/search/rebuild of the instance
+func rebuild(url string, token string, timeout time.Duration) (*responses.SearchRebuildResponse, error) {
+	request, err := http.NewRequest(http.MethodPost, url+"/admin/search/rebuild", nil)
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Authorization", "Bearer "+token)
+
+	client := &http.Client{Timeout: timeout}
+	res, err := client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		var body responses.ErrorResponse
+		if err = json.NewDecoder(res.Body).Decode(&body); err != nil || body.Message == "" {
+			return nil, fmt.Errorf("status %d", res.StatusCode)
+		}
+		return nil, fmt.Errorf("status %d: %s", res.StatusCode, body.Message)
+	}
+
+	var response responses.SearchRebuildResponse
+	if err = json.NewDecoder(res.Body).Decode(&response); err != nil {
+		return nil, err
+	}
+
+	return &response, nil
+}
+
+// defaultURL instance of the .env when SEARCH_REBUILD_URL is not defined
+func defaultURL() string {
+	if url := os.Getenv("SEARCH_REBUILD_URL"); url != "" {
+		return url
+	}
+
+	return "http://localhost:" + os.Getenv("APP_PORT")
+}
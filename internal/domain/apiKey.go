@@ -0,0 +1,71 @@
+package domain
+
+import (
+	"strings"
+	"time"
+
+	"github.com/karlbehrensg/go-fiber-template/internal/http/responses"
+	"github.com/karlbehrensg/go-fiber-template/pkg/errs"
+)
+
+const (
+	ScopeCatalogRead  = "catalog:read"
+	ScopeCatalogWrite = "catalog:write"
+)
+
+type APIKey struct {
+	ID         uint   `gorm:"id;primary_key"`
+	UserID     uint   `gorm:"user_id;not null;index"`
+	Name       string `gorm:"name;not null"`
+	Prefix     string `gorm:"prefix;not null"`
+	KeyHash    string `gorm:"key_hash;not null;unique"`
+	Scopes     string `gorm:"scopes"`
+	ExpiresAt  *time.Time
+	LastUsedAt *time.Time
+	RevokedAt  *time.Time
+	CreatedAt  time.Time
+}
+
+// APIKeyRepository port secondary
+//
+//go:generate mockgen -destination=../../mocks/domain/mockAPIKeyRepository.go -package=domain github.com/karlbehrensg/go-fiber-template/internal/domain APIKeyRepository
+type APIKeyRepository interface {
+	SaveAPIKey(*APIKey) *errs.AppError
+	FindAPIKeyByHash(string) (*APIKey, *errs.AppError)
+	FindAPIKeysByUser(userID uint) ([]APIKey, *errs.AppError)
+	RevokeAPIKey(userID uint, id uint) *errs.AppError
+	TouchAPIKey(id uint, usedAt time.Time) *errs.AppError
+}
+
+// IsValidScope validate if the scope exists
+func IsValidScope(scope string) bool {
+	return scope == ScopeCatalogRead || scope == ScopeCatalogWrite
+}
+
+// IsActive validate if the key is not revoked nor expired
+func (k *APIKey) IsActive() bool {
+	return k.RevokedAt == nil && (k.ExpiresAt == nil || time.Now().Before(*k.ExpiresAt))
+}
+
+// ScopeList scopes granted to the key, empty when the key is not restricted
+func (k *APIKey) ScopeList() []string {
+	if k.Scopes == "" {
+		return nil
+	}
+
+	return strings.Split(k.Scopes, ",")
+}
+
+// ToNewAPIKeyResponse convert APIKey struct to responses.APIKeyResponse struct
+func (k *APIKey) ToNewAPIKeyResponse() responses.APIKeyResponse {
+	return responses.APIKeyResponse{
+		Id:         k.ID,
+		Name:       k.Name,
+		Prefix:     k.Prefix,
+		Scopes:     k.ScopeList(),
+		ExpiresAt:  k.ExpiresAt,
+		LastUsedAt: k.LastUsedAt,
+		RevokedAt:  k.RevokedAt,
+		CreatedAt:  k.CreatedAt,
+	}
+}
@@ -0,0 +1,98 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/karlbehrensg/go-fiber-template/internal/http/responses"
+	"github.com/karlbehrensg/go-fiber-template/pkg/errs"
+)
+
+const (
+	AuditLogin       = "auth.login"
+	AuditLoginFailed = "auth.login_failed"
+	AuditSignup      = "auth.signup"
+	// AuditImpersonate an admin minted a token for another user, AuditImpersonatedRequest a request made with it
+	AuditImpersonate         = "auth.impersonate"
+	AuditImpersonatedRequest = "auth.impersonated_request"
+
+	AuditAuthorCreate = "author.create"
+	AuditAuthorUpdate = "author.update"
+	AuditAuthorDelete = "author.delete"
+	AuditBookCreate   = "book.create"
+	AuditBookUpdate   = "book.update"
+	AuditBookDelete   = "book.delete"
+
+	AuditUserRoleUpdate = "user.role_update"
+	AuditUserDisable    = "user.disable"
+	AuditUserEnable     = "user.enable"
+	AuditUserDelete     = "user.delete"
+	AuditUserRestore    = "user.restore"
+)
+
+// AuditEntry append-only record of who did what, it is never updated or deleted
+type AuditEntry struct {
+	ID         uint   `gorm:"id;primary_key"`
+	Action     string `gorm:"action;not null;index"`
+	ActorID    uint   `gorm:"actor_id;index"`
+	ActorEmail string `gorm:"actor_email"`
+	// ImpersonatorID admin acting as the actor, zero when the actor made the request
+	ImpersonatorID uint   `gorm:"impersonator_id;index"`
+	TargetType     string `gorm:"target_type;index"`
+	TargetID       string `gorm:"target_id;index"`
+	// Changes JSON object with the "before" and "after" value of each changed field
+	Changes   string    `gorm:"changes;type:text"`
+	Detail    string    `gorm:"detail"`
+	IP        string    `gorm:"ip"`
+	RequestID string    `gorm:"request_id;index"`
+	CreatedAt time.Time `gorm:"index"`
+}
+
+// Actor user and connection behind a change, UserID is zero for anonymous requests
+type Actor struct {
+	UserID uint
+	Email  string
+	// ImpersonatorID admin acting as the user, zero when the user made the request
+	ImpersonatorID uint
+	ClientInfo
+}
+
+// AuditFilter criteria to list audit entries, zero values are ignored
+type AuditFilter struct {
+	Action         string
+	ActorID        uint
+	ImpersonatorID uint
+	TargetType     string
+	TargetID       string
+	RequestID      string
+	From           *time.Time
+	To             *time.Time
+	Offset         int
+	Limit          int
+}
+
+// AuditRepository port secondary
+//
+//go:generate mockgen -destination=../../mocks/domain/mockAuditRepository.go -package=domain github.com/karlbehrensg/go-fiber-template/internal/domain AuditRepository
+type AuditRepository interface {
+	SaveAuditEntry(*AuditEntry) *errs.AppError
+	FindAuditEntries(AuditFilter) ([]AuditEntry, int64, *errs.AppError)
+}
+
+// ToNewAuditEntryResponse convert AuditEntry struct to responses.AuditEntryResponse struct
+func (e *AuditEntry) ToNewAuditEntryResponse() *responses.AuditEntryResponse {
+	return &responses.AuditEntryResponse{
+		Id:             e.ID,
+		Action:         e.Action,
+		ActorID:        e.ActorID,
+		ActorEmail:     e.ActorEmail,
+		ImpersonatorID: e.ImpersonatorID,
+		TargetType:     e.TargetType,
+		TargetID:       e.TargetID,
+		Changes:        json.RawMessage(e.Changes),
+		Detail:         e.Detail,
+		IP:             e.IP,
+		RequestID:      e.RequestID,
+		CreatedAt:      e.CreatedAt,
+	}
+}
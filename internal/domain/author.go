@@ -17,15 +17,33 @@ type Author struct {
 	Books     []Book
 }
 
+// policies for the books of a deleted author
+const (
+	// AuthorDeleteRestrict the author can not be deleted while it has books
+	AuthorDeleteRestrict = "restrict"
+	// AuthorDeleteCascade the books are deleted with the author
+	AuthorDeleteCascade = "cascade"
+	// AuthorDeleteReassign the books are moved to another author
+	AuthorDeleteReassign = "reassign"
+)
+
+// IsValidAuthorDeletePolicy validate the policy is one of the known ones
+func IsValidAuthorDeletePolicy(policy string) bool {
+	return policy == AuthorDeleteRestrict || policy == AuthorDeleteCascade || policy == AuthorDeleteReassign
+}
+
 // AuthorRepository port secondary
 //
 //go:generate mockgen -destination=../../mocks/domain/mockAuthorRepository.go -package=domain github.com/karlbehrensg/go-fiber-template/internal/domain AuthorRepository
 type AuthorRepository interface {
 	SaveAuthor(*Author) *errs.AppError
-	FindAllAuthor() ([]Author, *errs.AppError)
+	FindAllAuthor(ListQuery) ([]Author, int64, *errs.AppError)
 	FindAuthorById(uint) (*Author, *errs.AppError)
+	FindAuthorByIdWithBooks(uint) (*Author, *errs.AppError)
 	UpdateAuthor(*Author) (*Author, *errs.AppError)
-	DeleteAuthor(id uint) *errs.AppError
+	// DeleteAuthor delete the author applying the policy to its books, reassignTo is only used by reassign,
+	// returns the books deleted or reassigned as they were before
+	DeleteAuthor(id uint, policy string, reassignTo uint) ([]Book, *errs.AppError)
 }
 
 // ToNewAuthorResponse convert Author struct to responses.AuthorResponse struct
@@ -36,3 +54,26 @@ func (d *Author) ToNewAuthorResponse() *responses.AuthorResponse {
 		FullName: d.FullName,
 	}
 }
+
+// ToNewAuthorResponseWithBooks convert Author struct to responses.AuthorResponse struct, with the preloaded books
+func (d *Author) ToNewAuthorResponseWithBooks() *responses.AuthorResponse {
+	response := d.ToNewAuthorResponse()
+	response.Books = make([]responses.BookResponse, 0, len(d.Books))
+	for _, book := range d.Books {
+		b := book.ToNewBookResponse()
+		b.AuthorName = d.FullName
+		response.Books = append(response.Books, *b)
+	}
+
+	return response
+}
+
+// ListValue value of the column used by the keyset cursors of the list
+func (d *Author) ListValue(column string) interface{} {
+	switch column {
+	case "full_name":
+		return d.FullName
+	}
+
+	return d.ID
+}
@@ -1,6 +1,8 @@
 package domain
 
 import (
+	"regexp"
+	"strconv"
 	"time"
 
 	"github.com/karlbehrensg/go-fiber-template/internal/http/responses"
@@ -13,16 +15,24 @@ type Book struct {
 	Title           string `gorm:"title;not null"`
 	PublicationYear string `gorm:"publication_year"`
 	AuthorID        uint   `gorm:"author_id"`
-	CreatedAt       time.Time
-	UpdatedAt       time.Time
-	DeletedAt       gorm.DeletedAt `gorm:"index"`
+	// ISBN13 normalized ISBN, unique between the books not deleted
+	ISBN13 *string `gorm:"isbn13;index:idx_books_isbn13,unique,where:deleted_at IS NULL"`
+	// ISBN10 only books with ISBN-13 prefix 978 have one
+	ISBN10    *string `gorm:"isbn10"`
+	Author    *Author
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt gorm.DeletedAt `gorm:"index"`
 }
 
 // BookRepository port secondary
+//
+//go:generate mockgen -destination=../../mocks/domain/mockBookRepository.go -package=domain github.com/karlbehrensg/go-fiber-template/internal/domain BookRepository
 type BookRepository interface {
 	SaveBook(*Book) *errs.AppError
-	FindAllBook() ([]Book, *errs.AppError)
+	FindAllBook(ListQuery) ([]Book, int64, *errs.AppError)
 	FindBookById(uint) (*Book, *errs.AppError)
+	FindBookByISBN13(string) (*Book, *errs.AppError)
 	UpdateBook(*Book) (*Book, *errs.AppError)
 	DeleteBook(id uint) *errs.AppError
 }
@@ -30,10 +40,48 @@ type BookRepository interface {
 // ToNewBookResponse convert Book struct to responses.BookResponse struct
 func (d *Book) ToNewBookResponse() *responses.BookResponse {
 
-	return &responses.BookResponse{
+	response := &responses.BookResponse{
 		Id:              d.ID,
 		Title:           d.Title,
 		PublicationYear: d.PublicationYear,
 		AuthorID:        d.AuthorID,
 	}
+	if d.Author != nil {
+		response.AuthorName = d.Author.FullName
+	}
+	if d.ISBN13 != nil {
+		response.ISBN13 = *d.ISBN13
+	}
+	if d.ISBN10 != nil {
+		response.ISBN10 = *d.ISBN10
+	}
+
+	return response
+}
+
+// ListValue value of the column used by the keyset cursors of the list
+func (d *Book) ListValue(column string) interface{} {
+	switch column {
+	case "title":
+		return d.Title
+	case "publication_year":
+		return d.publicationYearNumber()
+	case "author_id":
+		return d.AuthorID
+	}
+
+	return d.ID
+}
+
+// integerText whole numbers cast to integer by the list queries
+var integerText = regexp.MustCompile("^[0-9]{1,9}$")
+
+// publicationYearNumber year as it is sorted by the list, a year that is not a whole number is 0
+func (d *Book) publicationYearNumber() uint64 {
+	if !integerText.MatchString(d.PublicationYear) {
+		return 0
+	}
+	year, _ := strconv.ParseUint(d.PublicationYear, 10, 64)
+
+	return year
 }
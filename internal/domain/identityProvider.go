@@ -0,0 +1,23 @@
+package domain
+
+import (
+	"github.com/karlbehrensg/go-fiber-template/pkg/errs"
+)
+
+// IdentityClaims identity of the user verified by the external provider
+type IdentityClaims struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+	// Roles raw values of the claim mapped to local roles
+	Roles []string
+}
+
+// IdentityProvider port secondary
+//
+//go:generate mockgen -destination=../../mocks/domain/mockIdentityProvider.go -package=domain github.com/karlbehrensg/go-fiber-template/internal/domain IdentityProvider
+type IdentityProvider interface {
+	AuthCodeURL(state string, nonce string, codeChallenge string) (string, *errs.AppError)
+	Exchange(code string, codeVerifier string, nonce string) (*IdentityClaims, *errs.AppError)
+}
@@ -0,0 +1,41 @@
+package domain
+
+const (
+	ListOperatorEqual        = "eq"
+	ListOperatorGreaterEqual = "gte"
+	ListOperatorLessEqual    = "lte"
+	ListOperatorContains     = "contains"
+)
+
+// ListQuery page of a list endpoint, the columns were validated by the service against the fields of the resource
+type ListQuery struct {
+	Filters []ListFilter
+	// Sort ends with the ID so the order, and the keyset cursors, are always unique
+	Sort  []ListSort
+	Limit int
+	// Offset is used with page numbers, After with keyset cursors
+	Offset int
+	After  *ListCursor
+	// Include associations loaded with the rows, each one with a single query for the whole page
+	Include []string
+}
+
+// ListFilter Integer is set on text columns holding whole numbers, they are compared as integers
+type ListFilter struct {
+	Column   string
+	Operator string
+	Value    interface{}
+	Integer  bool
+}
+
+// ListSort Integer is set on text columns holding whole numbers, they are sorted as integers
+type ListSort struct {
+	Column  string
+	Desc    bool
+	Integer bool
+}
+
+// ListCursor row after which the page starts, Values holds the value of each sort column of the row
+type ListCursor struct {
+	Values []interface{}
+}
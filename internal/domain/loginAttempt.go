@@ -0,0 +1,31 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/karlbehrensg/go-fiber-template/pkg/errs"
+)
+
+type LoginAttempt struct {
+	Key         string    `gorm:"key;primary_key"`
+	Failures    int       `gorm:"failures;not null;default:0"`
+	LastFailure time.Time `gorm:"last_failure;not null"`
+	LockedUntil *time.Time
+	UpdatedAt   time.Time
+}
+
+// LoginAttemptRepository port secondary
+//
+//go:generate mockgen -destination=../../mocks/domain/mockLoginAttemptRepository.go -package=domain github.com/karlbehrensg/go-fiber-template/internal/domain LoginAttemptRepository
+type LoginAttemptRepository interface {
+	FindLoginAttempt(key string) (*LoginAttempt, *errs.AppError)
+	// RegisterFailedLogin increments the failures, they start again from one when the last failure is older than window
+	RegisterFailedLogin(key string, window time.Duration) (*LoginAttempt, *errs.AppError)
+	LockLoginAttempt(key string, until time.Time) *errs.AppError
+	ResetLoginAttempts(key string) *errs.AppError
+}
+
+// IsLocked validate if the key is temporarily locked
+func (a *LoginAttempt) IsLocked() bool {
+	return a.LockedUntil != nil && time.Now().Before(*a.LockedUntil)
+}
@@ -0,0 +1,30 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/karlbehrensg/go-fiber-template/pkg/errs"
+)
+
+type MagicLinkToken struct {
+	ID        uint      `gorm:"id;primary_key"`
+	UserID    uint      `gorm:"user_id;not null;index"`
+	TokenHash string    `gorm:"token_hash;not null;unique"`
+	ExpiresAt time.Time `gorm:"expires_at;not null"`
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+// MagicLinkTokenRepository port secondary
+//
+//go:generate mockgen -destination=../../mocks/domain/mockMagicLinkTokenRepository.go -package=domain github.com/karlbehrensg/go-fiber-template/internal/domain MagicLinkTokenRepository
+type MagicLinkTokenRepository interface {
+	SaveMagicLinkToken(*MagicLinkToken) *errs.AppError
+	FindMagicLinkTokenByHash(string) (*MagicLinkToken, *errs.AppError)
+	ConsumeMagicLinkToken(id uint) *errs.AppError
+}
+
+// IsExpired validate if the magic link token is expired
+func (t *MagicLinkToken) IsExpired() bool {
+	return time.Now().After(t.ExpiresAt)
+}
@@ -0,0 +1,16 @@
+package domain
+
+import "github.com/karlbehrensg/go-fiber-template/pkg/errs"
+
+type Mail struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer port secondary
+//
+//go:generate mockgen -destination=../../mocks/domain/mockMailer.go -package=domain github.com/karlbehrensg/go-fiber-template/internal/domain Mailer
+type Mailer interface {
+	Send(Mail) *errs.AppError
+}
@@ -0,0 +1,31 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/karlbehrensg/go-fiber-template/pkg/errs"
+)
+
+// MFAChallenge login waiting for the second factor, it can only be completed once
+type MFAChallenge struct {
+	ID        uint      `gorm:"id;primary_key"`
+	UserID    uint      `gorm:"user_id;not null;index"`
+	NonceHash string    `gorm:"nonce_hash;not null;unique"`
+	ExpiresAt time.Time `gorm:"expires_at;not null"`
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+// MFAChallengeRepository port secondary
+//
+//go:generate mockgen -destination=../../mocks/domain/mockMFAChallengeRepository.go -package=domain github.com/karlbehrensg/go-fiber-template/internal/domain MFAChallengeRepository
+type MFAChallengeRepository interface {
+	SaveMFAChallenge(*MFAChallenge) *errs.AppError
+	FindMFAChallengeByHash(string) (*MFAChallenge, *errs.AppError)
+	ConsumeMFAChallenge(id uint) *errs.AppError
+}
+
+// IsExpired validate if the MFA challenge is expired
+func (c *MFAChallenge) IsExpired() bool {
+	return time.Now().After(c.ExpiresAt)
+}
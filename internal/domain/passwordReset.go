@@ -0,0 +1,31 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/karlbehrensg/go-fiber-template/pkg/errs"
+)
+
+type PasswordResetToken struct {
+	ID        uint      `gorm:"id;primary_key"`
+	UserID    uint      `gorm:"user_id;not null;index"`
+	TokenHash string    `gorm:"token_hash;not null;unique"`
+	ExpiresAt time.Time `gorm:"expires_at;not null"`
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+// PasswordResetTokenRepository port secondary
+//
+//go:generate mockgen -destination=../../mocks/domain/mockPasswordResetTokenRepository.go -package=domain github.com/karlbehrensg/go-fiber-template/internal/domain PasswordResetTokenRepository
+type PasswordResetTokenRepository interface {
+	SavePasswordResetToken(*PasswordResetToken) *errs.AppError
+	FindPasswordResetTokenByHash(string) (*PasswordResetToken, *errs.AppError)
+	// ConsumePasswordResetToken mark the token as used and save the new password hash in the same transaction
+	ConsumePasswordResetToken(token *PasswordResetToken, passwordHash string) *errs.AppError
+}
+
+// IsExpired validate if the reset token is expired
+func (t *PasswordResetToken) IsExpired() bool {
+	return time.Now().After(t.ExpiresAt)
+}
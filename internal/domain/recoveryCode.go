@@ -0,0 +1,24 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/karlbehrensg/go-fiber-template/pkg/errs"
+)
+
+type RecoveryCode struct {
+	ID        uint   `gorm:"id;primary_key"`
+	UserID    uint   `gorm:"user_id;not null;index"`
+	CodeHash  string `gorm:"code_hash;not null"`
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+// RecoveryCodeRepository port secondary
+//
+//go:generate mockgen -destination=../../mocks/domain/mockRecoveryCodeRepository.go -package=domain github.com/karlbehrensg/go-fiber-template/internal/domain RecoveryCodeRepository
+type RecoveryCodeRepository interface {
+	ReplaceRecoveryCodes(userID uint, codes []*RecoveryCode) *errs.AppError
+	ConsumeRecoveryCode(userID uint, hash string) *errs.AppError
+	DeleteRecoveryCodes(userID uint) *errs.AppError
+}
@@ -0,0 +1,35 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/karlbehrensg/go-fiber-template/pkg/errs"
+)
+
+type RefreshToken struct {
+	ID        uint      `gorm:"id;primary_key"`
+	UserID    uint      `gorm:"user_id;not null;index"`
+	FamilyID  string    `gorm:"family_id;not null;index"`
+	TokenHash string    `gorm:"token_hash;not null;unique"`
+	ExpiresAt time.Time `gorm:"expires_at;not null"`
+	RotatedAt *time.Time
+	RevokedAt *time.Time
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// RefreshTokenRepository port secondary
+//
+//go:generate mockgen -destination=../../mocks/domain/mockRefreshTokenRepository.go -package=domain github.com/karlbehrensg/go-fiber-template/internal/domain RefreshTokenRepository
+type RefreshTokenRepository interface {
+	SaveRefreshToken(*RefreshToken) *errs.AppError
+	FindRefreshTokenByHash(string) (*RefreshToken, *errs.AppError)
+	RotateRefreshToken(old *RefreshToken, new *RefreshToken) *errs.AppError
+	RevokeRefreshTokenFamily(familyID string) *errs.AppError
+	RevokeUserRefreshTokens(userID uint) *errs.AppError
+}
+
+// IsExpired validate if the refresh token is expired
+func (t *RefreshToken) IsExpired() bool {
+	return time.Now().After(t.ExpiresAt)
+}
@@ -0,0 +1,22 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/karlbehrensg/go-fiber-template/pkg/errs"
+)
+
+type RevokedToken struct {
+	JTI       string    `gorm:"jti;primary_key"`
+	ExpiresAt time.Time `gorm:"expires_at;not null;index"`
+	CreatedAt time.Time
+}
+
+// RevokedTokenRepository port secondary
+//
+//go:generate mockgen -destination=../../mocks/domain/mockRevokedTokenRepository.go -package=domain github.com/karlbehrensg/go-fiber-template/internal/domain RevokedTokenRepository
+type RevokedTokenRepository interface {
+	SaveRevokedToken(*RevokedToken) *errs.AppError
+	IsTokenRevoked(jti string) (bool, *errs.AppError)
+	DeleteExpiredRevokedTokens() *errs.AppError
+}
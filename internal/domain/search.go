@@ -0,0 +1,60 @@
+package domain
+
+import "github.com/karlbehrensg/go-fiber-template/pkg/errs"
+
+// kinds of documents in the search index
+const (
+	SearchKindBook   = "book"
+	SearchKindAuthor = "author"
+)
+
+// SearchDocument text of a book or an author indexed for search, by field name
+type SearchDocument struct {
+	Kind   string
+	ID     uint
+	Fields map[string]string
+}
+
+// SearchQuery text typed by the user, Kinds restricts the documents returned when not empty
+type SearchQuery struct {
+	Text  string
+	Kinds []string
+	Limit int
+}
+
+// SearchHit document matching the query, highlights are the fields with the matches wrapped in <em>
+type SearchHit struct {
+	Kind       string
+	ID         uint
+	Score      float64
+	Fields     map[string]string
+	Highlights map[string]string
+}
+
+// SearchIndex port secondary
+//
+//go:generate mockgen -destination=../../mocks/domain/mockSearchIndex.go -package=domain github.com/karlbehrensg/go-fiber-template/internal/domain SearchIndex
+type SearchIndex interface {
+	IndexDocument(SearchDocument) *errs.AppError
+	RemoveDocument(kind string, id uint) *errs.AppError
+	// RebuildDocuments swap the whole content of the index with the documents returned by load, the documents
+	// indexed or removed while load runs are applied again after the swap so they are not lost
+	RebuildDocuments(load func() ([]SearchDocument, *errs.AppError)) *errs.AppError
+	// Search returns the best hits and the total of matches
+	Search(SearchQuery) ([]SearchHit, int, *errs.AppError)
+}
+
+// ToSearchDocument document of the book, the author must be loaded to search by its name
+func (d *Book) ToSearchDocument() SearchDocument {
+	fields := map[string]string{"title": d.Title}
+	if d.Author != nil {
+		fields["author_name"] = d.Author.FullName
+	}
+
+	return SearchDocument{Kind: SearchKindBook, ID: d.ID, Fields: fields}
+}
+
+// ToSearchDocument document of the author
+func (d *Author) ToSearchDocument() SearchDocument {
+	return SearchDocument{Kind: SearchKindAuthor, ID: d.ID, Fields: map[string]string{"full_name": d.FullName}}
+}
@@ -0,0 +1,58 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/karlbehrensg/go-fiber-template/internal/http/responses"
+	"github.com/karlbehrensg/go-fiber-template/pkg/errs"
+)
+
+// Session one login of the user, it owns a refresh token family
+type Session struct {
+	ID         uint      `gorm:"id;primary_key"`
+	UserID     uint      `gorm:"user_id;not null;index"`
+	FamilyID   string    `gorm:"family_id;not null;unique"`
+	UserAgent  string    `gorm:"user_agent"`
+	IP         string    `gorm:"ip"`
+	LastSeenAt time.Time `gorm:"last_seen_at;not null"`
+	RevokedAt  *time.Time
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// ClientInfo connection data of the client that opens or uses a session
+type ClientInfo struct {
+	IP        string
+	UserAgent string
+	RequestID string
+}
+
+// SessionRepository port secondary
+//
+//go:generate mockgen -destination=../../mocks/domain/mockSessionRepository.go -package=domain github.com/karlbehrensg/go-fiber-template/internal/domain SessionRepository
+type SessionRepository interface {
+	SaveSession(*Session) *errs.AppError
+	FindSessionById(uint) (*Session, *errs.AppError)
+	FindSessionByFamily(familyID string) (*Session, *errs.AppError)
+	FindActiveSessionsByUser(userID uint) ([]Session, *errs.AppError)
+	TouchSession(id uint, ip string, lastSeenAt time.Time) *errs.AppError
+	RevokeSession(userID uint, id uint) *errs.AppError
+	RevokeUserSessions(userID uint, exceptID uint) *errs.AppError
+}
+
+// IsActive validate if the session was not signed out
+func (s *Session) IsActive() bool {
+	return s.RevokedAt == nil
+}
+
+// ToNewSessionResponse convert Session struct to responses.SessionResponse struct
+func (s *Session) ToNewSessionResponse(current bool) *responses.SessionResponse {
+	return &responses.SessionResponse{
+		Id:         s.ID,
+		UserAgent:  s.UserAgent,
+		IP:         s.IP,
+		Current:    current,
+		LastSeenAt: s.LastSeenAt,
+		CreatedAt:  s.CreatedAt,
+	}
+}
@@ -3,53 +3,164 @@ package domain
 import (
 	"time"
 
+	"github.com/karlbehrensg/go-fiber-template/internal/http/responses"
 	"github.com/karlbehrensg/go-fiber-template/pkg/errs"
 	"github.com/karlbehrensg/go-fiber-template/pkg/logger"
 	"github.com/karlbehrensg/go-fiber-template/pkg/utils"
-	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
 )
 
+const (
+	RoleReader    = "reader"
+	RoleLibrarian = "librarian"
+	RoleAdmin     = "admin"
+)
+
+const (
+	UserStatusActive   = "active"
+	UserStatusDisabled = "disabled"
+	UserStatusDeleted  = "deleted"
+)
+
 type User struct {
-	ID        uint   `gorm:"id;primary_key"`
-	Name      string `gorm:"full_name"`
-	Email     string `gorm:"email;not null;unique"`
-	Password  string `gorm:"password;not null"`
-	CreatedAt time.Time
-	UpdatedAt time.Time
-	DeletedAt gorm.DeletedAt `gorm:"index"`
+	ID                 uint   `gorm:"id;primary_key"`
+	Name               string `gorm:"full_name"`
+	Email              string `gorm:"email;not null;unique"`
+	Password           string `gorm:"password;not null"`
+	Role               string `gorm:"role;not null;default:reader"`
+	VerifiedAt         *time.Time
+	VerificationSentAt *time.Time
+	TOTPSecret         string `gorm:"totp_secret"`
+	TOTPLastStep       int64  `gorm:"totp_last_step"`
+	MFAEnabledAt       *time.Time
+	OIDCSubject        *string `gorm:"oidc_subject;uniqueIndex"`
+	OIDCProvisioned    bool    `gorm:"oidc_provisioned;not null;default:false"`
+	TokenVersion       uint    `gorm:"token_version;not null;default:0"`
+	DisabledAt         *time.Time
+	CreatedAt          time.Time
+	UpdatedAt          time.Time
+	DeletedAt          gorm.DeletedAt `gorm:"index"`
 }
 
 // UserRepository port secondary
+//
+//go:generate mockgen -destination=../../mocks/domain/mockUserRepository.go -package=domain github.com/karlbehrensg/go-fiber-template/internal/domain UserRepository
 type UserRepository interface {
 	SaveUser(*User) *errs.AppError
 	FindUserByEmail(string) (*User, *errs.AppError)
+	FindUserById(uint) (*User, *errs.AppError)
+	FindUserByOIDCSubject(string) (*User, *errs.AppError)
+	UpdateUser(*User) (*User, *errs.AppError)
+	UpdateUserColumns(uint, map[string]interface{}) *errs.AppError
+	UpdatePassword(uint, string) *errs.AppError
+	FindUsers(UserFilter) ([]User, int64, *errs.AppError)
+	FindUserByIdWithDeleted(uint) (*User, *errs.AppError)
+	DeleteUser(uint) *errs.AppError
+	RestoreUser(uint) *errs.AppError
+}
+
+// UserFilter criteria to list users, Search matches the email or the name
+type UserFilter struct {
+	Search string
+	Role   string
+	Status string
+	Offset int
+	Limit  int
+}
+
+// IsValidRole validate if the role exists
+func IsValidRole(role string) bool {
+	return role == RoleReader || role == RoleLibrarian || role == RoleAdmin
+}
+
+// IsVerified validate if the user confirmed the email address
+func (u *User) IsVerified() bool {
+	return u.VerifiedAt != nil
+}
+
+// IsDisabled validate if an admin disabled the account
+func (u *User) IsDisabled() bool {
+	return u.DisabledAt != nil
+}
+
+// IsMFAEnabled validate if the user confirmed the TOTP enrollment
+func (u *User) IsMFAEnabled() bool {
+	return u.MFAEnabledAt != nil
 }
 
-// HashPassword encrypt password
+// HashPassword encrypt password with the configured algorithm
 func (u *User) HashPassword() error {
-	hash, err := bcrypt.GenerateFromPassword([]byte(u.Password), 14)
+	hash, err := utils.GetPasswordHasher().Hash(u.Password)
 	if err != nil {
 		return err
 	}
-	u.Password = string(hash)
+	u.Password = hash
 	return nil
 }
 
 // ComparePassword validate password
 func (u *User) ComparePassword(password string) *errs.AppError {
-	if err := bcrypt.CompareHashAndPassword([]byte(u.Password), []byte(password)); err != nil {
+	if err := utils.ComparePassword(u.Password, password); err != nil {
 		logger.Error(err.Error())
 		return errs.NewAuthenticationError("Invalid credentials")
 	}
 	return nil
 }
 
+// RehashPassword hash again the already validated password when the algorithm or its parameters changed,
+// returns true when the new hash must be saved
+func (u *User) RehashPassword(password string) (bool, error) {
+	hasher := utils.GetPasswordHasher()
+	if !hasher.NeedsRehash(u.Password) {
+		return false, nil
+	}
+
+	hash, err := hasher.Hash(password)
+	if err != nil {
+		return false, err
+	}
+	u.Password = hash
+	return true, nil
+}
+
 // ToNewUtilsJWTClaims convert User struct to utils.JWTClaims struct
 func (u *User) ToNewUtilsJWTClaims() *utils.JWTClaims {
 	return &utils.JWTClaims{
-		UserID: u.ID,
-		Email:  u.Email,
-		Name:   u.Name,
+		UserID:       u.ID,
+		Email:        u.Email,
+		Name:         u.Name,
+		Role:         u.Role,
+		TokenVersion: u.TokenVersion,
+	}
+}
+
+// ToNewProfileResponse convert User struct to responses.ProfileResponse struct
+func (u *User) ToNewProfileResponse() *responses.ProfileResponse {
+	return &responses.ProfileResponse{
+		Id:         u.ID,
+		Name:       u.Name,
+		Email:      u.Email,
+		Role:       u.Role,
+		MFAEnabled: u.IsMFAEnabled(),
+		CreatedAt:  u.CreatedAt,
 	}
 }
+
+// ToNewAdminUserResponse convert User struct to responses.AdminUserResponse struct
+func (u *User) ToNewAdminUserResponse() *responses.AdminUserResponse {
+	response := &responses.AdminUserResponse{
+		Id:         u.ID,
+		Name:       u.Name,
+		Email:      u.Email,
+		Role:       u.Role,
+		Verified:   u.IsVerified(),
+		MFAEnabled: u.IsMFAEnabled(),
+		DisabledAt: u.DisabledAt,
+		CreatedAt:  u.CreatedAt,
+	}
+	if u.DeletedAt.Valid {
+		response.DeletedAt = &u.DeletedAt.Time
+	}
+
+	return response
+}
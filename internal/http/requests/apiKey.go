@@ -0,0 +1,8 @@
+package requests
+
+type APIKeyRequest struct {
+	Name string `form:"name" validate:"required,max=100" example:"import script"`
+	// Scopes empty grants every scope of the user
+	Scopes        []string `form:"scopes" validate:"omitempty,dive,oneof=catalog:read catalog:write" example:"catalog:read"`
+	ExpiresInDays int      `form:"expires_in_days" validate:"omitempty,min=1,max=365" example:"90"`
+}
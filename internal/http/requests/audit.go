@@ -0,0 +1,16 @@
+package requests
+
+type AuditListRequest struct {
+	Action  string `query:"action" example:"author.update"`
+	ActorID uint   `query:"actor_id" example:"1"`
+	// ImpersonatorID only entries made by the admin while impersonating
+	ImpersonatorID uint   `query:"impersonator_id" example:"2"`
+	TargetType     string `query:"target_type" example:"author"`
+	TargetID       string `query:"target_id" example:"30"`
+	RequestID      string `query:"request_id" example:"0f8fad5b-d9cb-469f-a165-70867728950e"`
+	// From and To are RFC 3339 dates, To is exclusive
+	From  string `query:"from" example:"2022-11-01T00:00:00Z"`
+	To    string `query:"to" example:"2022-12-01T00:00:00Z"`
+	Page  int    `query:"page" validate:"omitempty,min=1" example:"1"`
+	Limit int    `query:"limit" validate:"omitempty,min=1,max=100" example:"20"`
+}
@@ -2,5 +2,76 @@ package requests
 
 type LoginRequest struct {
 	Email    string `form:"email" validate:"required,email" example:"edwyn.rangel.externo@zeleri.com"`
-	Password string `form:"password" validate:"required,min=7" example:"1234567"`
+	Password string `form:"password" validate:"required" example:"1234567"`
+	// IP, UserAgent and RequestID are filled from the connection, never from the body
+	IP        string `form:"-" json:"-" swaggerignore:"true"`
+	UserAgent string `form:"-" json:"-" swaggerignore:"true"`
+	RequestID string `form:"-" json:"-" swaggerignore:"true"`
+}
+
+type RefreshTokenRequest struct {
+	RefreshToken string `form:"refresh_token" validate:"required" example:"3q2-7wAbC..."`
+	// IP is filled from the connection, never from the body
+	IP string `form:"-" json:"-" swaggerignore:"true"`
+}
+
+type LogoutRequest struct {
+	RefreshToken string `form:"refresh_token" example:"3q2-7wAbC..."`
+}
+
+type ForgotPasswordRequest struct {
+	Email string `form:"email" validate:"required,email" example:"edwyn.rangel.externo@zeleri.com"`
+}
+
+type ResetPasswordRequest struct {
+	Token                string `form:"token" validate:"required" example:"Zm9vYmFy..."`
+	Password             string `form:"password" validate:"required" example:"1234567"`
+	PasswordConfirmation string `form:"password_confirmation" validate:"required,eqfield=Password" example:"1234567"`
+}
+
+type ChangePasswordRequest struct {
+	CurrentPassword      string `form:"current_password" validate:"required" example:"1234567"`
+	Password             string `form:"password" validate:"required" example:"7654321"`
+	PasswordConfirmation string `form:"password_confirmation" validate:"required,eqfield=Password" example:"7654321"`
+}
+
+type ResendVerificationRequest struct {
+	Email string `form:"email" validate:"required,email" example:"edwyn.rangel.externo@zeleri.com"`
+}
+
+type MagicLinkRequest struct {
+	Email string `form:"email" validate:"required,email" example:"edwyn.rangel.externo@zeleri.com"`
+}
+
+type MagicLinkLoginRequest struct {
+	Token string `query:"token" validate:"required" example:"Zm9vYmFy..."`
+	// IP, UserAgent and RequestID are filled from the connection, never from the query
+	IP        string `query:"-" json:"-" swaggerignore:"true"`
+	UserAgent string `query:"-" json:"-" swaggerignore:"true"`
+	RequestID string `query:"-" json:"-" swaggerignore:"true"`
+}
+
+type MFACodeRequest struct {
+	Code string `form:"code" validate:"required" example:"123456"`
+}
+
+type MFAVerifyRequest struct {
+	MFAToken string `form:"mfa_token" validate:"required" example:"Zm9vYmFy..."`
+	// Code TOTP code or one of the recovery codes
+	Code string `form:"code" validate:"required" example:"123456"`
+	// IP, UserAgent and RequestID are filled from the connection, never from the body
+	IP        string `form:"-" json:"-" swaggerignore:"true"`
+	UserAgent string `form:"-" json:"-" swaggerignore:"true"`
+	RequestID string `form:"-" json:"-" swaggerignore:"true"`
+}
+
+type OIDCCallbackRequest struct {
+	Code  string `query:"code"`
+	State string `query:"state"`
+	Error string `query:"error"`
+	// Flow is read from the cookie set by /auth/oidc/login, IP, UserAgent and RequestID from the connection
+	Flow      string `query:"-" json:"-" swaggerignore:"true"`
+	IP        string `query:"-" json:"-" swaggerignore:"true"`
+	UserAgent string `query:"-" json:"-" swaggerignore:"true"`
+	RequestID string `query:"-" json:"-" swaggerignore:"true"`
 }
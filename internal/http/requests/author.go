@@ -4,3 +4,12 @@ type AuthorRequest struct {
 	Id       uint   `json:"id,omitempty"`
 	FullName string `json:"full_name" validate:"required,min=3" example:"J. J. Benítez"`
 }
+
+// DeleteAuthorRequest query string of DELETE /author/:id
+type DeleteAuthorRequest struct {
+	Id uint `query:"-" swaggerignore:"true"`
+	// OnBooks policy for the books of the author, AUTHOR_DELETE_POLICY when empty
+	OnBooks string `query:"on_books" validate:"omitempty,oneof=restrict cascade reassign" example:"reassign"`
+	// ReassignTo author receiving the books, required by reassign
+	ReassignTo uint `query:"reassign_to" example:"31"`
+}
@@ -5,4 +5,7 @@ type BookRequest struct {
 	Title           string `json:"title" validate:"required,min=3" example:"Caballo de Troya 1"`
 	AuthorID        uint   `json:"author_id" validate:"required" example:"30"`
 	PublicationYear string `json:"publication_year" validate:"required" example:"1984"`
+	// ISBN ISBN-10 or ISBN-13, hyphens are allowed, it is stored as ISBN-13.
+	// On update an absent isbn keeps the stored one and an empty isbn clears it
+	ISBN *string `json:"isbn,omitempty" validate:"omitempty,isbn" example:"0-306-40615-2"`
 }
@@ -0,0 +1,17 @@
+package requests
+
+// ListRequest query string of the list endpoints, a page is selected with page or with cursor
+type ListRequest struct {
+	Page   int    `query:"page" validate:"omitempty,min=1" example:"2"`
+	Limit  int    `query:"limit" validate:"omitempty,min=1,max=100" example:"20"`
+	Cursor string `query:"cursor" validate:"excluded_with=Page" example:"eyJzIjoiaWQiLCJ2IjpbMzBdfQ"`
+	// Sort comma separated fields, descending when prefixed with "-"
+	Sort string `query:"sort" example:"-publication_year,title"`
+	// Include comma separated associations returned with each row
+	Include string `query:"include" example:"books"`
+	// Filters the other parameters of the query string, e.g. "publication_year[gte]=1990" is stored as
+	// {"publication_year[gte]": "1990"}
+	Filters map[string]string `query:"-" swaggerignore:"true"`
+	// Path of the endpoint used to build the next and prev links
+	Path string `query:"-" swaggerignore:"true"`
+}
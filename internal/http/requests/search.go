@@ -0,0 +1,8 @@
+package requests
+
+type SearchRequest struct {
+	Q string `query:"q" validate:"required,max=200" example:"benitez caballo"`
+	// Type only hits of this type, books and authors when empty
+	Type  string `query:"type" validate:"omitempty,oneof=book author" example:"book"`
+	Limit int    `query:"limit" validate:"omitempty,min=1,max=50" example:"20"`
+}
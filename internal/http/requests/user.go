@@ -4,6 +4,23 @@ type UserRequest struct {
 	Name                 string `form:"full_name" example:"Edwyn Rangel"`
 	Email                string `form:"email" validate:"required,email" example:"edwyn.rangel.externo@zeleri.com"`
 	EmailConfirmation    string `form:"email_confirmation" validate:"required,email,eqfield=Email" example:"edwyn.rangel.externo@zeleri.com"`
-	Password             string `form:"password" validate:"required,min=7" example:"1234567"`
-	PasswordConfirmation string `form:"password_confirmation" validate:"required,min=7,eqfield=Password" example:"1234567"`
+	Password             string `form:"password" validate:"required" example:"1234567"`
+	PasswordConfirmation string `form:"password_confirmation" validate:"required,eqfield=Password" example:"1234567"`
+}
+
+type ProfileRequest struct {
+	Name  string `json:"full_name" form:"full_name" validate:"omitempty,min=3" example:"Edwyn Rangel"`
+	Email string `json:"email" form:"email" validate:"omitempty,email" example:"edwyn.rangel.externo@zeleri.com"`
+}
+
+type UserListRequest struct {
+	Search string `query:"search" example:"edwyn"`
+	Role   string `query:"role" validate:"omitempty,oneof=reader librarian admin" example:"reader"`
+	Status string `query:"status" validate:"omitempty,oneof=active disabled deleted" example:"active"`
+	Page   int    `query:"page" validate:"omitempty,min=1" example:"1"`
+	Limit  int    `query:"limit" validate:"omitempty,min=1,max=100" example:"20"`
+}
+
+type UserRoleRequest struct {
+	Role string `form:"role" validate:"required,oneof=reader librarian admin" example:"librarian"`
 }
@@ -0,0 +1,20 @@
+package responses
+
+import "time"
+
+type APIKeyResponse struct {
+	Id         uint       `json:"id" example:"1"`
+	Name       string     `json:"name" example:"import script"`
+	Prefix     string     `json:"prefix" example:"lk_3q2Aw7bC"`
+	Scopes     []string   `json:"scopes" example:"catalog:read"`
+	ExpiresAt  *time.Time `json:"expires_at" example:"2023-11-01T10:00:00Z"`
+	LastUsedAt *time.Time `json:"last_used_at" example:"2022-11-01T10:00:00Z"`
+	RevokedAt  *time.Time `json:"revoked_at" example:"2022-11-01T10:00:00Z"`
+	CreatedAt  time.Time  `json:"created_at" example:"2022-11-01T10:00:00Z"`
+}
+
+type APIKeyCreatedResponse struct {
+	APIKeyResponse
+	// Key is only returned once, on creation
+	Key string `json:"key" example:"lk_3q2Aw7bC..."`
+}
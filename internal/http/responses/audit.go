@@ -0,0 +1,29 @@
+package responses
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type AuditEntryResponse struct {
+	Id         uint   `json:"id" example:"1"`
+	Action     string `json:"action" example:"author.update"`
+	ActorID    uint   `json:"actor_id" example:"1"`
+	ActorEmail string `json:"actor_email" example:"edwyn.rangel.externo@zeleri.com"`
+	// ImpersonatorID admin acting as the actor
+	ImpersonatorID uint            `json:"impersonator_id,omitempty" example:"2"`
+	TargetType     string          `json:"target_type" example:"author"`
+	TargetID       string          `json:"target_id" example:"30"`
+	Changes        json.RawMessage `json:"changes,omitempty" swaggertype:"object"`
+	Detail         string          `json:"detail,omitempty" example:"invalid credentials"`
+	IP             string          `json:"ip" example:"10.0.0.1"`
+	RequestID      string          `json:"request_id" example:"0f8fad5b-d9cb-469f-a165-70867728950e"`
+	CreatedAt      time.Time       `json:"created_at" example:"2022-11-01T10:00:00Z"`
+}
+
+type AuditListResponse struct {
+	Items []AuditEntryResponse `json:"items"`
+	Total int64                `json:"total" example:"42"`
+	Page  int                  `json:"page" example:"1"`
+	Limit int                  `json:"limit" example:"20"`
+}
@@ -1,5 +1,26 @@
 package responses
 
+import "time"
+
 type LoginResponse struct {
-	Token string `json:"token"`
+	Token        string `json:"token,omitempty"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	// MFAToken challenge to be exchanged in /auth/mfa/verify when MFARequired
+	MFARequired bool   `json:"mfa_required,omitempty"`
+	MFAToken    string `json:"mfa_token,omitempty"`
+}
+
+type ImpersonationResponse struct {
+	Token     string    `json:"token"`
+	UserID    uint      `json:"user_id" example:"30"`
+	ExpiresAt time.Time `json:"expires_at" example:"2022-11-01T10:10:00Z"`
+}
+
+type TOTPEnrollmentResponse struct {
+	Secret string `json:"secret" example:"JBSWY3DPEHPK3PXP"`
+	URI    string `json:"otpauth_uri" example:"otpauth://totp/go-fiber-template:edwyn@example.com?secret=JBSWY3DPEHPK3PXP"`
+}
+
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes" example:"3f9a-1c2b-77de-0a41"`
 }
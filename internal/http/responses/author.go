@@ -3,4 +3,13 @@ package responses
 type AuthorResponse struct {
 	Id       uint   `json:"id" example:"30"`
 	FullName string `json:"full_name" example:"J. J. Benítez"`
+	// Books only returned with ?include=books
+	Books []BookResponse `json:"books,omitempty"`
+}
+
+type AuthorListResponse struct {
+	Items []AuthorResponse `json:"items"`
+	Total int64            `json:"total" example:"42"`
+	Limit int              `json:"limit" example:"20"`
+	Links ListLinks        `json:"links"`
 }
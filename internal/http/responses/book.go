@@ -6,4 +6,13 @@ type BookResponse struct {
 	PublicationYear string `json:"publication_year" example:"1984"`
 	AuthorID        uint   `json:"author_id" example:"30"`
 	AuthorName      string `json:"author_name" example:"J. J. Benítez"`
+	ISBN10          string `json:"isbn_10,omitempty" example:"0306406152"`
+	ISBN13          string `json:"isbn_13,omitempty" example:"9780306406157"`
+}
+
+type BookListResponse struct {
+	Items []BookResponse `json:"items"`
+	Total int64          `json:"total" example:"42"`
+	Limit int            `json:"limit" example:"20"`
+	Links ListLinks      `json:"links"`
 }
@@ -0,0 +1,7 @@
+package responses
+
+import "github.com/karlbehrensg/go-fiber-template/pkg/utils"
+
+type JWKSResponse struct {
+	Keys []utils.JWK `json:"keys"`
+}
@@ -0,0 +1,7 @@
+package responses
+
+// ListLinks links to the next and previous pages, empty at the ends of the list
+type ListLinks struct {
+	Next string `json:"next,omitempty" example:"/book?cursor=eyJzIjoiaWQiLCJ2IjpbMzBdfQ&limit=20"`
+	Prev string `json:"prev,omitempty" example:"/book?cursor=eyJiIjp0cnVlLCJzIjoiaWQiLCJ2IjpbMTFdfQ&limit=20"`
+}
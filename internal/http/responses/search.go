@@ -0,0 +1,21 @@
+package responses
+
+type SearchHitResponse struct {
+	Type  string  `json:"type" example:"book"`
+	Id    uint    `json:"id" example:"1"`
+	Score float64 `json:"score" example:"2.386"`
+	// Fields text of the book (title, author_name) or the author (full_name)
+	Fields map[string]string `json:"fields"`
+	// Highlights fields with the matching words wrapped in <em>, the rest of the text is HTML escaped
+	Highlights map[string]string `json:"highlights"`
+}
+
+type SearchResponse struct {
+	Items []SearchHitResponse `json:"items"`
+	Total int                 `json:"total" example:"3"`
+}
+
+type SearchRebuildResponse struct {
+	Authors int `json:"authors" example:"12"`
+	Books   int `json:"books" example:"120"`
+}
@@ -0,0 +1,12 @@
+package responses
+
+import "time"
+
+type SessionResponse struct {
+	Id         uint      `json:"id" example:"1"`
+	UserAgent  string    `json:"user_agent" example:"Mozilla/5.0 (X11; Linux x86_64)"`
+	IP         string    `json:"ip" example:"10.0.0.1"`
+	Current    bool      `json:"current" example:"true"`
+	LastSeenAt time.Time `json:"last_seen_at" example:"2022-11-01T10:00:00Z"`
+	CreatedAt  time.Time `json:"created_at" example:"2022-11-01T10:00:00Z"`
+}
@@ -1,5 +1,35 @@
 package responses
 
+import "time"
+
 type UserResponse struct {
 	Message string `json:"message" example:"user created"`
 }
+
+type ProfileResponse struct {
+	Id         uint      `json:"id" example:"1"`
+	Name       string    `json:"full_name" example:"Edwyn Rangel"`
+	Email      string    `json:"email" example:"edwyn.rangel.externo@zeleri.com"`
+	Role       string    `json:"role" example:"reader"`
+	MFAEnabled bool      `json:"mfa_enabled" example:"false"`
+	CreatedAt  time.Time `json:"created_at" example:"2022-11-01T10:00:00Z"`
+}
+
+type AdminUserResponse struct {
+	Id         uint       `json:"id" example:"1"`
+	Name       string     `json:"full_name" example:"Edwyn Rangel"`
+	Email      string     `json:"email" example:"edwyn.rangel.externo@zeleri.com"`
+	Role       string     `json:"role" example:"reader"`
+	Verified   bool       `json:"verified" example:"true"`
+	MFAEnabled bool       `json:"mfa_enabled" example:"false"`
+	DisabledAt *time.Time `json:"disabled_at" example:"2022-11-01T10:00:00Z"`
+	DeletedAt  *time.Time `json:"deleted_at" example:"2022-11-01T10:00:00Z"`
+	CreatedAt  time.Time  `json:"created_at" example:"2022-11-01T10:00:00Z"`
+}
+
+type UserListResponse struct {
+	Items []AdminUserResponse `json:"items"`
+	Total int64               `json:"total" example:"42"`
+	Page  int                 `json:"page" example:"1"`
+	Limit int                 `json:"limit" example:"20"`
+}
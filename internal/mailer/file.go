@@ -0,0 +1,42 @@
+package mailer
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/karlbehrensg/go-fiber-template/internal/domain"
+	"github.com/karlbehrensg/go-fiber-template/pkg/errs"
+	"github.com/karlbehrensg/go-fiber-template/pkg/logger"
+)
+
+type FileMailer struct {
+	mu   *sync.Mutex
+	path string
+}
+
+// NewFileMailer create a new instance of FileMailer
+func NewFileMailer(path string) FileMailer {
+	return FileMailer{&sync.Mutex{}, path}
+}
+
+// Send append the mail to a file, intended for development
+func (m FileMailer) Send(mail domain.Mail) *errs.AppError {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	file, err := os.OpenFile(m.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		logger.Error(err.Error())
+		return errs.NewUnexpectedError("Unexpected error while sending mail")
+	}
+	defer file.Close()
+
+	if _, err = fmt.Fprintf(file, "Date: %s\nTo: %s\nSubject: %s\n\n%s\n\n", time.Now().Format(time.RFC1123Z), mail.To, mail.Subject, mail.Body); err != nil {
+		logger.Error(err.Error())
+		return errs.NewUnexpectedError("Unexpected error while sending mail")
+	}
+
+	return nil
+}
@@ -0,0 +1,26 @@
+package mailer
+
+import (
+	"github.com/karlbehrensg/go-fiber-template/internal/domain"
+	"github.com/karlbehrensg/go-fiber-template/pkg/errs"
+	"github.com/karlbehrensg/go-fiber-template/pkg/logger"
+	"go.uber.org/zap"
+)
+
+type LogMailer struct{}
+
+// NewLogMailer create a new instance of LogMailer
+func NewLogMailer() LogMailer {
+	return LogMailer{}
+}
+
+// Send write the mail in the application log, intended for development
+func (m LogMailer) Send(mail domain.Mail) *errs.AppError {
+	logger.Info("Mail sent",
+		zap.String("to", mail.To),
+		zap.String("subject", mail.Subject),
+		zap.String("body", mail.Body),
+	)
+
+	return nil
+}
@@ -0,0 +1,20 @@
+package mailer
+
+import (
+	"os"
+
+	"github.com/karlbehrensg/go-fiber-template/internal/domain"
+)
+
+// NewMailer select the mailer from env, MAILER=file writes the mails to MAILER_FILE_PATH
+func NewMailer() domain.Mailer {
+	if os.Getenv("MAILER") == "file" {
+		path := os.Getenv("MAILER_FILE_PATH")
+		if path == "" {
+			path = "mails.log"
+		}
+		return NewFileMailer(path)
+	}
+
+	return NewLogMailer()
+}
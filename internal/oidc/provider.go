@@ -0,0 +1,304 @@
+package oidc
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/karlbehrensg/go-fiber-template/internal/domain"
+	"github.com/karlbehrensg/go-fiber-template/pkg/errs"
+	"github.com/karlbehrensg/go-fiber-template/pkg/logger"
+	"github.com/karlbehrensg/go-fiber-template/pkg/utils"
+)
+
+// Config client registration in the identity provider
+type Config struct {
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
+	// RoleClaim claim with the roles or groups of the user, nested claims use dots e.g. realm_access.roles
+	RoleClaim string
+	// KeysRefreshInterval minimum time between two fetches of the JWKS for an unknown kid
+	KeysRefreshInterval time.Duration
+}
+
+// Provider OpenID Connect adapter using the authorization code flow with PKCE
+type Provider struct {
+	config    Config
+	client    *http.Client
+	mu        *sync.Mutex
+	discovery *discoveryDocument
+	keys      map[string]interface{}
+	// keysFetchedAt last fetch of the JWKS, successful or not
+	keysFetchedAt time.Time
+}
+
+// discoveryDocument fields used from /.well-known/openid-configuration
+type discoveryDocument struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// tokenResponse fields used from the token endpoint
+type tokenResponse struct {
+	IDToken          string `json:"id_token"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// ConfigFromEnv read the client registration from env
+func ConfigFromEnv() Config {
+	return Config{
+		Issuer:              os.Getenv("OIDC_ISSUER"),
+		ClientID:            os.Getenv("OIDC_CLIENT_ID"),
+		ClientSecret:        os.Getenv("OIDC_CLIENT_SECRET"),
+		RedirectURL:         os.Getenv("OIDC_REDIRECT_URL"),
+		Scopes:              strings.Fields(os.Getenv("OIDC_SCOPES")),
+		RoleClaim:           os.Getenv("OIDC_ROLE_CLAIM"),
+		KeysRefreshInterval: utils.GetEnvDuration("OIDC_JWKS_REFRESH_INTERVAL", time.Minute),
+	}
+}
+
+// NewProvider create a new instance of Provider, the discovery document is fetched on first use
+func NewProvider(config Config) *Provider {
+	if len(config.Scopes) == 0 {
+		config.Scopes = []string{"openid", "email", "profile"}
+	}
+	if config.KeysRefreshInterval <= 0 {
+		config.KeysRefreshInterval = time.Minute
+	}
+
+	return &Provider{
+		config: config,
+		client: &http.Client{Timeout: time.Second * 10},
+		mu:     &sync.Mutex{},
+		keys:   map[string]interface{}{},
+	}
+}
+
+// AuthCodeURL create the URL of the provider where the user must be redirected to login
+func (p *Provider) AuthCodeURL(state string, nonce string, codeChallenge string) (string, *errs.AppError) {
+	discovery, appErr := p.getDiscovery()
+	if appErr != nil {
+		return "", appErr
+	}
+
+	query := url.Values{}
+	query.Set("response_type", "code")
+	query.Set("client_id", p.config.ClientID)
+	query.Set("redirect_uri", p.config.RedirectURL)
+	query.Set("scope", strings.Join(p.config.Scopes, " "))
+	query.Set("state", state)
+	query.Set("nonce", nonce)
+	query.Set("code_challenge", codeChallenge)
+	query.Set("code_challenge_method", "S256")
+
+	separator := "?"
+	if strings.Contains(discovery.AuthorizationEndpoint, "?") {
+		separator = "&"
+	}
+
+	return discovery.AuthorizationEndpoint + separator + query.Encode(), nil
+}
+
+// Exchange redeem the authorization code and verify the ID token
+func (p *Provider) Exchange(code string, codeVerifier string, nonce string) (*domain.IdentityClaims, *errs.AppError) {
+	discovery, appErr := p.getDiscovery()
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", p.config.RedirectURL)
+	form.Set("client_id", p.config.ClientID)
+	form.Set("client_secret", p.config.ClientSecret)
+	form.Set("code_verifier", codeVerifier)
+
+	res, err := p.client.PostForm(discovery.TokenEndpoint, form)
+	if err != nil {
+		logger.Error(err.Error())
+		return nil, errs.NewUnexpectedError("identity provider is not available")
+	}
+	defer res.Body.Close()
+
+	var token tokenResponse
+	if err = json.NewDecoder(res.Body).Decode(&token); err != nil {
+		logger.Error(err.Error())
+		return nil, errs.NewUnexpectedError("invalid response from identity provider")
+	}
+	if res.StatusCode != http.StatusOK || token.IDToken == "" {
+		logger.Error(fmt.Sprintf("Token endpoint returned %d: %s %s", res.StatusCode, token.Error, token.ErrorDescription))
+		return nil, errs.NewAuthenticationError("invalid authorization code")
+	}
+
+	return p.verifyIDToken(token.IDToken, nonce)
+}
+
+// verifyIDToken validate signature, issuer, audience, expiry and nonce of the ID token
+func (p *Provider) verifyIDToken(idToken string, nonce string) (*domain.IdentityClaims, *errs.AppError) {
+	claims := jwt.MapClaims{}
+	parser := &jwt.Parser{ValidMethods: []string{jwt.SigningMethodRS256.Alg(), jwt.SigningMethodEdDSA.Alg()}}
+	if _, err := parser.ParseWithClaims(idToken, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		return p.getKey(kid)
+	}); err != nil {
+		logger.Error(err.Error())
+		return nil, errs.NewAuthenticationError("invalid ID token")
+	}
+
+	if !claims.VerifyExpiresAt(time.Now().Unix(), true) {
+		return nil, errs.NewAuthenticationError("invalid ID token")
+	}
+	if !claims.VerifyIssuer(p.config.Issuer, true) || !claims.VerifyAudience(p.config.ClientID, true) {
+		return nil, errs.NewAuthenticationError("invalid ID token issuer or audience")
+	}
+	if claimString(claims, "nonce") != nonce {
+		return nil, errs.NewAuthenticationError("invalid ID token nonce")
+	}
+
+	identity := &domain.IdentityClaims{
+		Subject: claimString(claims, "sub"),
+		Email:   claimString(claims, "email"),
+		Name:    claimString(claims, "name"),
+		Roles:   claimStrings(claims, p.config.RoleClaim),
+	}
+	identity.EmailVerified, _ = claims["email_verified"].(bool)
+	if identity.Subject == "" {
+		return nil, errs.NewAuthenticationError("invalid ID token subject")
+	}
+
+	return identity, nil
+}
+
+// getDiscovery fetch the discovery document once
+func (p *Provider) getDiscovery() (*discoveryDocument, *errs.AppError) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.discovery != nil {
+		return p.discovery, nil
+	}
+
+	var discovery discoveryDocument
+	if err := p.getJSON(strings.TrimSuffix(p.config.Issuer, "/")+"/.well-known/openid-configuration", &discovery); err != nil {
+		logger.Error(err.Error())
+		return nil, errs.NewUnexpectedError("identity provider is not available")
+	}
+	if discovery.Issuer != p.config.Issuer {
+		logger.Error(fmt.Sprintf("Discovery issuer %s does not match %s", discovery.Issuer, p.config.Issuer))
+		return nil, errs.NewUnexpectedError("identity provider is misconfigured")
+	}
+
+	p.discovery = &discovery
+	return p.discovery, nil
+}
+
+// getKey find the provider key by kid, the JWKS is fetched again when the kid is unknown at most once
+// per KeysRefreshInterval; the fetch runs outside the lock and only a successful one replaces the keys
+func (p *Provider) getKey(kid string) (interface{}, error) {
+	p.mu.Lock()
+	if key, ok := p.keys[kid]; ok {
+		p.mu.Unlock()
+		return key, nil
+	}
+	if time.Since(p.keysFetchedAt) < p.config.KeysRefreshInterval {
+		p.mu.Unlock()
+		return nil, fmt.Errorf("unknown identity provider key %s", kid)
+	}
+	// the fetch is reserved before unlocking so concurrent logins do not fetch again
+	p.keysFetchedAt = time.Now()
+	jwksURI := p.discovery.JWKSURI
+	p.mu.Unlock()
+
+	var jwks struct {
+		Keys []utils.JWK `json:"keys"`
+	}
+	if err := p.getJSON(jwksURI, &jwks); err != nil {
+		return nil, err
+	}
+
+	keys := map[string]interface{}{}
+	for _, jwk := range jwks.Keys {
+		key, err := jwk.PublicKey()
+		if err != nil {
+			continue
+		}
+		keys[jwk.Kid] = key
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("identity provider returned no usable keys")
+	}
+
+	p.mu.Lock()
+	p.keys = keys
+	p.mu.Unlock()
+
+	key, ok := keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown identity provider key %s", kid)
+	}
+
+	return key, nil
+}
+
+func (p *Provider) getJSON(url string, out interface{}) error {
+	res, err := p.client.Get(url)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s returned %d", url, res.StatusCode)
+	}
+
+	return json.NewDecoder(res.Body).Decode(out)
+}
+
+func claimString(claims jwt.MapClaims, name string) string {
+	value, _ := claims[name].(string)
+	return value
+}
+
+// claimStrings read a string or list claim, nested claims use dots
+func claimStrings(claims jwt.MapClaims, path string) []string {
+	if path == "" {
+		return nil
+	}
+
+	var value interface{} = map[string]interface{}(claims)
+	for _, name := range strings.Split(path, ".") {
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		value = object[name]
+	}
+
+	switch v := value.(type) {
+	case string:
+		return []string{v}
+	case []interface{}:
+		values := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	}
+
+	return nil
+}
@@ -0,0 +1,210 @@
+package oidc
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/karlbehrensg/go-fiber-template/pkg/utils"
+)
+
+// stubProvider local identity provider issuing ID tokens for the code "good-code"
+type stubProvider struct {
+	server    *httptest.Server
+	key       *rsa.PrivateKey
+	challenge string
+	nonce     string
+	// jwksRequests fetches of the JWKS, jwksDown makes them fail
+	jwksRequests int32
+	jwksDown     int32
+}
+
+func newStubProvider(t *testing.T) *stubProvider {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stub := &stubProvider{key: key}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 stub.server.URL,
+			"authorization_endpoint": stub.server.URL + "/authorize",
+			"token_endpoint":         stub.server.URL + "/token",
+			"jwks_uri":               stub.server.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&stub.jwksRequests, 1)
+		if atomic.LoadInt32(&stub.jwksDown) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		jwk, _ := (&utils.SigningKey{ID: "stub", Method: jwt.SigningMethodRS256, Public: &key.PublicKey}).JWK()
+		json.NewEncoder(w).Encode(map[string]interface{}{"keys": []interface{}{jwk}})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		sum := sha256.Sum256([]byte(r.Form.Get("code_verifier")))
+		if r.Form.Get("code") != "good-code" || base64.RawURLEncoding.EncodeToString(sum[:]) != stub.challenge {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+			return
+		}
+
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+			"iss":            stub.server.URL,
+			"aud":            []string{"library", "another-client"},
+			"sub":            "subject-1",
+			"email":          "edwyn@example.com",
+			"email_verified": true,
+			"name":           "Edwyn Rangel",
+			"nonce":          stub.nonce,
+			"iat":            time.Now().Unix(),
+			"exp":            time.Now().Add(time.Minute).Unix(),
+			"realm_access":   map[string]interface{}{"roles": []string{"library-staff"}},
+		})
+		token.Header["kid"] = "stub"
+		idToken, _ := token.SignedString(key)
+		json.NewEncoder(w).Encode(map[string]string{"id_token": idToken})
+	})
+
+	stub.server = httptest.NewServer(mux)
+	t.Cleanup(stub.server.Close)
+
+	return stub
+}
+
+// authorize start the flow and keep what the provider would remember
+func (s *stubProvider) authorize(t *testing.T, provider *Provider, nonce string, verifier string) {
+	sum := sha256.Sum256([]byte(verifier))
+	authURL, appErr := provider.AuthCodeURL("state", nonce, base64.RawURLEncoding.EncodeToString(sum[:]))
+	if appErr != nil {
+		t.Fatal(appErr.Message)
+	}
+
+	parsed, _ := url.Parse(authURL)
+	s.challenge = parsed.Query().Get("code_challenge")
+	s.nonce = parsed.Query().Get("nonce")
+}
+
+func Test_should_return_identity_when_code_and_id_token_are_valid(t *testing.T) {
+	// Arrange
+	stub := newStubProvider(t)
+	provider := NewProvider(Config{Issuer: stub.server.URL, ClientID: "library", RoleClaim: "realm_access.roles"})
+	stub.authorize(t, provider, "nonce", "verifier")
+
+	// Act
+	identity, appErr := provider.Exchange("good-code", "verifier", "nonce")
+
+	// Assert
+	if appErr != nil {
+		t.Fatal(appErr.Message)
+	}
+	if identity.Subject != "subject-1" || !identity.EmailVerified || len(identity.Roles) != 1 || identity.Roles[0] != "library-staff" {
+		t.Error("Test failed while mapping ID token claims")
+	}
+}
+
+func Test_should_return_an_error_when_pkce_verifier_does_not_match(t *testing.T) {
+	// Arrange
+	stub := newStubProvider(t)
+	provider := NewProvider(Config{Issuer: stub.server.URL, ClientID: "library"})
+	stub.authorize(t, provider, "nonce", "verifier")
+
+	// Act
+	_, appErr := provider.Exchange("good-code", "another-verifier", "nonce")
+
+	// Assert
+	if appErr == nil || appErr.Code != http.StatusUnauthorized {
+		t.Error("Test failed while validating PKCE verifier")
+	}
+}
+
+func Test_should_return_an_error_when_id_token_nonce_does_not_match(t *testing.T) {
+	// Arrange
+	stub := newStubProvider(t)
+	provider := NewProvider(Config{Issuer: stub.server.URL, ClientID: "library"})
+	stub.authorize(t, provider, "nonce", "verifier")
+
+	// Act
+	_, appErr := provider.Exchange("good-code", "verifier", "another-nonce")
+
+	// Assert
+	if appErr == nil || appErr.Message != "invalid ID token nonce" {
+		t.Error("Test failed while validating ID token nonce")
+	}
+}
+
+func Test_should_return_an_error_when_id_token_audience_is_another_client(t *testing.T) {
+	// Arrange
+	stub := newStubProvider(t)
+	provider := NewProvider(Config{Issuer: stub.server.URL, ClientID: "unknown-client"})
+	stub.authorize(t, provider, "nonce", "verifier")
+
+	// Act
+	_, appErr := provider.Exchange("good-code", "verifier", "nonce")
+
+	// Assert
+	if appErr == nil || appErr.Message != "invalid ID token issuer or audience" {
+		t.Error("Test failed while validating ID token audience")
+	}
+}
+
+func Test_should_fetch_the_jwks_at_most_once_per_interval_when_kid_is_unknown(t *testing.T) {
+	// Arrange
+	stub := newStubProvider(t)
+	provider := NewProvider(Config{Issuer: stub.server.URL, ClientID: "library", KeysRefreshInterval: time.Hour})
+	if _, appErr := provider.getDiscovery(); appErr != nil {
+		t.Fatal(appErr.Message)
+	}
+
+	// Act
+	for i := 0; i < 5; i++ {
+		provider.getKey("unknown")
+	}
+	_, err := provider.getKey("stub")
+
+	// Assert
+	if requests := atomic.LoadInt32(&stub.jwksRequests); requests != 1 {
+		t.Errorf("Test failed while rate limiting the JWKS fetch: %d requests", requests)
+	}
+	if err != nil {
+		t.Errorf("Test failed while keeping the fetched keys: %s", err.Error())
+	}
+}
+
+func Test_should_keep_the_keys_when_the_jwks_fetch_fails(t *testing.T) {
+	// Arrange
+	stub := newStubProvider(t)
+	provider := NewProvider(Config{Issuer: stub.server.URL, ClientID: "library", KeysRefreshInterval: time.Nanosecond})
+	if _, appErr := provider.getDiscovery(); appErr != nil {
+		t.Fatal(appErr.Message)
+	}
+	if _, err := provider.getKey("stub"); err != nil {
+		t.Fatal(err.Error())
+	}
+	atomic.StoreInt32(&stub.jwksDown, 1)
+
+	// Act
+	_, unknownErr := provider.getKey("rotated")
+	_, err := provider.getKey("stub")
+
+	// Assert
+	if unknownErr == nil || atomic.LoadInt32(&stub.jwksRequests) != 2 {
+		t.Error("Test failed while fetching the JWKS for an unknown kid")
+	}
+	if err != nil {
+		t.Errorf("Test failed while keeping the keys after a failed fetch: %s", err.Error())
+	}
+}
@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"fmt"
 	"strings"
 
 	"github.com/karlbehrensg/go-fiber-template/internal/domain"
@@ -45,3 +46,175 @@ func (r UserRepositoryGorm) FindUserByEmail(email string) (*domain.User, *errs.A
 
 	return user, nil
 }
+
+// FindUserById find user by ID in database
+func (r UserRepositoryGorm) FindUserById(id uint) (*domain.User, *errs.AppError) {
+	var user *domain.User
+
+	if err := r.client.Where("id = ?", id).First(&user).Error; err != nil {
+		logger.Error(err.Error())
+		if strings.Contains(err.Error(), "record not found") {
+			return nil, errs.NewNotFoundError(err.Error())
+		}
+		return nil, errs.NewUnexpectedError("Unexpected error from database")
+	}
+
+	return user, nil
+}
+
+// FindUserByOIDCSubject find user linked to the identity provider subject in database
+func (r UserRepositoryGorm) FindUserByOIDCSubject(subject string) (*domain.User, *errs.AppError) {
+	var user *domain.User
+
+	if err := r.client.Where("oidc_subject = ?", subject).First(&user).Error; err != nil {
+		logger.Error(err.Error())
+		if strings.Contains(err.Error(), "record not found") {
+			return nil, errs.NewNotFoundError(err.Error())
+		}
+		return nil, errs.NewUnexpectedError("Unexpected error from database")
+	}
+
+	return user, nil
+}
+
+// UpdateUser update user in database
+func (r UserRepositoryGorm) UpdateUser(user *domain.User) (*domain.User, *errs.AppError) {
+	var result *gorm.DB
+	if result = r.client.Where("id = ?", user.ID).Updates(&user); result.Error != nil {
+		logger.Error(result.Error.Error())
+		if strings.Contains(result.Error.Error(), "ERROR: duplicate key value violates unique constraint ") {
+			return nil, errs.NewBadRequestError("key email duplicate value")
+		}
+		return nil, errs.NewUnexpectedError("Unexpected error from database")
+	}
+
+	// validates if the rows have changed
+	if result.RowsAffected < 1 {
+		logger.Info(fmt.Sprintf("Row with id=%d cannot be updated because it doesn't exist", user.ID))
+		return nil, errs.NewNotFoundError("User not found")
+	}
+
+	return user, nil
+}
+
+// UpdatePassword update the password hash and increase the token version in database
+func (r UserRepositoryGorm) UpdatePassword(id uint, hash string) *errs.AppError {
+	var result *gorm.DB
+	if result = r.client.Model(&domain.User{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"password":      hash,
+		"token_version": gorm.Expr("token_version + 1"),
+	}); result.Error != nil {
+		logger.Error(result.Error.Error())
+		return errs.NewUnexpectedError("Unexpected error from database")
+	}
+
+	// validates if the rows have changed
+	if result.RowsAffected < 1 {
+		logger.Info(fmt.Sprintf("Row with id=%d cannot be updated because it doesn't exist", id))
+		return errs.NewNotFoundError("User not found")
+	}
+
+	return nil
+}
+
+// UpdateUserColumns update the given columns of the user in database, zero values included
+func (r UserRepositoryGorm) UpdateUserColumns(id uint, columns map[string]interface{}) *errs.AppError {
+	var result *gorm.DB
+	if result = r.client.Model(&domain.User{}).Where("id = ?", id).Updates(columns); result.Error != nil {
+		logger.Error(result.Error.Error())
+		return errs.NewUnexpectedError("Unexpected error from database")
+	}
+
+	// validates if the rows have changed
+	if result.RowsAffected < 1 {
+		logger.Info(fmt.Sprintf("Row with id=%d cannot be updated because it doesn't exist", id))
+		return errs.NewNotFoundError("User not found")
+	}
+
+	return nil
+}
+
+// FindUsers find a page of users matching the filter in database, returns the total of matches
+func (r UserRepositoryGorm) FindUsers(filter domain.UserFilter) ([]domain.User, int64, *errs.AppError) {
+	query := r.client.Model(&domain.User{})
+	switch filter.Status {
+	case domain.UserStatusActive:
+		query = query.Where("disabled_at IS NULL")
+	case domain.UserStatusDisabled:
+		query = query.Where("disabled_at IS NOT NULL")
+	case domain.UserStatusDeleted:
+		query = query.Unscoped().Where("deleted_at IS NOT NULL")
+	}
+	if filter.Role != "" {
+		query = query.Where("role = ?", filter.Role)
+	}
+	if filter.Search != "" {
+		search := containsPattern(filter.Search)
+		query = query.Where("LOWER(email) LIKE ? ESCAPE '\\' OR LOWER(name) LIKE ? ESCAPE '\\'", search, search)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		logger.Error(err.Error())
+		return nil, 0, errs.NewUnexpectedError("Unexpected error from database")
+	}
+
+	users := []domain.User{}
+	if err := query.Order("id").Offset(filter.Offset).Limit(filter.Limit).Find(&users).Error; err != nil {
+		logger.Error(err.Error())
+		return nil, 0, errs.NewUnexpectedError("Unexpected error from database")
+	}
+
+	return users, total, nil
+}
+
+// FindUserByIdWithDeleted find user by ID in database, soft deleted users included
+func (r UserRepositoryGorm) FindUserByIdWithDeleted(id uint) (*domain.User, *errs.AppError) {
+	var user *domain.User
+
+	if err := r.client.Unscoped().Where("id = ?", id).First(&user).Error; err != nil {
+		logger.Error(err.Error())
+		if strings.Contains(err.Error(), "record not found") {
+			return nil, errs.NewNotFoundError(err.Error())
+		}
+		return nil, errs.NewUnexpectedError("Unexpected error from database")
+	}
+
+	return user, nil
+}
+
+// DeleteUser soft delete user in database
+func (r UserRepositoryGorm) DeleteUser(id uint) *errs.AppError {
+	var result *gorm.DB
+	if result = r.client.Delete(&domain.User{}, id); result.Error != nil {
+		logger.Error(result.Error.Error())
+		return errs.NewUnexpectedError("Unexpected error from database")
+	}
+
+	// validates if the rows have changed
+	if result.RowsAffected < 1 {
+		logger.Info(fmt.Sprintf("Row with id=%d cannot be deleted because it doesn't exist", id))
+		return errs.NewNotFoundError("User not found")
+	}
+
+	return nil
+}
+
+// RestoreUser undo the soft delete of user in database
+func (r UserRepositoryGorm) RestoreUser(id uint) *errs.AppError {
+	var result *gorm.DB
+	if result = r.client.Unscoped().Model(&domain.User{}).
+		Where("id = ? AND deleted_at IS NOT NULL", id).
+		Update("deleted_at", nil); result.Error != nil {
+		logger.Error(result.Error.Error())
+		return errs.NewUnexpectedError("Unexpected error from database")
+	}
+
+	// validates if the rows have changed
+	if result.RowsAffected < 1 {
+		logger.Info(fmt.Sprintf("Row with id=%d cannot be restored because it doesn't exist or is not deleted", id))
+		return errs.NewNotFoundError("Deleted user not found")
+	}
+
+	return nil
+}
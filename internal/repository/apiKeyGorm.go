@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/karlbehrensg/go-fiber-template/internal/domain"
+	"github.com/karlbehrensg/go-fiber-template/pkg/errs"
+	"github.com/karlbehrensg/go-fiber-template/pkg/logger"
+	"gorm.io/gorm"
+)
+
+type APIKeyRepositoryGorm struct {
+	client *gorm.DB
+}
+
+// NewAPIKeyRepositoryGorm create a new instance of APIKeyRepositoryGorm
+func NewAPIKeyRepositoryGorm(dbClient *gorm.DB) APIKeyRepositoryGorm {
+	return APIKeyRepositoryGorm{dbClient}
+}
+
+// SaveAPIKey save API key in database
+func (r APIKeyRepositoryGorm) SaveAPIKey(key *domain.APIKey) *errs.AppError {
+	if err := r.client.Create(key).Error; err != nil {
+		logger.Error(err.Error())
+		return errs.NewUnexpectedError("Unexpected error from database")
+	}
+
+	return nil
+}
+
+// FindAPIKeyByHash find API key by hash in database
+func (r APIKeyRepositoryGorm) FindAPIKeyByHash(hash string) (*domain.APIKey, *errs.AppError) {
+	var key *domain.APIKey
+
+	if err := r.client.Where("key_hash = ?", hash).First(&key).Error; err != nil {
+		logger.Error(err.Error())
+		if strings.Contains(err.Error(), "record not found") {
+			return nil, errs.NewNotFoundError(err.Error())
+		}
+		return nil, errs.NewUnexpectedError("Unexpected error from database")
+	}
+
+	return key, nil
+}
+
+// FindAPIKeysByUser find the API keys of the user in database
+func (r APIKeyRepositoryGorm) FindAPIKeysByUser(userID uint) ([]domain.APIKey, *errs.AppError) {
+	var keys []domain.APIKey
+
+	if err := r.client.Where("user_id = ?", userID).Order("id").Find(&keys).Error; err != nil {
+		logger.Error(err.Error())
+		return nil, errs.NewUnexpectedError("Unexpected error from database")
+	}
+
+	return keys, nil
+}
+
+// RevokeAPIKey mark the API key of the user as revoked in database
+func (r APIKeyRepositoryGorm) RevokeAPIKey(userID uint, id uint) *errs.AppError {
+	var result *gorm.DB
+	if result = r.client.Model(&domain.APIKey{}).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL", id, userID).
+		Update("revoked_at", time.Now()); result.Error != nil {
+		logger.Error(result.Error.Error())
+		return errs.NewUnexpectedError("Unexpected error from database")
+	}
+
+	// validates if the rows have changed
+	if result.RowsAffected < 1 {
+		logger.Info(fmt.Sprintf("API key with id=%d not found for user id=%d", id, userID))
+		return errs.NewNotFoundError("record not found")
+	}
+
+	return nil
+}
+
+// TouchAPIKey update the last time the API key was used in database
+func (r APIKeyRepositoryGorm) TouchAPIKey(id uint, usedAt time.Time) *errs.AppError {
+	if err := r.client.Model(&domain.APIKey{}).Where("id = ?", id).Update("last_used_at", usedAt).Error; err != nil {
+		logger.Error(err.Error())
+		return errs.NewUnexpectedError("Unexpected error from database")
+	}
+
+	return nil
+}
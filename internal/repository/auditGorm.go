@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"github.com/karlbehrensg/go-fiber-template/internal/domain"
+	"github.com/karlbehrensg/go-fiber-template/pkg/errs"
+	"github.com/karlbehrensg/go-fiber-template/pkg/logger"
+	"gorm.io/gorm"
+)
+
+type AuditRepositoryGorm struct {
+	client *gorm.DB
+}
+
+// NewAuditRepositoryGorm create a new instance of AuditRepositoryGorm
+func NewAuditRepositoryGorm(dbClient *gorm.DB) AuditRepositoryGorm {
+	return AuditRepositoryGorm{dbClient}
+}
+
+// SaveAuditEntry append the entry to the audit log in database
+func (r AuditRepositoryGorm) SaveAuditEntry(entry *domain.AuditEntry) *errs.AppError {
+	if err := r.client.Create(entry).Error; err != nil {
+		logger.Error(err.Error())
+		return errs.NewUnexpectedError("Unexpected error from database")
+	}
+
+	return nil
+}
+
+// FindAuditEntries find a page of audit entries matching the filter in database, most recent first
+func (r AuditRepositoryGorm) FindAuditEntries(filter domain.AuditFilter) ([]domain.AuditEntry, int64, *errs.AppError) {
+	query := r.client.Model(&domain.AuditEntry{})
+	if filter.Action != "" {
+		query = query.Where("action = ?", filter.Action)
+	}
+	if filter.ActorID != 0 {
+		query = query.Where("actor_id = ?", filter.ActorID)
+	}
+	if filter.ImpersonatorID != 0 {
+		query = query.Where("impersonator_id = ?", filter.ImpersonatorID)
+	}
+	if filter.TargetType != "" {
+		query = query.Where("target_type = ?", filter.TargetType)
+	}
+	if filter.TargetID != "" {
+		query = query.Where("target_id = ?", filter.TargetID)
+	}
+	if filter.RequestID != "" {
+		query = query.Where("request_id = ?", filter.RequestID)
+	}
+	if filter.From != nil {
+		query = query.Where("created_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("created_at < ?", *filter.To)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		logger.Error(err.Error())
+		return nil, 0, errs.NewUnexpectedError("Unexpected error from database")
+	}
+
+	entries := []domain.AuditEntry{}
+	if err := query.Order("id DESC").Offset(filter.Offset).Limit(filter.Limit).Find(&entries).Error; err != nil {
+		logger.Error(err.Error())
+		return nil, 0, errs.NewUnexpectedError("Unexpected error from database")
+	}
+
+	return entries, total, nil
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/karlbehrensg/go-fiber-template/pkg/errs"
 	"github.com/karlbehrensg/go-fiber-template/pkg/logger"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type AuthorRepositoryGorm struct {
@@ -32,15 +33,23 @@ func (r AuthorRepositoryGorm) SaveAuthor(author *domain.Author) *errs.AppError {
 	return nil
 }
 
-// FindAllAuthor find all author in database
-func (r AuthorRepositoryGorm) FindAllAuthor() ([]domain.Author, *errs.AppError) {
+// FindAllAuthor find a page of authors matching the filters in database, returns the total of matches
+func (r AuthorRepositoryGorm) FindAllAuthor(list domain.ListQuery) ([]domain.Author, int64, *errs.AppError) {
+	query := applyListFilters(r.client.Model(&domain.Author{}), list.Filters)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		logger.Error(err.Error())
+		return nil, 0, errs.NewUnexpectedError("Unexpected error from database")
+	}
+
 	authors := []domain.Author{}
-	if err := r.client.Find(&authors).Error; err != nil {
+	if err := applyListPage(query, list).Find(&authors).Error; err != nil {
 		logger.Error(err.Error())
-		return nil, errs.NewUnexpectedError("Unexpected error from database")
+		return nil, 0, errs.NewUnexpectedError("Unexpected error from database")
 	}
 
-	return authors, nil
+	return authors, total, nil
 }
 
 // FindAuthorById find author by ID in database
@@ -58,6 +67,21 @@ func (r AuthorRepositoryGorm) FindAuthorById(id uint) (*domain.Author, *errs.App
 	return author, nil
 }
 
+// FindAuthorByIdWithBooks find author by ID in database, with its books
+func (r AuthorRepositoryGorm) FindAuthorByIdWithBooks(id uint) (*domain.Author, *errs.AppError) {
+	var author *domain.Author
+
+	if err := r.client.Preload("Books", orderByID).Where("id = ?", id).First(&author).Error; err != nil {
+		logger.Error(err.Error())
+		if strings.Contains(err.Error(), "record not found") {
+			return nil, errs.NewNotFoundError(err.Error())
+		}
+		return nil, errs.NewUnexpectedError("Unexpected error from database")
+	}
+
+	return author, nil
+}
+
 // UpdateAuthor update author in database
 func (r AuthorRepositoryGorm) UpdateAuthor(author *domain.Author) (*domain.Author, *errs.AppError) {
 	var result *gorm.DB
@@ -78,20 +102,58 @@ func (r AuthorRepositoryGorm) UpdateAuthor(author *domain.Author) (*domain.Autho
 	return author, nil
 }
 
-// DeleteAuthor delete author in database
-func (r AuthorRepositoryGorm) DeleteAuthor(id uint) *errs.AppError {
-	var author *domain.Author
-	var result *gorm.DB
-	if result = r.client.Where("id = ?", id).Delete(&author); result.Error != nil {
-		logger.Error(result.Error.Error())
-		return errs.NewUnexpectedError("Unexpected error from database")
-	}
+// DeleteAuthor delete author in database applying the policy to its books in a single transaction, the books
+// are locked so the ones returned are the ones deleted or reassigned
+func (r AuthorRepositoryGorm) DeleteAuthor(id uint, policy string, reassignTo uint) ([]domain.Book, *errs.AppError) {
+	var appErr *errs.AppError
+	var books []domain.Book
+	err := r.client.Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("author_id = ?", id).Order("id").Find(&books)
+		if result.Error != nil {
+			return result.Error
+		}
 
-	// validates if the rows have changed
-	if result.RowsAffected < 1 {
-		logger.Info(fmt.Sprintf("Row with id=%d cannot be deleted because it doesn't exist", id))
-		return errs.NewNotFoundError("Author not found")
+		ids := make([]uint, 0, len(books))
+		for _, book := range books {
+			ids = append(ids, book.ID)
+		}
+		switch {
+		case len(ids) == 0:
+		case policy == domain.AuthorDeleteCascade:
+			result = tx.Where("id IN ?", ids).Delete(&domain.Book{})
+		case policy == domain.AuthorDeleteReassign:
+			result = tx.Model(&domain.Book{}).Where("id IN ?", ids).Update("author_id", reassignTo)
+		default:
+			appErr = errs.NewConflictError(fmt.Sprintf("Author has %d books, delete or reassign them first", len(ids)))
+			return gorm.ErrInvalidTransaction
+		}
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result = tx.Where("id = ?", id).Delete(&domain.Author{}); result.Error != nil {
+			return result.Error
+		}
+
+		// validates if the rows have changed
+		if result.RowsAffected < 1 {
+			logger.Info(fmt.Sprintf("Row with id=%d cannot be deleted because it doesn't exist", id))
+			appErr = errs.NewNotFoundError("Author not found")
+			return gorm.ErrRecordNotFound
+		}
+
+		return nil
+	})
+	if appErr != nil {
+		return nil, appErr
+	}
+	if err != nil {
+		logger.Error(err.Error())
+		if strings.Contains(err.Error(), "violates foreign key constraint") {
+			return nil, errs.NewValidationError(fmt.Sprintf("reassign_to %d does not exist", reassignTo))
+		}
+		return nil, errs.NewUnexpectedError("Unexpected error from database")
 	}
 
-	return nil
+	return books, nil
 }
@@ -23,28 +23,58 @@ func NewBookRepositoryGorm(dbClient *gorm.DB) BookRepositoryGorm {
 func (r BookRepositoryGorm) SaveBook(book *domain.Book) *errs.AppError {
 	if err := r.client.Create(book).Error; err != nil {
 		logger.Error(err.Error())
+		if strings.Contains(err.Error(), "violates foreign key constraint") {
+			return errs.NewValidationError(fmt.Sprintf("author_id %d does not exist", book.AuthorID))
+		}
+		if strings.Contains(err.Error(), "duplicate key value violates unique constraint") && book.ISBN13 != nil {
+			return errs.NewConflictError(fmt.Sprintf("isbn %s already exists", *book.ISBN13))
+		}
 		return errs.NewUnexpectedError("Unexpected error from database")
 	}
 
 	return nil
 }
 
-// FindAllBook find all book in database
-func (r BookRepositoryGorm) FindAllBook() ([]domain.Book, *errs.AppError) {
+// FindAllBook find a page of books matching the filters in database with their author, returns the total of matches
+func (r BookRepositoryGorm) FindAllBook(list domain.ListQuery) ([]domain.Book, int64, *errs.AppError) {
+	query := applyListFilters(r.client.Model(&domain.Book{}), list.Filters)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		logger.Error(err.Error())
+		return nil, 0, errs.NewUnexpectedError("Unexpected error from database")
+	}
+
 	Books := []domain.Book{}
-	if err := r.client.Find(&Books).Error; err != nil {
+	// the authors of the page are loaded with a single query
+	if err := applyListPage(query, list).Preload("Author").Find(&Books).Error; err != nil {
 		logger.Error(err.Error())
-		return nil, errs.NewUnexpectedError("Unexpected error from database")
+		return nil, 0, errs.NewUnexpectedError("Unexpected error from database")
 	}
 
-	return Books, nil
+	return Books, total, nil
 }
 
-// FindBookById find book by ID in database
+// FindBookById find book by ID in database with its author
 func (r BookRepositoryGorm) FindBookById(id uint) (*domain.Book, *errs.AppError) {
 	var Book *domain.Book
 
-	if err := r.client.Where("id = ?", id).First(&Book).Error; err != nil {
+	if err := r.client.Preload("Author").Where("id = ?", id).First(&Book).Error; err != nil {
+		logger.Error(err.Error())
+		if strings.Contains(err.Error(), "record not found") {
+			return nil, errs.NewNotFoundError(err.Error())
+		}
+		return nil, errs.NewUnexpectedError("Unexpected error from database")
+	}
+
+	return Book, nil
+}
+
+// FindBookByISBN13 find book by normalized ISBN-13 in database with its author
+func (r BookRepositoryGorm) FindBookByISBN13(isbn13 string) (*domain.Book, *errs.AppError) {
+	var Book *domain.Book
+
+	if err := r.client.Preload("Author").Where("isbn13 = ?", isbn13).First(&Book).Error; err != nil {
 		logger.Error(err.Error())
 		if strings.Contains(err.Error(), "record not found") {
 			return nil, errs.NewNotFoundError(err.Error())
@@ -58,8 +88,16 @@ func (r BookRepositoryGorm) FindBookById(id uint) (*domain.Book, *errs.AppError)
 // UpdateBook update book in database
 func (r BookRepositoryGorm) UpdateBook(Book *domain.Book) (*domain.Book, *errs.AppError) {
 	var result *gorm.DB
-	if result = r.client.Where("id = ?", Book.ID).Updates(&Book); result.Error != nil {
+	// the ISBN columns are replaced too, a book can lose its ISBN-10 or its ISBN
+	columns := []string{"title", "publication_year", "author_id", "isbn13", "isbn10", "updated_at"}
+	if result = r.client.Where("id = ?", Book.ID).Select(columns).Updates(&Book); result.Error != nil {
 		logger.Error(result.Error.Error())
+		if strings.Contains(result.Error.Error(), "violates foreign key constraint") {
+			return nil, errs.NewValidationError(fmt.Sprintf("author_id %d does not exist", Book.AuthorID))
+		}
+		if strings.Contains(result.Error.Error(), "duplicate key value violates unique constraint") && Book.ISBN13 != nil {
+			return nil, errs.NewConflictError(fmt.Sprintf("isbn %s already exists", *Book.ISBN13))
+		}
 		return nil, errs.NewUnexpectedError("Unexpected error from database")
 	}
 
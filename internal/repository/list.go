@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/karlbehrensg/go-fiber-template/internal/domain"
+	"gorm.io/gorm"
+)
+
+// applyListFilters add the filters of the list to the query
+func applyListFilters(query *gorm.DB, filters []domain.ListFilter) *gorm.DB {
+	for _, filter := range filters {
+		column := listColumn(filter.Column, filter.Integer)
+		switch filter.Operator {
+		case domain.ListOperatorGreaterEqual:
+			query = query.Where(fmt.Sprintf("%s >= ?", column), filter.Value)
+		case domain.ListOperatorLessEqual:
+			query = query.Where(fmt.Sprintf("%s <= ?", column), filter.Value)
+		case domain.ListOperatorContains:
+			query = query.Where(fmt.Sprintf("LOWER(%s) LIKE ? ESCAPE '\\'", column), containsPattern(fmt.Sprint(filter.Value)))
+		default:
+			query = query.Where(fmt.Sprintf("%s = ?", column), filter.Value)
+		}
+	}
+
+	return query
+}
+
+// containsPattern lowercase LIKE pattern matching the value anywhere, the wildcards typed by the user are escaped
+// and must be used with ESCAPE '\'
+func containsPattern(value string) string {
+	escaped := strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(strings.ToLower(value))
+	return "%" + escaped + "%"
+}
+
+// applyListPage add the order, the page and the associations of the list to the query, the rows after the cursor
+// are (a > x) OR (a = x AND b > y) OR ..., with < for descending columns
+func applyListPage(query *gorm.DB, list domain.ListQuery) *gorm.DB {
+	for _, association := range list.Include {
+		query = query.Preload(association, orderByID)
+	}
+
+	order := make([]string, 0, len(list.Sort))
+	for _, sort := range list.Sort {
+		if sort.Desc {
+			order = append(order, listColumn(sort.Column, sort.Integer)+" DESC")
+		} else {
+			order = append(order, listColumn(sort.Column, sort.Integer))
+		}
+	}
+	query = query.Order(strings.Join(order, ", "))
+
+	if list.After == nil {
+		return query.Offset(list.Offset).Limit(list.Limit)
+	}
+
+	conditions := make([]string, 0, len(list.Sort))
+	args := make([]interface{}, 0)
+	for i, sort := range list.Sort {
+		terms := make([]string, 0, i+1)
+		for j := 0; j < i; j++ {
+			terms = append(terms, fmt.Sprintf("%s = ?", listColumn(list.Sort[j].Column, list.Sort[j].Integer)))
+			args = append(args, list.After.Values[j])
+		}
+		operator := ">"
+		if sort.Desc {
+			operator = "<"
+		}
+		terms = append(terms, fmt.Sprintf("%s %s ?", listColumn(sort.Column, sort.Integer), operator))
+		args = append(args, list.After.Values[i])
+		conditions = append(conditions, "("+strings.Join(terms, " AND ")+")")
+	}
+
+	return query.Where("("+strings.Join(conditions, " OR ")+")", args...).Limit(list.Limit)
+}
+
+// listColumn expression of the column in the query, text holding whole numbers is cast so "999" comes before
+// "1984", text that is not a whole number is 0 as in Book.ListValue
+func listColumn(column string, integer bool) string {
+	if !integer {
+		return column
+	}
+
+	return fmt.Sprintf("COALESCE(CAST(SUBSTRING(%s FROM '^[0-9]{1,9}$') AS INTEGER), 0)", column)
+}
+
+// orderByID keep the preloaded associations in a stable order
+func orderByID(query *gorm.DB) *gorm.DB {
+	return query.Order("id")
+}
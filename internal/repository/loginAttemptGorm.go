@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"strings"
+	"time"
+
+	"github.com/karlbehrensg/go-fiber-template/internal/domain"
+	"github.com/karlbehrensg/go-fiber-template/pkg/errs"
+	"github.com/karlbehrensg/go-fiber-template/pkg/logger"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type LoginAttemptRepositoryGorm struct {
+	client *gorm.DB
+}
+
+// NewLoginAttemptRepositoryGorm create a new instance of LoginAttemptRepositoryGorm
+func NewLoginAttemptRepositoryGorm(dbClient *gorm.DB) LoginAttemptRepositoryGorm {
+	return LoginAttemptRepositoryGorm{dbClient}
+}
+
+// FindLoginAttempt find login attempts by key in database
+func (r LoginAttemptRepositoryGorm) FindLoginAttempt(key string) (*domain.LoginAttempt, *errs.AppError) {
+	var attempt *domain.LoginAttempt
+
+	if err := r.client.Where("key = ?", key).First(&attempt).Error; err != nil {
+		if strings.Contains(err.Error(), "record not found") {
+			return nil, errs.NewNotFoundError(err.Error())
+		}
+		logger.Error(err.Error())
+		return nil, errs.NewUnexpectedError("Unexpected error from database")
+	}
+
+	return attempt, nil
+}
+
+// RegisterFailedLogin increment the failures of the key in database
+func (r LoginAttemptRepositoryGorm) RegisterFailedLogin(key string, window time.Duration) (*domain.LoginAttempt, *errs.AppError) {
+	now := time.Now()
+	attempt := &domain.LoginAttempt{Key: key, Failures: 1, LastFailure: now}
+
+	// the increment is done by the database so concurrent failures are not lost
+	if err := r.client.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "key"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"failures": gorm.Expr(
+				"CASE WHEN login_attempts.last_failure < ? THEN 1 ELSE login_attempts.failures + 1 END",
+				now.Add(-window),
+			),
+			"last_failure": now,
+			"updated_at":   now,
+		}),
+	}).Create(attempt).Error; err != nil {
+		logger.Error(err.Error())
+		return nil, errs.NewUnexpectedError("Unexpected error from database")
+	}
+
+	return r.FindLoginAttempt(key)
+}
+
+// LockLoginAttempt lock the key until the given time in database
+func (r LoginAttemptRepositoryGorm) LockLoginAttempt(key string, until time.Time) *errs.AppError {
+	if err := r.client.Model(&domain.LoginAttempt{}).Where("key = ?", key).Update("locked_until", until).Error; err != nil {
+		logger.Error(err.Error())
+		return errs.NewUnexpectedError("Unexpected error from database")
+	}
+
+	return nil
+}
+
+// ResetLoginAttempts delete the failures of the key in database
+func (r LoginAttemptRepositoryGorm) ResetLoginAttempts(key string) *errs.AppError {
+	if err := r.client.Where("key = ?", key).Delete(&domain.LoginAttempt{}).Error; err != nil {
+		logger.Error(err.Error())
+		return errs.NewUnexpectedError("Unexpected error from database")
+	}
+
+	return nil
+}
@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"sync"
+	"time"
+
+	"github.com/karlbehrensg/go-fiber-template/internal/domain"
+	"github.com/karlbehrensg/go-fiber-template/pkg/errs"
+)
+
+type LoginAttemptRepositoryMemory struct {
+	mu       *sync.Mutex
+	attempts map[string]domain.LoginAttempt
+}
+
+// NewLoginAttemptRepositoryMemory create a new instance of LoginAttemptRepositoryMemory,
+// stale attempts are purged every purgeInterval
+func NewLoginAttemptRepositoryMemory(purgeInterval time.Duration, maxAge time.Duration) LoginAttemptRepositoryMemory {
+	r := LoginAttemptRepositoryMemory{
+		mu:       &sync.Mutex{},
+		attempts: map[string]domain.LoginAttempt{},
+	}
+	go r.purge(purgeInterval, maxAge)
+
+	return r
+}
+
+// FindLoginAttempt find login attempts by key in memory
+func (r LoginAttemptRepositoryMemory) FindLoginAttempt(key string) (*domain.LoginAttempt, *errs.AppError) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	attempt, ok := r.attempts[key]
+	if !ok {
+		return nil, errs.NewNotFoundError("record not found")
+	}
+
+	return &attempt, nil
+}
+
+// RegisterFailedLogin increment the failures of the key in memory
+func (r LoginAttemptRepositoryMemory) RegisterFailedLogin(key string, window time.Duration) (*domain.LoginAttempt, *errs.AppError) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	attempt, ok := r.attempts[key]
+	if !ok || attempt.LastFailure.Before(now.Add(-window)) {
+		attempt.Failures = 0
+	}
+	attempt.Key = key
+	attempt.Failures++
+	attempt.LastFailure = now
+	attempt.UpdatedAt = now
+	r.attempts[key] = attempt
+
+	return &attempt, nil
+}
+
+// LockLoginAttempt lock the key until the given time in memory
+func (r LoginAttemptRepositoryMemory) LockLoginAttempt(key string, until time.Time) *errs.AppError {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if attempt, ok := r.attempts[key]; ok {
+		attempt.LockedUntil = &until
+		r.attempts[key] = attempt
+	}
+
+	return nil
+}
+
+// ResetLoginAttempts delete the failures of the key in memory
+func (r LoginAttemptRepositoryMemory) ResetLoginAttempts(key string) *errs.AppError {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.attempts, key)
+
+	return nil
+}
+
+// purge delete the attempts without failures nor lock in the last maxAge
+func (r LoginAttemptRepositoryMemory) purge(interval time.Duration, maxAge time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		r.mu.Lock()
+		now := time.Now()
+		for key, attempt := range r.attempts {
+			if attempt.LastFailure.Before(now.Add(-maxAge)) && (attempt.LockedUntil == nil || now.After(*attempt.LockedUntil)) {
+				delete(r.attempts, key)
+			}
+		}
+		r.mu.Unlock()
+	}
+}
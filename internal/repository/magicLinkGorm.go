@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/karlbehrensg/go-fiber-template/internal/domain"
+	"github.com/karlbehrensg/go-fiber-template/pkg/errs"
+	"github.com/karlbehrensg/go-fiber-template/pkg/logger"
+	"gorm.io/gorm"
+)
+
+type MagicLinkTokenRepositoryGorm struct {
+	client *gorm.DB
+}
+
+// NewMagicLinkTokenRepositoryGorm create a new instance of MagicLinkTokenRepositoryGorm
+func NewMagicLinkTokenRepositoryGorm(dbClient *gorm.DB) MagicLinkTokenRepositoryGorm {
+	return MagicLinkTokenRepositoryGorm{dbClient}
+}
+
+// SaveMagicLinkToken save magic link token in database
+func (r MagicLinkTokenRepositoryGorm) SaveMagicLinkToken(token *domain.MagicLinkToken) *errs.AppError {
+	if err := r.client.Create(token).Error; err != nil {
+		logger.Error(err.Error())
+		return errs.NewUnexpectedError("Unexpected error from database")
+	}
+
+	return nil
+}
+
+// FindMagicLinkTokenByHash find magic link token by hash in database
+func (r MagicLinkTokenRepositoryGorm) FindMagicLinkTokenByHash(hash string) (*domain.MagicLinkToken, *errs.AppError) {
+	var token *domain.MagicLinkToken
+
+	if err := r.client.Where("token_hash = ?", hash).First(&token).Error; err != nil {
+		logger.Error(err.Error())
+		if strings.Contains(err.Error(), "record not found") {
+			return nil, errs.NewNotFoundError(err.Error())
+		}
+		return nil, errs.NewUnexpectedError("Unexpected error from database")
+	}
+
+	return token, nil
+}
+
+// ConsumeMagicLinkToken mark the magic link token as used in database, a token can only be used once
+func (r MagicLinkTokenRepositoryGorm) ConsumeMagicLinkToken(id uint) *errs.AppError {
+	var result *gorm.DB
+	if result = r.client.Model(&domain.MagicLinkToken{}).
+		Where("id = ? AND used_at IS NULL", id).
+		Update("used_at", time.Now()); result.Error != nil {
+		logger.Error(result.Error.Error())
+		return errs.NewUnexpectedError("Unexpected error from database")
+	}
+
+	// validates if the rows have changed
+	if result.RowsAffected < 1 {
+		logger.Info(fmt.Sprintf("Magic link token with id=%d was already used", id))
+		return errs.NewAuthenticationError("invalid or expired magic link")
+	}
+
+	return nil
+}
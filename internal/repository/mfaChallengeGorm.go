@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/karlbehrensg/go-fiber-template/internal/domain"
+	"github.com/karlbehrensg/go-fiber-template/pkg/errs"
+	"github.com/karlbehrensg/go-fiber-template/pkg/logger"
+	"gorm.io/gorm"
+)
+
+type MFAChallengeRepositoryGorm struct {
+	client *gorm.DB
+}
+
+// NewMFAChallengeRepositoryGorm create a new instance of MFAChallengeRepositoryGorm
+func NewMFAChallengeRepositoryGorm(dbClient *gorm.DB) MFAChallengeRepositoryGorm {
+	return MFAChallengeRepositoryGorm{dbClient}
+}
+
+// SaveMFAChallenge save MFA challenge in database
+func (r MFAChallengeRepositoryGorm) SaveMFAChallenge(challenge *domain.MFAChallenge) *errs.AppError {
+	if err := r.client.Create(challenge).Error; err != nil {
+		logger.Error(err.Error())
+		return errs.NewUnexpectedError("Unexpected error from database")
+	}
+
+	return nil
+}
+
+// FindMFAChallengeByHash find MFA challenge by nonce hash in database
+func (r MFAChallengeRepositoryGorm) FindMFAChallengeByHash(hash string) (*domain.MFAChallenge, *errs.AppError) {
+	var challenge *domain.MFAChallenge
+
+	if err := r.client.Where("nonce_hash = ?", hash).First(&challenge).Error; err != nil {
+		logger.Error(err.Error())
+		if strings.Contains(err.Error(), "record not found") {
+			return nil, errs.NewNotFoundError(err.Error())
+		}
+		return nil, errs.NewUnexpectedError("Unexpected error from database")
+	}
+
+	return challenge, nil
+}
+
+// ConsumeMFAChallenge mark the MFA challenge as used in database, a challenge can only be used once
+func (r MFAChallengeRepositoryGorm) ConsumeMFAChallenge(id uint) *errs.AppError {
+	var result *gorm.DB
+	if result = r.client.Model(&domain.MFAChallenge{}).
+		Where("id = ? AND used_at IS NULL", id).
+		Update("used_at", time.Now()); result.Error != nil {
+		logger.Error(result.Error.Error())
+		return errs.NewUnexpectedError("Unexpected error from database")
+	}
+
+	// validates if the rows have changed
+	if result.RowsAffected < 1 {
+		logger.Info(fmt.Sprintf("MFA challenge with id=%d was already used", id))
+		return errs.NewAuthenticationError("invalid or expired MFA challenge")
+	}
+
+	return nil
+}
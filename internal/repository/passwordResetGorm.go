@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/karlbehrensg/go-fiber-template/internal/domain"
+	"github.com/karlbehrensg/go-fiber-template/pkg/errs"
+	"github.com/karlbehrensg/go-fiber-template/pkg/logger"
+	"gorm.io/gorm"
+)
+
+type PasswordResetTokenRepositoryGorm struct {
+	client *gorm.DB
+}
+
+// NewPasswordResetTokenRepositoryGorm create a new instance of PasswordResetTokenRepositoryGorm
+func NewPasswordResetTokenRepositoryGorm(dbClient *gorm.DB) PasswordResetTokenRepositoryGorm {
+	return PasswordResetTokenRepositoryGorm{dbClient}
+}
+
+// SavePasswordResetToken save reset token in database
+func (r PasswordResetTokenRepositoryGorm) SavePasswordResetToken(token *domain.PasswordResetToken) *errs.AppError {
+	if err := r.client.Create(token).Error; err != nil {
+		logger.Error(err.Error())
+		return errs.NewUnexpectedError("Unexpected error from database")
+	}
+
+	return nil
+}
+
+// FindPasswordResetTokenByHash find reset token by hash in database
+func (r PasswordResetTokenRepositoryGorm) FindPasswordResetTokenByHash(hash string) (*domain.PasswordResetToken, *errs.AppError) {
+	var token *domain.PasswordResetToken
+
+	if err := r.client.Where("token_hash = ?", hash).First(&token).Error; err != nil {
+		logger.Error(err.Error())
+		if strings.Contains(err.Error(), "record not found") {
+			return nil, errs.NewNotFoundError(err.Error())
+		}
+		return nil, errs.NewUnexpectedError("Unexpected error from database")
+	}
+
+	return token, nil
+}
+
+// ConsumePasswordResetToken mark the reset token as used and change the password of its user in a single
+// transaction, a token can only be used once
+func (r PasswordResetTokenRepositoryGorm) ConsumePasswordResetToken(token *domain.PasswordResetToken, hash string) *errs.AppError {
+	var appErr *errs.AppError
+	err := r.client.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&domain.PasswordResetToken{}).
+			Where("id = ? AND used_at IS NULL", token.ID).
+			Update("used_at", time.Now())
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected < 1 {
+			logger.Info(fmt.Sprintf("Reset token with id=%d was already used", token.ID))
+			appErr = errs.NewBadRequestError("invalid or expired reset token")
+			return gorm.ErrInvalidTransaction
+		}
+
+		// the token version is increased, access tokens issued before are rejected
+		result = tx.Model(&domain.User{}).Where("id = ?", token.UserID).Updates(map[string]interface{}{
+			"password":      hash,
+			"token_version": gorm.Expr("token_version + 1"),
+		})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected < 1 {
+			logger.Info(fmt.Sprintf("Row with id=%d cannot be updated because it doesn't exist", token.UserID))
+			appErr = errs.NewNotFoundError("User not found")
+			return gorm.ErrInvalidTransaction
+		}
+
+		return nil
+	})
+	if appErr != nil {
+		return appErr
+	}
+	if err != nil {
+		logger.Error(err.Error())
+		return errs.NewUnexpectedError("Unexpected error from database")
+	}
+
+	return nil
+}
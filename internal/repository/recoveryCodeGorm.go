@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/karlbehrensg/go-fiber-template/internal/domain"
+	"github.com/karlbehrensg/go-fiber-template/pkg/errs"
+	"github.com/karlbehrensg/go-fiber-template/pkg/logger"
+	"gorm.io/gorm"
+)
+
+type RecoveryCodeRepositoryGorm struct {
+	client *gorm.DB
+}
+
+// NewRecoveryCodeRepositoryGorm create a new instance of RecoveryCodeRepositoryGorm
+func NewRecoveryCodeRepositoryGorm(dbClient *gorm.DB) RecoveryCodeRepositoryGorm {
+	return RecoveryCodeRepositoryGorm{dbClient}
+}
+
+// ReplaceRecoveryCodes delete the previous recovery codes of the user and save the new ones in database
+func (r RecoveryCodeRepositoryGorm) ReplaceRecoveryCodes(userID uint, codes []*domain.RecoveryCode) *errs.AppError {
+	err := r.client.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&domain.RecoveryCode{}).Error; err != nil {
+			return err
+		}
+		return tx.Create(codes).Error
+	})
+	if err != nil {
+		logger.Error(err.Error())
+		return errs.NewUnexpectedError("Unexpected error from database")
+	}
+
+	return nil
+}
+
+// ConsumeRecoveryCode mark the recovery code as used in database, a code can only be used once
+func (r RecoveryCodeRepositoryGorm) ConsumeRecoveryCode(userID uint, hash string) *errs.AppError {
+	var result *gorm.DB
+	if result = r.client.Model(&domain.RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, hash).
+		Update("used_at", time.Now()); result.Error != nil {
+		logger.Error(result.Error.Error())
+		return errs.NewUnexpectedError("Unexpected error from database")
+	}
+
+	// validates if the rows have changed
+	if result.RowsAffected < 1 {
+		logger.Info(fmt.Sprintf("Recovery code of user id=%d not found or already used", userID))
+		return errs.NewNotFoundError("record not found")
+	}
+
+	return nil
+}
+
+// DeleteRecoveryCodes delete every recovery code of the user in database
+func (r RecoveryCodeRepositoryGorm) DeleteRecoveryCodes(userID uint) *errs.AppError {
+	if err := r.client.Where("user_id = ?", userID).Delete(&domain.RecoveryCode{}).Error; err != nil {
+		logger.Error(err.Error())
+		return errs.NewUnexpectedError("Unexpected error from database")
+	}
+
+	return nil
+}
@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/karlbehrensg/go-fiber-template/internal/domain"
+	"github.com/karlbehrensg/go-fiber-template/pkg/errs"
+	"github.com/karlbehrensg/go-fiber-template/pkg/logger"
+	"gorm.io/gorm"
+)
+
+type RefreshTokenRepositoryGorm struct {
+	client *gorm.DB
+}
+
+// NewRefreshTokenRepositoryGorm create a new instance of RefreshTokenRepositoryGorm
+func NewRefreshTokenRepositoryGorm(dbClient *gorm.DB) RefreshTokenRepositoryGorm {
+	return RefreshTokenRepositoryGorm{dbClient}
+}
+
+// SaveRefreshToken save refresh token in database
+func (r RefreshTokenRepositoryGorm) SaveRefreshToken(token *domain.RefreshToken) *errs.AppError {
+	if err := r.client.Create(token).Error; err != nil {
+		logger.Error(err.Error())
+		return errs.NewUnexpectedError("Unexpected error from database")
+	}
+
+	return nil
+}
+
+// FindRefreshTokenByHash find refresh token by hash in database
+func (r RefreshTokenRepositoryGorm) FindRefreshTokenByHash(hash string) (*domain.RefreshToken, *errs.AppError) {
+	var token *domain.RefreshToken
+
+	if err := r.client.Where("token_hash = ?", hash).First(&token).Error; err != nil {
+		logger.Error(err.Error())
+		if strings.Contains(err.Error(), "record not found") {
+			return nil, errs.NewNotFoundError(err.Error())
+		}
+		return nil, errs.NewUnexpectedError("Unexpected error from database")
+	}
+
+	return token, nil
+}
+
+// RotateRefreshToken mark the old refresh token as rotated and save the new one in a single transaction
+func (r RefreshTokenRepositoryGorm) RotateRefreshToken(old *domain.RefreshToken, new *domain.RefreshToken) *errs.AppError {
+	var appErr *errs.AppError
+	err := r.client.Transaction(func(tx *gorm.DB) error {
+		// only one request can rotate the token, a concurrent one is a reuse
+		result := tx.Model(&domain.RefreshToken{}).
+			Where("id = ? AND rotated_at IS NULL AND revoked_at IS NULL", old.ID).
+			Update("rotated_at", time.Now())
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected < 1 {
+			logger.Info(fmt.Sprintf("Refresh token with id=%d was already rotated", old.ID))
+			appErr = errs.NewAuthenticationError("refresh token reuse detected")
+			return gorm.ErrInvalidTransaction
+		}
+
+		return tx.Create(new).Error
+	})
+	if appErr != nil {
+		return appErr
+	}
+	if err != nil {
+		logger.Error(err.Error())
+		return errs.NewUnexpectedError("Unexpected error from database")
+	}
+
+	return nil
+}
+
+// RevokeRefreshTokenFamily revoke every refresh token of the family in database
+func (r RefreshTokenRepositoryGorm) RevokeRefreshTokenFamily(familyID string) *errs.AppError {
+	if err := r.client.Model(&domain.RefreshToken{}).
+		Where("family_id = ? AND revoked_at IS NULL", familyID).
+		Update("revoked_at", time.Now()).Error; err != nil {
+		logger.Error(err.Error())
+		return errs.NewUnexpectedError("Unexpected error from database")
+	}
+
+	return nil
+}
+
+// RevokeUserRefreshTokens revoke every refresh token of the user in database
+func (r RefreshTokenRepositoryGorm) RevokeUserRefreshTokens(userID uint) *errs.AppError {
+	if err := r.client.Model(&domain.RefreshToken{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", time.Now()).Error; err != nil {
+		logger.Error(err.Error())
+		return errs.NewUnexpectedError("Unexpected error from database")
+	}
+
+	return nil
+}
@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/karlbehrensg/go-fiber-template/internal/domain"
+	"github.com/karlbehrensg/go-fiber-template/pkg/errs"
+	"github.com/karlbehrensg/go-fiber-template/pkg/logger"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type RevokedTokenRepositoryGorm struct {
+	client *gorm.DB
+}
+
+// NewRevokedTokenRepositoryGorm create a new instance of RevokedTokenRepositoryGorm
+func NewRevokedTokenRepositoryGorm(dbClient *gorm.DB) RevokedTokenRepositoryGorm {
+	return RevokedTokenRepositoryGorm{dbClient}
+}
+
+// SaveRevokedToken save revoked token in database
+func (r RevokedTokenRepositoryGorm) SaveRevokedToken(token *domain.RevokedToken) *errs.AppError {
+	// revoking the same token twice is not an error
+	if err := r.client.Clauses(clause.OnConflict{DoNothing: true}).Create(token).Error; err != nil {
+		logger.Error(err.Error())
+		return errs.NewUnexpectedError("Unexpected error from database")
+	}
+
+	return nil
+}
+
+// IsTokenRevoked validate if the token ID was revoked in database
+func (r RevokedTokenRepositoryGorm) IsTokenRevoked(jti string) (bool, *errs.AppError) {
+	var count int64
+	if err := r.client.Model(&domain.RevokedToken{}).
+		Where("jti = ? AND expires_at > ?", jti, time.Now()).
+		Count(&count).Error; err != nil {
+		logger.Error(err.Error())
+		return false, errs.NewUnexpectedError("Unexpected error from database")
+	}
+
+	return count > 0, nil
+}
+
+// DeleteExpiredRevokedTokens delete revoked tokens that are already expired in database
+func (r RevokedTokenRepositoryGorm) DeleteExpiredRevokedTokens() *errs.AppError {
+	var result *gorm.DB
+	if result = r.client.Where("expires_at <= ?", time.Now()).Delete(&domain.RevokedToken{}); result.Error != nil {
+		logger.Error(result.Error.Error())
+		return errs.NewUnexpectedError("Unexpected error from database")
+	}
+
+	if result.RowsAffected > 0 {
+		logger.Info(fmt.Sprintf("%d expired revoked tokens purged", result.RowsAffected))
+	}
+
+	return nil
+}
@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"sync"
+	"time"
+
+	"github.com/karlbehrensg/go-fiber-template/internal/domain"
+	"github.com/karlbehrensg/go-fiber-template/pkg/errs"
+)
+
+type RevokedTokenRepositoryMemory struct {
+	mu     *sync.RWMutex
+	tokens map[string]time.Time
+}
+
+// NewRevokedTokenRepositoryMemory create a new instance of RevokedTokenRepositoryMemory
+func NewRevokedTokenRepositoryMemory() RevokedTokenRepositoryMemory {
+	return RevokedTokenRepositoryMemory{
+		mu:     &sync.RWMutex{},
+		tokens: map[string]time.Time{},
+	}
+}
+
+// SaveRevokedToken save revoked token in memory
+func (r RevokedTokenRepositoryMemory) SaveRevokedToken(token *domain.RevokedToken) *errs.AppError {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.tokens[token.JTI] = token.ExpiresAt
+
+	return nil
+}
+
+// IsTokenRevoked validate if the token ID was revoked in memory
+func (r RevokedTokenRepositoryMemory) IsTokenRevoked(jti string) (bool, *errs.AppError) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	expiresAt, ok := r.tokens[jti]
+
+	return ok && time.Now().Before(expiresAt), nil
+}
+
+// DeleteExpiredRevokedTokens delete revoked tokens that are already expired in memory
+func (r RevokedTokenRepositoryMemory) DeleteExpiredRevokedTokens() *errs.AppError {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	for jti, expiresAt := range r.tokens {
+		if !now.Before(expiresAt) {
+			delete(r.tokens, jti)
+		}
+	}
+
+	return nil
+}
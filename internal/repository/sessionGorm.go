@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/karlbehrensg/go-fiber-template/internal/domain"
+	"github.com/karlbehrensg/go-fiber-template/pkg/errs"
+	"github.com/karlbehrensg/go-fiber-template/pkg/logger"
+	"gorm.io/gorm"
+)
+
+type SessionRepositoryGorm struct {
+	client *gorm.DB
+}
+
+// NewSessionRepositoryGorm create a new instance of SessionRepositoryGorm
+func NewSessionRepositoryGorm(dbClient *gorm.DB) SessionRepositoryGorm {
+	return SessionRepositoryGorm{dbClient}
+}
+
+// SaveSession save session in database
+func (r SessionRepositoryGorm) SaveSession(session *domain.Session) *errs.AppError {
+	if err := r.client.Create(session).Error; err != nil {
+		logger.Error(err.Error())
+		return errs.NewUnexpectedError("Unexpected error from database")
+	}
+
+	return nil
+}
+
+// FindSessionById find session by ID in database
+func (r SessionRepositoryGorm) FindSessionById(id uint) (*domain.Session, *errs.AppError) {
+	var session *domain.Session
+
+	if err := r.client.Where("id = ?", id).First(&session).Error; err != nil {
+		logger.Error(err.Error())
+		if strings.Contains(err.Error(), "record not found") {
+			return nil, errs.NewNotFoundError(err.Error())
+		}
+		return nil, errs.NewUnexpectedError("Unexpected error from database")
+	}
+
+	return session, nil
+}
+
+// FindSessionByFamily find the session owning the refresh token family in database
+func (r SessionRepositoryGorm) FindSessionByFamily(familyID string) (*domain.Session, *errs.AppError) {
+	var session *domain.Session
+
+	if err := r.client.Where("family_id = ?", familyID).First(&session).Error; err != nil {
+		logger.Error(err.Error())
+		if strings.Contains(err.Error(), "record not found") {
+			return nil, errs.NewNotFoundError(err.Error())
+		}
+		return nil, errs.NewUnexpectedError("Unexpected error from database")
+	}
+
+	return session, nil
+}
+
+// FindActiveSessionsByUser find the sessions of the user not signed out in database, most recent first
+func (r SessionRepositoryGorm) FindActiveSessionsByUser(userID uint) ([]domain.Session, *errs.AppError) {
+	var sessions []domain.Session
+
+	if err := r.client.Where("user_id = ? AND revoked_at IS NULL", userID).
+		Order("last_seen_at DESC").Find(&sessions).Error; err != nil {
+		logger.Error(err.Error())
+		return nil, errs.NewUnexpectedError("Unexpected error from database")
+	}
+
+	return sessions, nil
+}
+
+// TouchSession update the last time and address the session was used in database
+func (r SessionRepositoryGorm) TouchSession(id uint, ip string, lastSeenAt time.Time) *errs.AppError {
+	if err := r.client.Model(&domain.Session{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"ip":           ip,
+		"last_seen_at": lastSeenAt,
+	}).Error; err != nil {
+		logger.Error(err.Error())
+		return errs.NewUnexpectedError("Unexpected error from database")
+	}
+
+	return nil
+}
+
+// RevokeSession mark the session of the user as signed out in database
+func (r SessionRepositoryGorm) RevokeSession(userID uint, id uint) *errs.AppError {
+	var result *gorm.DB
+	if result = r.client.Model(&domain.Session{}).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL", id, userID).
+		Update("revoked_at", time.Now()); result.Error != nil {
+		logger.Error(result.Error.Error())
+		return errs.NewUnexpectedError("Unexpected error from database")
+	}
+
+	// validates if the rows have changed
+	if result.RowsAffected < 1 {
+		logger.Info(fmt.Sprintf("Session with id=%d not found for user id=%d", id, userID))
+		return errs.NewNotFoundError("record not found")
+	}
+
+	return nil
+}
+
+// RevokeUserSessions mark every session of the user as signed out in database, except the given one
+func (r SessionRepositoryGorm) RevokeUserSessions(userID uint, exceptID uint) *errs.AppError {
+	if err := r.client.Model(&domain.Session{}).
+		Where("user_id = ? AND id <> ? AND revoked_at IS NULL", userID, exceptID).
+		Update("revoked_at", time.Now()).Error; err != nil {
+		logger.Error(err.Error())
+		return errs.NewUnexpectedError("Unexpected error from database")
+	}
+
+	return nil
+}
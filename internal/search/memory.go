@@ -0,0 +1,297 @@
+package search
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/karlbehrensg/go-fiber-template/internal/domain"
+	"github.com/karlbehrensg/go-fiber-template/pkg/errs"
+)
+
+// weight of a term by how it matches the word typed, exact matches rank first
+const (
+	exactWeight  = 1.0
+	prefixWeight = 0.7
+	typoWeight   = 0.5
+)
+
+// MemoryIndex in-process inverted index, it must be rebuilt from the database when the app starts
+type MemoryIndex struct {
+	mu *sync.RWMutex
+	// rebuild only one rebuild runs at a time
+	rebuild *sync.Mutex
+	docs    map[string]*indexedDocument
+	// terms documents containing each term
+	terms map[string]map[string]bool
+	// pending documents indexed or removed while a rebuild loads, nil without rebuild, a nil document is a removal
+	pending map[string]*domain.SearchDocument
+}
+
+// indexedDocument document with the words of its fields
+type indexedDocument struct {
+	doc    domain.SearchDocument
+	tokens map[string][]token
+}
+
+// NewMemoryIndex create a new instance of MemoryIndex
+func NewMemoryIndex() *MemoryIndex {
+	return &MemoryIndex{
+		mu:      &sync.RWMutex{},
+		rebuild: &sync.Mutex{},
+		docs:    map[string]*indexedDocument{},
+		terms:   map[string]map[string]bool{},
+	}
+}
+
+// IndexDocument add the document to the index or replace it
+func (m *MemoryIndex) IndexDocument(doc domain.SearchDocument) *errs.AppError {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := documentKey(doc.Kind, doc.ID)
+	if m.pending != nil {
+		m.pending[key] = &doc
+	}
+	m.remove(key)
+	m.add(doc)
+
+	return nil
+}
+
+// RemoveDocument remove the document from the index, unknown documents are ignored
+func (m *MemoryIndex) RemoveDocument(kind string, id uint) *errs.AppError {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := documentKey(kind, id)
+	if m.pending != nil {
+		m.pending[key] = nil
+	}
+	m.remove(key)
+
+	return nil
+}
+
+// RebuildDocuments swap the content of the index with the loaded documents, searches keep using the old content
+// until it is built and the changes made while loading are applied on top of the new content
+func (m *MemoryIndex) RebuildDocuments(load func() ([]domain.SearchDocument, *errs.AppError)) *errs.AppError {
+	m.rebuild.Lock()
+	defer m.rebuild.Unlock()
+
+	m.mu.Lock()
+	m.pending = map[string]*domain.SearchDocument{}
+	m.mu.Unlock()
+
+	docs, appErr := load()
+	if appErr != nil {
+		m.mu.Lock()
+		m.pending = nil
+		m.mu.Unlock()
+		return appErr
+	}
+
+	index := NewMemoryIndex()
+	for _, doc := range docs {
+		index.remove(documentKey(doc.Kind, doc.ID))
+		index.add(doc)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for key, doc := range m.pending {
+		index.remove(key)
+		if doc != nil {
+			index.add(*doc)
+		}
+	}
+	m.docs, m.terms, m.pending = index.docs, index.terms, nil
+
+	return nil
+}
+
+// Search find the documents matching every word of the query, ranked by score
+func (m *MemoryIndex) Search(query domain.SearchQuery) ([]domain.SearchHit, int, *errs.AppError) {
+	words := uniqueTerms(tokenize(query.Text))
+	if len(words) == 0 {
+		return []domain.SearchHit{}, 0, nil
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var scores map[string]float64
+	matched := map[string]map[string]bool{}
+	for _, word := range words {
+		wordScores := map[string]float64{}
+		for term, weight := range m.expand(word) {
+			idf := 1 + math.Log(float64(len(m.docs))/float64(len(m.terms[term])))
+			for key := range m.terms[term] {
+				indexed := m.docs[key]
+				if !hasKind(query.Kinds, indexed.doc.Kind) {
+					continue
+				}
+				if score := weight * idf * indexed.fieldWeight(term); score > wordScores[key] {
+					wordScores[key] = score
+				}
+				if matched[key] == nil {
+					matched[key] = map[string]bool{}
+				}
+				matched[key][term] = true
+			}
+		}
+
+		// every word must match, the score of the document adds the best match of each one
+		if scores == nil {
+			scores = wordScores
+			continue
+		}
+		for key := range scores {
+			if score, ok := wordScores[key]; ok {
+				scores[key] += score
+			} else {
+				delete(scores, key)
+			}
+		}
+	}
+
+	hits := make([]domain.SearchHit, 0, len(scores))
+	for key, score := range scores {
+		indexed := m.docs[key]
+		hit := domain.SearchHit{
+			Kind:       indexed.doc.Kind,
+			ID:         indexed.doc.ID,
+			Score:      math.Round(score*1000) / 1000,
+			Fields:     indexed.doc.Fields,
+			Highlights: map[string]string{},
+		}
+		for field, tokens := range indexed.tokens {
+			for _, tk := range tokens {
+				if matched[key][tk.term] {
+					hit.Highlights[field] = highlight(indexed.doc.Fields[field], tokens, matched[key])
+					break
+				}
+			}
+		}
+		hits = append(hits, hit)
+	}
+
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		if hits[i].Kind != hits[j].Kind {
+			return hits[i].Kind < hits[j].Kind
+		}
+		return hits[i].ID < hits[j].ID
+	})
+
+	total := len(hits)
+	if query.Limit > 0 && len(hits) > query.Limit {
+		hits = hits[:query.Limit]
+	}
+
+	return hits, total, nil
+}
+
+// expand find the terms of the index matching the word exactly, by prefix or with typos
+func (m *MemoryIndex) expand(word string) map[string]float64 {
+	expanded := map[string]float64{}
+	edits := maxEdits(word)
+	for term := range m.terms {
+		switch {
+		case term == word:
+			expanded[term] = exactWeight
+		case len(word) > 1 && strings.HasPrefix(term, word):
+			expanded[term] = prefixWeight
+		case edits > 0:
+			if d := editDistance(word, term, edits); d <= edits {
+				expanded[term] = typoWeight / float64(d)
+			}
+		}
+	}
+
+	return expanded
+}
+
+// add index the document, it must not be in the index
+func (m *MemoryIndex) add(doc domain.SearchDocument) {
+	key := documentKey(doc.Kind, doc.ID)
+	indexed := &indexedDocument{doc: doc, tokens: map[string][]token{}}
+	for field, text := range doc.Fields {
+		indexed.tokens[field] = tokenize(text)
+		for _, tk := range indexed.tokens[field] {
+			if m.terms[tk.term] == nil {
+				m.terms[tk.term] = map[string]bool{}
+			}
+			m.terms[tk.term][key] = true
+		}
+	}
+	m.docs[key] = indexed
+}
+
+// remove drop the document and the terms left without documents
+func (m *MemoryIndex) remove(key string) {
+	indexed, ok := m.docs[key]
+	if !ok {
+		return
+	}
+
+	for _, tokens := range indexed.tokens {
+		for _, tk := range tokens {
+			delete(m.terms[tk.term], key)
+			if len(m.terms[tk.term]) == 0 {
+				delete(m.terms, tk.term)
+			}
+		}
+	}
+	delete(m.docs, key)
+}
+
+// fieldWeight weight of the best field containing the term, matches in short fields weigh more
+func (d *indexedDocument) fieldWeight(term string) float64 {
+	best := 0.0
+	for _, tokens := range d.tokens {
+		for _, tk := range tokens {
+			if tk.term == term {
+				best = math.Max(best, 1/math.Sqrt(float64(len(tokens))))
+				break
+			}
+		}
+	}
+
+	return best
+}
+
+func documentKey(kind string, id uint) string {
+	return fmt.Sprintf("%s:%d", kind, id)
+}
+
+func uniqueTerms(tokens []token) []string {
+	seen := map[string]bool{}
+	terms := make([]string, 0, len(tokens))
+	for _, tk := range tokens {
+		if !seen[tk.term] {
+			seen[tk.term] = true
+			terms = append(terms, tk.term)
+		}
+	}
+
+	return terms
+}
+
+func hasKind(kinds []string, kind string) bool {
+	if len(kinds) == 0 {
+		return true
+	}
+	for _, k := range kinds {
+		if k == kind {
+			return true
+		}
+	}
+
+	return false
+}
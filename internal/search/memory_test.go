@@ -0,0 +1,150 @@
+package search
+
+import (
+	"testing"
+
+	"github.com/karlbehrensg/go-fiber-template/internal/domain"
+	"github.com/karlbehrensg/go-fiber-template/pkg/errs"
+)
+
+func newTestIndex() *MemoryIndex {
+	index := NewMemoryIndex()
+	index.RebuildDocuments(func() ([]domain.SearchDocument, *errs.AppError) {
+		return []domain.SearchDocument{
+			{Kind: domain.SearchKindAuthor, ID: 1, Fields: map[string]string{"full_name": "J. J. Benítez"}},
+			{Kind: domain.SearchKindAuthor, ID: 2, Fields: map[string]string{"full_name": "Isaac Asimov"}},
+			{Kind: domain.SearchKindBook, ID: 1, Fields: map[string]string{"title": "Caballo de Troya 1", "author_name": "J. J. Benítez"}},
+			{Kind: domain.SearchKindBook, ID: 2, Fields: map[string]string{"title": "Caballo de Troya 2", "author_name": "J. J. Benítez"}},
+			{Kind: domain.SearchKindBook, ID: 3, Fields: map[string]string{"title": "Los caballos <de> la noche", "author_name": "Isaac Asimov"}},
+			{Kind: domain.SearchKindBook, ID: 4, Fields: map[string]string{"title": "Fundación", "author_name": "Isaac Asimov"}},
+		}, nil
+	})
+
+	return index
+}
+
+func search(t *testing.T, index *MemoryIndex, query domain.SearchQuery) []domain.SearchHit {
+	hits, total, appErr := index.Search(query)
+	if appErr != nil {
+		t.Fatal(appErr.Message)
+	}
+	if total < len(hits) {
+		t.Fatalf("total %d lower than the %d hits", total, len(hits))
+	}
+
+	return hits
+}
+
+func Test_should_find_the_books_matching_every_word_ignoring_accents_and_case(t *testing.T) {
+	index := newTestIndex()
+
+	hits := search(t, index, domain.SearchQuery{Text: "BENITEZ caballo"})
+
+	if len(hits) != 2 || hits[0].ID != 1 || hits[1].ID != 2 || hits[0].Kind != domain.SearchKindBook {
+		t.Fatalf("Failed while matching hits: %+v", hits)
+	}
+	if hits[0].Highlights["title"] != "<em>Caballo</em> de Troya 1" || hits[0].Highlights["author_name"] != "J. J. <em>Benítez</em>" {
+		t.Errorf("Failed while matching highlights: %+v", hits[0].Highlights)
+	}
+}
+
+func Test_should_rank_exact_matches_before_prefix_matches(t *testing.T) {
+	index := newTestIndex()
+
+	hits := search(t, index, domain.SearchQuery{Text: "caballo", Kinds: []string{domain.SearchKindBook}})
+
+	if len(hits) != 3 || hits[2].ID != 3 || hits[0].Score <= hits[2].Score {
+		t.Fatalf("Failed while ranking hits: %+v", hits)
+	}
+	// the rest of the text is escaped
+	if hits[2].Highlights["title"] != "Los <em>caballos</em> &lt;de&gt; la noche" {
+		t.Errorf("Failed while matching highlight: %s", hits[2].Highlights["title"])
+	}
+}
+
+func Test_should_tolerate_typos_in_long_words(t *testing.T) {
+	index := newTestIndex()
+
+	hits := search(t, index, domain.SearchQuery{Text: "fundacoin", Kinds: []string{domain.SearchKindBook}})
+	if len(hits) != 1 || hits[0].ID != 4 {
+		t.Fatalf("Failed while matching transposition: %+v", hits)
+	}
+
+	hits = search(t, index, domain.SearchQuery{Text: "asimof", Kinds: []string{domain.SearchKindAuthor}})
+	if len(hits) != 1 || hits[0].ID != 2 {
+		t.Fatalf("Failed while matching typo: %+v", hits)
+	}
+
+	// short words must be exact
+	if hits = search(t, index, domain.SearchQuery{Text: "tro"}); len(hits) != 2 {
+		t.Fatalf("Failed while matching prefix: %+v", hits)
+	}
+	if hits = search(t, index, domain.SearchQuery{Text: "le noche"}); len(hits) != 0 {
+		t.Fatalf("Failed while rejecting typo in short word: %+v", hits)
+	}
+}
+
+func Test_should_update_and_remove_documents(t *testing.T) {
+	index := newTestIndex()
+
+	index.IndexDocument(domain.SearchDocument{Kind: domain.SearchKindAuthor, ID: 2, Fields: map[string]string{"full_name": "Ursula K. Le Guin"}})
+	index.RemoveDocument(domain.SearchKindBook, 4)
+
+	if hits := search(t, index, domain.SearchQuery{Text: "asimov", Kinds: []string{domain.SearchKindAuthor}}); len(hits) != 0 {
+		t.Fatalf("Failed while updating document: %+v", hits)
+	}
+	if hits := search(t, index, domain.SearchQuery{Text: "guin"}); len(hits) != 1 {
+		t.Fatalf("Failed while indexing document: %+v", hits)
+	}
+	if hits := search(t, index, domain.SearchQuery{Text: "fundacion"}); len(hits) != 0 {
+		t.Fatalf("Failed while removing document: %+v", hits)
+	}
+}
+
+func Test_should_limit_the_hits_and_return_the_total(t *testing.T) {
+	index := newTestIndex()
+
+	hits, total, _ := index.Search(domain.SearchQuery{Text: "asimov", Limit: 1})
+
+	if len(hits) != 1 || total != 3 {
+		t.Errorf("Failed while limiting hits: %d of %d", len(hits), total)
+	}
+}
+
+func Test_should_keep_the_changes_made_while_the_index_is_rebuilt(t *testing.T) {
+	index := newTestIndex()
+
+	// the rows were read before the changes, they are applied again after the swap
+	index.RebuildDocuments(func() ([]domain.SearchDocument, *errs.AppError) {
+		index.IndexDocument(domain.SearchDocument{Kind: domain.SearchKindBook, ID: 5, Fields: map[string]string{"title": "Dune"}})
+		index.RemoveDocument(domain.SearchKindBook, 4)
+		return []domain.SearchDocument{
+			{Kind: domain.SearchKindBook, ID: 4, Fields: map[string]string{"title": "Fundación"}},
+		}, nil
+	})
+
+	if hits := search(t, index, domain.SearchQuery{Text: "dune"}); len(hits) != 1 {
+		t.Fatalf("Failed while keeping the indexed document: %+v", hits)
+	}
+	if hits := search(t, index, domain.SearchQuery{Text: "fundacion"}); len(hits) != 0 {
+		t.Fatalf("Failed while keeping the removed document: %+v", hits)
+	}
+	if hits := search(t, index, domain.SearchQuery{Text: "asimov"}); len(hits) != 0 {
+		t.Fatalf("Failed while replacing the content: %+v", hits)
+	}
+}
+
+func Test_should_keep_the_content_when_the_rebuild_fails(t *testing.T) {
+	index := newTestIndex()
+
+	appErr := index.RebuildDocuments(func() ([]domain.SearchDocument, *errs.AppError) {
+		return nil, errs.NewUnexpectedError("Unexpected error from database")
+	})
+
+	if appErr == nil {
+		t.Fatal("Failed while returning the load error")
+	}
+	if hits := search(t, index, domain.SearchQuery{Text: "fundacion"}); len(hits) != 1 {
+		t.Fatalf("Failed while keeping the content: %+v", hits)
+	}
+}
@@ -0,0 +1,131 @@
+package search
+
+import (
+	"html"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// token word of a text, start and end are the bytes of the word in the original text
+type token struct {
+	term  string
+	start int
+	end   int
+}
+
+// tokenize split the text in words folded for search, e.g. "J. J. Benítez" is [j j benitez]
+func tokenize(text string) []token {
+	tokens := make([]token, 0)
+	start := -1
+	for i, r := range text {
+		// combining marks belong to the word of the letter before them
+		inWord := unicode.IsLetter(r) || unicode.IsDigit(r) || (start >= 0 && unicode.Is(unicode.Mn, r))
+		if inWord && start < 0 {
+			start = i
+		}
+		if !inWord && start >= 0 {
+			tokens = append(tokens, token{term: fold(text[start:i]), start: start, end: i})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		tokens = append(tokens, token{term: fold(text[start:]), start: start, end: len(text)})
+	}
+
+	return tokens
+}
+
+// fold remove the accents and the case of the word, so "Benítez" and "BENITEZ" are the same term
+func fold(word string) string {
+	// transformers keep state, a new chain is needed on each call
+	folder := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	folded, _, err := transform.String(folder, word)
+	if err != nil {
+		folded = word
+	}
+
+	return strings.ToLower(folded)
+}
+
+// maxEdits typos tolerated by the length of the word, short words must be exact
+func maxEdits(term string) int {
+	switch n := len([]rune(term)); {
+	case n < 4:
+		return 0
+	case n < 8:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// editDistance Damerau-Levenshtein distance (optimal string alignment), it stops counting past max
+func editDistance(a string, b string, max int) int {
+	s, t := []rune(a), []rune(b)
+	if d := len(s) - len(t); d > max || -d > max {
+		return max + 1
+	}
+
+	// three rows are enough to count transpositions
+	prev2 := make([]int, len(t)+1)
+	prev := make([]int, len(t)+1)
+	curr := make([]int, len(t)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(s); i++ {
+		curr[0] = i
+		lowest := curr[0]
+		for j := 1; j <= len(t); j++ {
+			cost := 1
+			if s[i-1] == t[j-1] {
+				cost = 0
+			}
+			curr[j] = minInt(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && s[i-1] == t[j-2] && s[i-2] == t[j-1] {
+				curr[j] = minInt(curr[j], prev2[j-2]+1)
+			}
+			lowest = minInt(lowest, curr[j])
+		}
+		if lowest > max {
+			return max + 1
+		}
+		prev2, prev, curr = prev, curr, prev2
+	}
+
+	return prev[len(t)]
+}
+
+// highlight wrap the words of the text matching the terms in <em>, the rest of the text is escaped
+func highlight(text string, tokens []token, terms map[string]bool) string {
+	var b strings.Builder
+	last := 0
+	for _, tk := range tokens {
+		if !terms[tk.term] {
+			continue
+		}
+		b.WriteString(html.EscapeString(text[last:tk.start]))
+		b.WriteString("<em>")
+		b.WriteString(html.EscapeString(text[tk.start:tk.end]))
+		b.WriteString("</em>")
+		last = tk.end
+	}
+	b.WriteString(html.EscapeString(text[last:]))
+
+	return b.String()
+}
+
+func minInt(values ...int) int {
+	min := values[0]
+	for _, v := range values[1:] {
+		if v < min {
+			min = v
+		}
+	}
+
+	return min
+}
@@ -2,31 +2,52 @@ package service
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/karlbehrensg/go-fiber-template/internal/domain"
 	"github.com/karlbehrensg/go-fiber-template/internal/http/requests"
+	"github.com/karlbehrensg/go-fiber-template/internal/http/responses"
 	"github.com/karlbehrensg/go-fiber-template/pkg/errs"
 	"github.com/karlbehrensg/go-fiber-template/pkg/logger"
 )
 
 // UserService port primary
 type UserService interface {
-	CreateUser(requests.UserRequest) *errs.AppError
+	CreateUser(domain.Actor, requests.UserRequest) *errs.AppError
+	BootstrapAdmin(requests.UserRequest) *errs.AppError
+	FindProfile(uint) (*responses.ProfileResponse, *errs.AppError)
+	UpdateProfile(uint, *requests.ProfileRequest) (*responses.ProfileResponse, *errs.AppError)
+	FindUsers(requests.UserListRequest) (*responses.UserListResponse, *errs.AppError)
+	FindUser(uint) (*responses.AdminUserResponse, *errs.AppError)
+	UpdateUserRole(actor domain.Actor, id uint, role string) (*responses.AdminUserResponse, *errs.AppError)
+	SetUserDisabled(actor domain.Actor, id uint, disabled bool) (*responses.AdminUserResponse, *errs.AppError)
+	DeleteUser(actor domain.Actor, id uint) *errs.AppError
+	RestoreUser(actor domain.Actor, id uint) (*responses.AdminUserResponse, *errs.AppError)
 }
 
 type DefaultUserService struct {
-	repo domain.UserRepository
+	repo   domain.UserRepository
+	mailer domain.Mailer
+	audit  auditLog
 }
 
-func NewUserService(repository domain.UserRepository) DefaultUserService {
-	return DefaultUserService{repository}
+func NewUserService(repository domain.UserRepository, mailer domain.Mailer, auditRepository domain.AuditRepository) DefaultUserService {
+	return DefaultUserService{repository, mailer, newAuditLog(auditRepository)}
 }
 
-func (s DefaultUserService) CreateUser(request requests.UserRequest) *errs.AppError {
+// CreateUser use case for sign up, the actor is the connection of the new user
+func (s DefaultUserService) CreateUser(actor domain.Actor, request requests.UserRequest) *errs.AppError {
+	if appErr := validatePassword(request.Password, request.Email); appErr != nil {
+		return appErr
+	}
+
 	user := &domain.User{
 		Name:     request.Name,
 		Email:    request.Email,
 		Password: request.Password,
+		Role:     domain.RoleReader,
 	}
 
 	if err := user.HashPassword(); err != nil {
@@ -38,5 +59,253 @@ func (s DefaultUserService) CreateUser(request requests.UserRequest) *errs.AppEr
 		return err
 	}
 
+	actor.UserID, actor.Email = user.ID, user.Email
+	s.audit.record(
+		actor,
+		domain.AuditEntry{Action: domain.AuditSignup, TargetType: "user", TargetID: strconv.FormatUint(uint64(user.ID), 10)},
+		nil, user.ToNewProfileResponse(),
+	)
+
+	// the account is usable once the email is verified, the link can be sent again
+	if err := sendVerificationMail(s.repo, s.mailer, user); err != nil {
+		logger.Error(fmt.Sprintf("Error while sending verification email: %s", err.Message))
+	}
+
 	return nil
 }
+
+// BootstrapAdmin use case for create the first admin or promote an existing user
+func (s DefaultUserService) BootstrapAdmin(request requests.UserRequest) *errs.AppError {
+	u, appErr := s.repo.FindUserByEmail(request.Email)
+	if appErr != nil {
+		if !strings.Contains(appErr.Message, "record not found") {
+			return appErr
+		}
+
+		if appErr = validatePassword(request.Password, request.Email); appErr != nil {
+			return appErr
+		}
+
+		verifiedAt := time.Now()
+		admin := &domain.User{
+			Name:       request.Name,
+			Email:      request.Email,
+			Password:   request.Password,
+			Role:       domain.RoleAdmin,
+			VerifiedAt: &verifiedAt,
+		}
+		if err := admin.HashPassword(); err != nil {
+			logger.Error(fmt.Sprintf("Error while encripting password: %s", err.Error()))
+			return errs.NewUnexpectedError("Unexpected error while encripting password")
+		}
+
+		logger.Info(fmt.Sprintf("Bootstrapping admin user %s", request.Email))
+		return s.repo.SaveUser(admin)
+	}
+
+	if u.Role == domain.RoleAdmin && u.IsVerified() {
+		return nil
+	}
+
+	logger.Info(fmt.Sprintf("Promoting user %s to admin", request.Email))
+	verifiedAt := time.Now()
+	_, appErr = s.repo.UpdateUser(&domain.User{ID: u.ID, Role: domain.RoleAdmin, VerifiedAt: &verifiedAt})
+
+	return appErr
+}
+
+// FindProfile use case for find the profile of a user
+func (s DefaultUserService) FindProfile(id uint) (*responses.ProfileResponse, *errs.AppError) {
+	var u *domain.User
+	var err *errs.AppError
+	// calls repository to find user by ID
+	if u, err = s.repo.FindUserById(id); err != nil {
+		return nil, err
+	}
+
+	return u.ToNewProfileResponse(), nil
+}
+
+// UpdateProfile use case for update the profile of a user
+func (s DefaultUserService) UpdateProfile(id uint, request *requests.ProfileRequest) (*responses.ProfileResponse, *errs.AppError) {
+	var current *domain.User
+	var err *errs.AppError
+	if current, err = s.repo.FindUserById(id); err != nil {
+		return nil, err
+	}
+
+	u := &domain.User{
+		ID:    id,
+		Name:  request.Name,
+		Email: request.Email,
+	}
+
+	// calls repository to update user
+	if u, err = s.repo.UpdateUser(u); err != nil {
+		return nil, err
+	}
+
+	// a new email address must be verified again
+	if request.Email != "" && request.Email != current.Email {
+		if err = s.repo.UpdateUserColumns(id, map[string]interface{}{"verified_at": nil}); err != nil {
+			return nil, err
+		}
+		current.Email = request.Email
+		if err = sendVerificationMail(s.repo, s.mailer, current); err != nil {
+			logger.Error(fmt.Sprintf("Error while sending verification email: %s", err.Message))
+		}
+	}
+
+	return s.FindProfile(id)
+}
+
+// FindUsers use case for list a page of users
+func (s DefaultUserService) FindUsers(request requests.UserListRequest) (*responses.UserListResponse, *errs.AppError) {
+	if request.Page < 1 {
+		request.Page = 1
+	}
+	if request.Limit < 1 {
+		request.Limit = 20
+	}
+
+	// calls repository to find the page of users
+	users, total, appErr := s.repo.FindUsers(domain.UserFilter{
+		Search: request.Search,
+		Role:   request.Role,
+		Status: request.Status,
+		Offset: (request.Page - 1) * request.Limit,
+		Limit:  request.Limit,
+	})
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	response := &responses.UserListResponse{
+		Items: make([]responses.AdminUserResponse, 0, len(users)),
+		Total: total,
+		Page:  request.Page,
+		Limit: request.Limit,
+	}
+	for _, u := range users {
+		response.Items = append(response.Items, *u.ToNewAdminUserResponse())
+	}
+
+	return response, nil
+}
+
+// FindUser use case for inspect a user, soft deleted users included
+func (s DefaultUserService) FindUser(id uint) (*responses.AdminUserResponse, *errs.AppError) {
+	u, appErr := s.repo.FindUserByIdWithDeleted(id)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	return u.ToNewAdminUserResponse(), nil
+}
+
+// UpdateUserRole use case for assign the role of a user, admins cannot change their own role
+func (s DefaultUserService) UpdateUserRole(actor domain.Actor, id uint, role string) (*responses.AdminUserResponse, *errs.AppError) {
+	if !domain.IsValidRole(role) {
+		return nil, errs.NewBadRequestError(fmt.Sprintf("invalid role %s", role))
+	}
+	if actor.UserID == id {
+		return nil, errs.NewBadRequestError("admins cannot change their own role")
+	}
+
+	before, appErr := s.FindUser(id)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	// calls repository to update the role, tokens take the new role on the next request
+	if appErr = s.repo.UpdateUserColumns(id, map[string]interface{}{"role": role}); appErr != nil {
+		return nil, appErr
+	}
+
+	after, appErr := s.FindUser(id)
+	if appErr != nil {
+		return nil, appErr
+	}
+	s.audit.record(actor, userAuditEntry(domain.AuditUserRoleUpdate, id), before, after)
+
+	return after, nil
+}
+
+// SetUserDisabled use case for disable or enable a user, disabled users cannot login and their tokens are rejected
+func (s DefaultUserService) SetUserDisabled(actor domain.Actor, id uint, disabled bool) (*responses.AdminUserResponse, *errs.AppError) {
+	if actor.UserID == id && disabled {
+		return nil, errs.NewBadRequestError("admins cannot disable their own account")
+	}
+
+	before, appErr := s.FindUser(id)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	var disabledAt *time.Time
+	action := domain.AuditUserEnable
+	if disabled {
+		now := time.Now()
+		disabledAt = &now
+		action = domain.AuditUserDisable
+	}
+
+	// calls repository to update the disabled date
+	if appErr = s.repo.UpdateUserColumns(id, map[string]interface{}{"disabled_at": disabledAt}); appErr != nil {
+		return nil, appErr
+	}
+
+	after, appErr := s.FindUser(id)
+	if appErr != nil {
+		return nil, appErr
+	}
+	s.audit.record(actor, userAuditEntry(action, id), before, after)
+
+	return after, nil
+}
+
+// DeleteUser use case for soft delete a user, it can be restored later
+func (s DefaultUserService) DeleteUser(actor domain.Actor, id uint) *errs.AppError {
+	if actor.UserID == id {
+		return errs.NewBadRequestError("admins cannot delete their own account")
+	}
+
+	before, appErr := s.FindUser(id)
+	if appErr != nil {
+		return appErr
+	}
+
+	// calls repository to soft delete the user
+	if appErr = s.repo.DeleteUser(id); appErr != nil {
+		return appErr
+	}
+	s.audit.record(actor, userAuditEntry(domain.AuditUserDelete, id), before, nil)
+
+	return nil
+}
+
+// RestoreUser use case for restore a soft deleted user
+func (s DefaultUserService) RestoreUser(actor domain.Actor, id uint) (*responses.AdminUserResponse, *errs.AppError) {
+	before, appErr := s.FindUser(id)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	// calls repository to restore the user
+	if appErr = s.repo.RestoreUser(id); appErr != nil {
+		return nil, appErr
+	}
+
+	after, appErr := s.FindUser(id)
+	if appErr != nil {
+		return nil, appErr
+	}
+	s.audit.record(actor, userAuditEntry(domain.AuditUserRestore, id), before, after)
+
+	return after, nil
+}
+
+// userAuditEntry entry of an admin action on a user
+func userAuditEntry(action string, id uint) domain.AuditEntry {
+	return domain.AuditEntry{Action: action, TargetType: "user", TargetID: strconv.FormatUint(uint64(id), 10)}
+}
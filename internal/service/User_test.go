@@ -0,0 +1,185 @@
+package service
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	realDomain "github.com/karlbehrensg/go-fiber-template/internal/domain"
+	"github.com/karlbehrensg/go-fiber-template/internal/http/requests"
+	"github.com/karlbehrensg/go-fiber-template/mocks/domain"
+	"github.com/karlbehrensg/go-fiber-template/pkg/errs"
+)
+
+var userService UserService
+
+// admin actor of the user management tests
+var adminActor = realDomain.Actor{UserID: 1, Email: "admin@example.com"}
+
+func userSetup(t *testing.T) func() {
+	ctrl := gomock.NewController(t)
+	mockUserRepo = domain.NewMockUserRepository(ctrl)
+	mockMailer = domain.NewMockMailer(ctrl)
+	userService = NewUserService(mockUserRepo, mockMailer, nil)
+	return func() {
+		userService = nil
+		defer ctrl.Finish()
+	}
+}
+
+func Test_should_promote_existing_user_when_bootstrap_admin(t *testing.T) {
+	// Arrange
+	teardown := userSetup(t)
+	defer teardown()
+
+	u := &realDomain.User{ID: 1, Email: "admin@example.com", Role: realDomain.RoleReader}
+
+	mockUserRepo.EXPECT().FindUserByEmail("admin@example.com").Return(u, nil)
+	mockUserRepo.EXPECT().UpdateUser(gomock.Any()).DoAndReturn(func(updated *realDomain.User) (*realDomain.User, *errs.AppError) {
+		if updated.Role != realDomain.RoleAdmin || !updated.IsVerified() {
+			t.Error("Failed while promoting user to verified admin")
+		}
+		return updated, nil
+	})
+	// Act
+	appError := userService.BootstrapAdmin(requests.UserRequest{Email: "admin@example.com", Password: "1234567"})
+
+	// Assert
+	if appError != nil {
+		t.Error("Test failed while promoting admin")
+	}
+}
+
+func Test_should_do_nothing_when_bootstrap_admin_already_exists(t *testing.T) {
+	// Arrange
+	teardown := userSetup(t)
+	defer teardown()
+
+	verifiedAt := time.Now()
+	u := &realDomain.User{ID: 1, Email: "admin@example.com", Role: realDomain.RoleAdmin, VerifiedAt: &verifiedAt}
+
+	mockUserRepo.EXPECT().FindUserByEmail("admin@example.com").Return(u, nil)
+	// Act
+	appError := userService.BootstrapAdmin(requests.UserRequest{Email: "admin@example.com", Password: "1234567"})
+
+	// Assert
+	if appError != nil {
+		t.Error("Test failed while bootstrapping admin")
+	}
+}
+
+func Test_should_list_users_with_offset_of_the_requested_page(t *testing.T) {
+	// Arrange
+	teardown := userSetup(t)
+	defer teardown()
+
+	users := []realDomain.User{{ID: 21, Email: "edwyn@example.com", Role: realDomain.RoleReader}}
+	filter := realDomain.UserFilter{Search: "edwyn", Status: realDomain.UserStatusActive, Offset: 20, Limit: 20}
+
+	mockUserRepo.EXPECT().FindUsers(filter).Return(users, int64(21), nil)
+	// Act
+	response, appError := userService.FindUsers(requests.UserListRequest{Search: "edwyn", Status: realDomain.UserStatusActive, Page: 2})
+
+	// Assert
+	if appError != nil || response.Total != 21 || len(response.Items) != 1 || response.Limit != 20 {
+		t.Error("Test failed while listing users")
+	}
+}
+
+func Test_should_return_status_400_when_admin_disables_own_account(t *testing.T) {
+	// Arrange
+	teardown := userSetup(t)
+	defer teardown()
+
+	// Act
+	_, appError := userService.SetUserDisabled(adminActor, 1, true)
+
+	// Assert
+	if appError == nil || appError.Code != 400 {
+		t.Error("Test failed while validating self disable")
+	}
+}
+
+func Test_should_set_disabled_date_and_record_it_when_user_is_disabled(t *testing.T) {
+	// Arrange
+	teardown := userSetup(t)
+	defer teardown()
+	mockAuditRepo := domain.NewMockAuditRepository(gomock.NewController(t))
+	userService = NewUserService(mockUserRepo, mockMailer, mockAuditRepo)
+
+	disabledAt := time.Now()
+	var entry *realDomain.AuditEntry
+	gomock.InOrder(
+		mockUserRepo.EXPECT().FindUserByIdWithDeleted(uint(2)).Return(&realDomain.User{ID: 2}, nil),
+		mockUserRepo.EXPECT().UpdateUserColumns(uint(2), gomock.Any()).Do(func(id uint, columns map[string]interface{}) {
+			if value, ok := columns["disabled_at"].(*time.Time); !ok || value == nil {
+				t.Error("Failed while setting disabled date")
+			}
+		}).Return(nil),
+		mockUserRepo.EXPECT().FindUserByIdWithDeleted(uint(2)).Return(&realDomain.User{ID: 2, DisabledAt: &disabledAt}, nil),
+	)
+	mockAuditRepo.EXPECT().SaveAuditEntry(gomock.Any()).Do(func(e *realDomain.AuditEntry) { entry = e }).Return(nil)
+	// Act
+	response, appError := userService.SetUserDisabled(adminActor, 2, true)
+
+	// Assert
+	if appError != nil || response.DisabledAt == nil {
+		t.Error("Test failed while disabling user")
+	}
+	if entry == nil || entry.Action != realDomain.AuditUserDisable || entry.ActorID != 1 || entry.TargetID != "2" ||
+		!strings.Contains(entry.Changes, `"disabled_at":{"after":`) {
+		t.Error("Test failed while recording disabled user")
+	}
+}
+
+func Test_should_record_role_before_and_after_when_user_role_is_updated(t *testing.T) {
+	// Arrange
+	teardown := userSetup(t)
+	defer teardown()
+	mockAuditRepo := domain.NewMockAuditRepository(gomock.NewController(t))
+	userService = NewUserService(mockUserRepo, mockMailer, mockAuditRepo)
+
+	var entry *realDomain.AuditEntry
+	gomock.InOrder(
+		mockUserRepo.EXPECT().FindUserByIdWithDeleted(uint(2)).Return(&realDomain.User{ID: 2, Role: realDomain.RoleReader}, nil),
+		mockUserRepo.EXPECT().UpdateUserColumns(uint(2), map[string]interface{}{"role": realDomain.RoleLibrarian}).Return(nil),
+		mockUserRepo.EXPECT().FindUserByIdWithDeleted(uint(2)).Return(&realDomain.User{ID: 2, Role: realDomain.RoleLibrarian}, nil),
+	)
+	mockAuditRepo.EXPECT().SaveAuditEntry(gomock.Any()).Do(func(e *realDomain.AuditEntry) { entry = e }).Return(nil)
+	// Act
+	response, appError := userService.UpdateUserRole(adminActor, 2, realDomain.RoleLibrarian)
+
+	// Assert
+	if appError != nil || response.Role != realDomain.RoleLibrarian {
+		t.Error("Test failed while updating user role")
+	}
+	if entry == nil || entry.Action != realDomain.AuditUserRoleUpdate || entry.ActorEmail != "admin@example.com" ||
+		entry.Changes != `{"role":{"after":"librarian","before":"reader"}}` {
+		t.Error("Test failed while recording user role")
+	}
+}
+
+func Test_should_record_the_deleted_user(t *testing.T) {
+	// Arrange
+	teardown := userSetup(t)
+	defer teardown()
+	mockAuditRepo := domain.NewMockAuditRepository(gomock.NewController(t))
+	userService = NewUserService(mockUserRepo, mockMailer, mockAuditRepo)
+
+	var entry *realDomain.AuditEntry
+	mockUserRepo.EXPECT().FindUserByIdWithDeleted(uint(2)).Return(&realDomain.User{ID: 2, Email: "edwyn@example.com"}, nil)
+	mockUserRepo.EXPECT().DeleteUser(uint(2)).Return(nil)
+	mockAuditRepo.EXPECT().SaveAuditEntry(gomock.Any()).Do(func(e *realDomain.AuditEntry) { entry = e }).Return(nil)
+	// Act
+	appError := userService.DeleteUser(adminActor, 2)
+
+	// Assert
+	if appError != nil {
+		t.Error("Test failed while deleting user")
+	}
+	if entry == nil || entry.Action != realDomain.AuditUserDelete || entry.TargetType != "user" ||
+		!strings.Contains(entry.Changes, `"email":{"before":"edwyn@example.com"}`) {
+		t.Error("Test failed while recording deleted user")
+	}
+}
@@ -0,0 +1,133 @@
+package service
+
+import (
+	"strings"
+	"time"
+
+	"github.com/karlbehrensg/go-fiber-template/internal/domain"
+	"github.com/karlbehrensg/go-fiber-template/internal/http/requests"
+	"github.com/karlbehrensg/go-fiber-template/internal/http/responses"
+	"github.com/karlbehrensg/go-fiber-template/pkg/errs"
+	"github.com/karlbehrensg/go-fiber-template/pkg/logger"
+	"github.com/karlbehrensg/go-fiber-template/pkg/utils"
+)
+
+// apiKeyPrefix identify our keys in logs and secret scanners
+const apiKeyPrefix = "lk_"
+
+// APIKeyService port primary
+type APIKeyService interface {
+	CreateAPIKey(uint, requests.APIKeyRequest) (*responses.APIKeyCreatedResponse, *errs.AppError)
+	FindAPIKeys(uint) ([]responses.APIKeyResponse, *errs.AppError)
+	RevokeAPIKey(userID uint, id uint) *errs.AppError
+	AuthenticateAPIKey(string) (*utils.JWTClaims, *errs.AppError)
+}
+
+type DefaultAPIKeyService struct {
+	repo     domain.APIKeyRepository
+	userRepo domain.UserRepository
+}
+
+// NewAPIKeyService create a new instance of DefaultAPIKeyService
+func NewAPIKeyService(repository domain.APIKeyRepository, userRepository domain.UserRepository) DefaultAPIKeyService {
+	return DefaultAPIKeyService{repository, userRepository}
+}
+
+// CreateAPIKey use case for create an API key, the key is only returned here
+func (s DefaultAPIKeyService) CreateAPIKey(userID uint, request requests.APIKeyRequest) (*responses.APIKeyCreatedResponse, *errs.AppError) {
+	secret, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		logger.Error(err.Error())
+		return nil, errs.NewUnexpectedError("unexpected error while creating API key")
+	}
+	key := apiKeyPrefix + secret
+
+	apiKey := &domain.APIKey{
+		UserID:  userID,
+		Name:    request.Name,
+		Prefix:  key[:len(apiKeyPrefix)+8],
+		KeyHash: utils.HashToken(key),
+		Scopes:  strings.Join(request.Scopes, ","),
+	}
+	if request.ExpiresInDays > 0 {
+		expiresAt := time.Now().AddDate(0, 0, request.ExpiresInDays)
+		apiKey.ExpiresAt = &expiresAt
+	}
+
+	// calls repository to save API key
+	if appErr := s.repo.SaveAPIKey(apiKey); appErr != nil {
+		return nil, appErr
+	}
+
+	return &responses.APIKeyCreatedResponse{APIKeyResponse: apiKey.ToNewAPIKeyResponse(), Key: key}, nil
+}
+
+// FindAPIKeys use case for list the API keys of the user
+func (s DefaultAPIKeyService) FindAPIKeys(userID uint) ([]responses.APIKeyResponse, *errs.AppError) {
+	keys, appErr := s.repo.FindAPIKeysByUser(userID)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	response := make([]responses.APIKeyResponse, 0, len(keys))
+	for i := range keys {
+		response = append(response, keys[i].ToNewAPIKeyResponse())
+	}
+
+	return response, nil
+}
+
+// RevokeAPIKey use case for revoke an API key of the user
+func (s DefaultAPIKeyService) RevokeAPIKey(userID uint, id uint) *errs.AppError {
+	if appErr := s.repo.RevokeAPIKey(userID, id); appErr != nil {
+		if strings.Contains(appErr.Message, "record not found") {
+			return errs.NewNotFoundError("API key not found")
+		}
+		return appErr
+	}
+
+	return nil
+}
+
+// AuthenticateAPIKey use case for validate an API key and return the claims of its owner
+func (s DefaultAPIKeyService) AuthenticateAPIKey(key string) (*utils.JWTClaims, *errs.AppError) {
+	if !strings.HasPrefix(key, apiKeyPrefix) {
+		return nil, errs.NewAuthenticationError("invalid API key")
+	}
+
+	apiKey, appErr := s.repo.FindAPIKeyByHash(utils.HashToken(key))
+	if appErr != nil {
+		if strings.Contains(appErr.Message, "record not found") {
+			return nil, errs.NewAuthenticationError("invalid API key")
+		}
+		return nil, appErr
+	}
+
+	if !apiKey.IsActive() {
+		return nil, errs.NewAuthenticationError("API key revoked or expired")
+	}
+
+	var u *domain.User
+	if u, appErr = s.userRepo.FindUserById(apiKey.UserID); appErr != nil {
+		if strings.Contains(appErr.Message, "record not found") {
+			return nil, errs.NewAuthenticationError("invalid API key")
+		}
+		return nil, appErr
+	}
+
+	if u.IsDisabled() {
+		return nil, errs.NewAuthenticationError("account is disabled")
+	}
+
+	// the last use is tracked with minute precision to avoid a write per request
+	now := time.Now()
+	if apiKey.LastUsedAt == nil || now.Sub(*apiKey.LastUsedAt) > time.Minute {
+		s.repo.TouchAPIKey(apiKey.ID, now)
+	}
+
+	claims := u.ToNewUtilsJWTClaims()
+	claims.APIKeyID = apiKey.ID
+	claims.Scopes = apiKey.ScopeList()
+
+	return claims, nil
+}
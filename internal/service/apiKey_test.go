@@ -0,0 +1,94 @@
+package service
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	realDomain "github.com/karlbehrensg/go-fiber-template/internal/domain"
+	"github.com/karlbehrensg/go-fiber-template/internal/http/requests"
+	"github.com/karlbehrensg/go-fiber-template/mocks/domain"
+	"github.com/karlbehrensg/go-fiber-template/pkg/utils"
+)
+
+var mockAPIKeyRepo *domain.MockAPIKeyRepository
+var apiKeyService APIKeyService
+
+func apiKeySetup(t *testing.T) func() {
+	ctrl := gomock.NewController(t)
+	mockUserRepo = domain.NewMockUserRepository(ctrl)
+	mockAPIKeyRepo = domain.NewMockAPIKeyRepository(ctrl)
+	apiKeyService = NewAPIKeyService(mockAPIKeyRepo, mockUserRepo)
+	return func() {
+		apiKeyService = nil
+		defer ctrl.Finish()
+	}
+}
+
+func Test_should_store_only_the_hash_and_prefix_when_api_key_is_created(t *testing.T) {
+	// Arrange
+	teardown := apiKeySetup(t)
+	defer teardown()
+
+	var saved *realDomain.APIKey
+	mockAPIKeyRepo.EXPECT().SaveAPIKey(gomock.Any()).Do(func(key *realDomain.APIKey) {
+		saved = key
+	}).Return(nil)
+	// Act
+	response, appError := apiKeyService.CreateAPIKey(1, requests.APIKeyRequest{
+		Name: "import script", Scopes: []string{realDomain.ScopeCatalogRead}, ExpiresInDays: 30,
+	})
+
+	// Assert
+	if appError != nil {
+		t.Error("Test failed while creating API key")
+	}
+	if saved.KeyHash != utils.HashToken(response.Key) || !strings.HasPrefix(response.Key, saved.Prefix) || saved.Prefix == response.Key {
+		t.Error("Failed while hashing API key")
+	}
+	if saved.Scopes != realDomain.ScopeCatalogRead || saved.ExpiresAt == nil {
+		t.Error("Failed while setting API key scopes and expiry")
+	}
+}
+
+func Test_should_return_claims_with_scopes_when_api_key_is_valid(t *testing.T) {
+	// Arrange
+	teardown := apiKeySetup(t)
+	defer teardown()
+
+	key := &realDomain.APIKey{ID: 3, UserID: 1, Scopes: realDomain.ScopeCatalogRead}
+	u := &realDomain.User{ID: 1, Role: realDomain.RoleLibrarian}
+
+	mockAPIKeyRepo.EXPECT().FindAPIKeyByHash(utils.HashToken("lk_secret")).Return(key, nil)
+	mockUserRepo.EXPECT().FindUserById(uint(1)).Return(u, nil)
+	mockAPIKeyRepo.EXPECT().TouchAPIKey(uint(3), gomock.Any()).Return(nil)
+	// Act
+	claims, appError := apiKeyService.AuthenticateAPIKey("lk_secret")
+
+	// Assert
+	if appError != nil || claims.UserID != 1 || claims.APIKeyID != 3 {
+		t.Error("Test failed while authenticating API key")
+	}
+	if !claims.HasScope(realDomain.ScopeCatalogRead) || claims.HasScope(realDomain.ScopeCatalogWrite) {
+		t.Error("Failed while restricting API key scopes")
+	}
+}
+
+func Test_should_return_status_401_when_api_key_is_expired(t *testing.T) {
+	// Arrange
+	teardown := apiKeySetup(t)
+	defer teardown()
+
+	expiresAt := time.Now().Add(-time.Minute)
+	key := &realDomain.APIKey{ID: 3, UserID: 1, ExpiresAt: &expiresAt}
+
+	mockAPIKeyRepo.EXPECT().FindAPIKeyByHash(gomock.Any()).Return(key, nil)
+	// Act
+	_, appError := apiKeyService.AuthenticateAPIKey("lk_secret")
+
+	// Assert
+	if appError == nil || appError.Code != 401 {
+		t.Error("Test failed while validating expired API key")
+	}
+}
@@ -0,0 +1,168 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/karlbehrensg/go-fiber-template/internal/domain"
+	"github.com/karlbehrensg/go-fiber-template/internal/http/requests"
+	"github.com/karlbehrensg/go-fiber-template/internal/http/responses"
+	"github.com/karlbehrensg/go-fiber-template/pkg/errs"
+	"github.com/karlbehrensg/go-fiber-template/pkg/logger"
+)
+
+// AuditService port primary
+type AuditService interface {
+	FindAuditEntries(requests.AuditListRequest) (*responses.AuditListResponse, *errs.AppError)
+}
+
+type DefaultAuditService struct {
+	repo domain.AuditRepository
+}
+
+// NewAuditService create a new instance of DefaultAuditService
+func NewAuditService(repository domain.AuditRepository) DefaultAuditService {
+	return DefaultAuditService{repository}
+}
+
+// FindAuditEntries use case for list a page of audit entries
+func (s DefaultAuditService) FindAuditEntries(request requests.AuditListRequest) (*responses.AuditListResponse, *errs.AppError) {
+	if request.Page < 1 {
+		request.Page = 1
+	}
+	if request.Limit < 1 {
+		request.Limit = 20
+	}
+
+	filter := domain.AuditFilter{
+		Action:         request.Action,
+		ActorID:        request.ActorID,
+		ImpersonatorID: request.ImpersonatorID,
+		TargetType:     request.TargetType,
+		TargetID:       request.TargetID,
+		RequestID:      request.RequestID,
+		Offset:         (request.Page - 1) * request.Limit,
+		Limit:          request.Limit,
+	}
+	var appErr *errs.AppError
+	if filter.From, appErr = parseAuditDate(request.From); appErr != nil {
+		return nil, appErr
+	}
+	if filter.To, appErr = parseAuditDate(request.To); appErr != nil {
+		return nil, appErr
+	}
+
+	// calls repository to find the page of entries
+	entries, total, appErr := s.repo.FindAuditEntries(filter)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	response := &responses.AuditListResponse{
+		Items: make([]responses.AuditEntryResponse, 0, len(entries)),
+		Total: total,
+		Page:  request.Page,
+		Limit: request.Limit,
+	}
+	for _, entry := range entries {
+		response.Items = append(response.Items, *entry.ToNewAuditEntryResponse())
+	}
+
+	return response, nil
+}
+
+// parseAuditDate parse an optional RFC 3339 date of the filter
+func parseAuditDate(value string) (*time.Time, *errs.AppError) {
+	if value == "" {
+		return nil, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, errs.NewBadRequestError(fmt.Sprintf("invalid date %s, use RFC 3339", value))
+	}
+
+	return &t, nil
+}
+
+// auditLog records the audit entries of the use cases, a failure is logged and never stops the use case
+type auditLog struct {
+	repo domain.AuditRepository
+}
+
+func newAuditLog(repository domain.AuditRepository) auditLog {
+	return auditLog{repository}
+}
+
+// record append the entry made by the actor, with the fields changed between before and after
+func (a auditLog) record(actor domain.Actor, entry domain.AuditEntry, before interface{}, after interface{}) {
+	if a.repo == nil {
+		return
+	}
+
+	entry.ActorID = actor.UserID
+	entry.ActorEmail = actor.Email
+	entry.ImpersonatorID = actor.ImpersonatorID
+	entry.IP = actor.IP
+	entry.RequestID = actor.RequestID
+
+	changes, err := auditChanges(before, after)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Error while creating audit changes: %s", err.Error()))
+	}
+	entry.Changes = changes
+
+	if appErr := a.repo.SaveAuditEntry(&entry); appErr != nil {
+		logger.Error(fmt.Sprintf("Error while saving audit entry %s: %s", entry.Action, appErr.Message))
+	}
+}
+
+// auditChanges create the JSON diff {"field": {"before": x, "after": y}} of the changed fields, before or after can be nil
+func auditChanges(before interface{}, after interface{}) (string, error) {
+	beforeFields, err := auditFields(before)
+	if err != nil {
+		return "", err
+	}
+	afterFields, err := auditFields(after)
+	if err != nil {
+		return "", err
+	}
+
+	changes := map[string]map[string]interface{}{}
+	for name, value := range beforeFields {
+		if other, ok := afterFields[name]; !ok || !reflect.DeepEqual(value, other) {
+			changes[name] = map[string]interface{}{"before": value}
+		}
+	}
+	for name, value := range afterFields {
+		if other, ok := beforeFields[name]; !ok || !reflect.DeepEqual(value, other) {
+			if changes[name] == nil {
+				changes[name] = map[string]interface{}{}
+			}
+			changes[name]["after"] = value
+		}
+	}
+	if len(changes) == 0 {
+		return "", nil
+	}
+
+	diff, err := json.Marshal(changes)
+	return string(diff), err
+}
+
+// auditFields convert a value to its JSON fields
+func auditFields(value interface{}) (map[string]interface{}, error) {
+	fields := map[string]interface{}{}
+	if v := reflect.ValueOf(value); !v.IsValid() || (v.Kind() == reflect.Ptr && v.IsNil()) {
+		return fields, nil
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	return fields, json.Unmarshal(data, &fields)
+}
@@ -1,6 +1,8 @@
 package service
 
 import (
+	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/karlbehrensg/go-fiber-template/internal/domain"
@@ -15,12 +17,28 @@ type AuthService interface {
 }
 
 type DefaultAuthService struct {
-	repo domain.UserRepository
+	repo       domain.UserRepository
+	tokenSrv   TokenService
+	throttle   loginThrottle
+	audit      auditLog
+	challenges mfaChallenges
 }
 
 // NewAuthService create a new instance of DefaultAuthService
-func NewAuthService(repository domain.UserRepository) DefaultAuthService {
-	return DefaultAuthService{repository}
+func NewAuthService(
+	repository domain.UserRepository,
+	tokenService TokenService,
+	loginAttemptRepository domain.LoginAttemptRepository,
+	auditRepository domain.AuditRepository,
+	mfaChallengeRepository domain.MFAChallengeRepository,
+) DefaultAuthService {
+	return DefaultAuthService{
+		repository,
+		tokenService,
+		newLoginThrottle(loginAttemptRepository),
+		newAuditLog(auditRepository),
+		newMFAChallenges(mfaChallengeRepository),
+	}
 }
 
 // Login use case for validate user and return token
@@ -28,10 +46,18 @@ func (s DefaultAuthService) Login(request requests.LoginRequest) (*responses.Log
 	var appErr *errs.AppError
 	var u *domain.User
 
+	// reject locked or throttled attempts before comparing the password
+	if appErr = s.throttle.check(request.Email, request.IP); appErr != nil {
+		s.loginFailed(request, nil, appErr.Message)
+		return nil, appErr
+	}
+
 	// find user by email
 	if u, appErr = s.repo.FindUserByEmail(request.Email); appErr != nil {
 		logger.Error(appErr.Message)
 		if strings.Contains(appErr.Message, "record not found") {
+			s.throttle.fail(request.Email, request.IP)
+			s.loginFailed(request, nil, "unknown email")
 			return nil, errs.NewAuthenticationError("invalid credentials")
 		}
 		return nil, appErr
@@ -39,19 +65,55 @@ func (s DefaultAuthService) Login(request requests.LoginRequest) (*responses.Log
 
 	// validate password
 	if appErr = u.ComparePassword(request.Password); appErr != nil {
+		s.throttle.fail(request.Email, request.IP)
+		s.loginFailed(request, u, "invalid password")
 		return nil, appErr
 	}
+	s.throttle.reset(request.Email, request.IP)
+
+	// upgrade the hash when the hashing algorithm or its parameters changed
+	if rehashed, err := u.RehashPassword(request.Password); err != nil {
+		logger.Error(fmt.Sprintf("Error while upgrading password hash: %s", err.Error()))
+	} else if rehashed {
+		if appErr = s.repo.UpdateUserColumns(u.ID, map[string]interface{}{"password": u.Password}); appErr != nil {
+			logger.Error(fmt.Sprintf("Error while saving upgraded password hash: %s", appErr.Message))
+		}
+	}
 
-	// create claim
-	jwtClaims := u.ToNewUtilsJWTClaims()
+	// validate the email was confirmed
+	if !u.IsVerified() {
+		s.loginFailed(request, u, "email not verified")
+		return nil, errs.NewUnverifiedError("email not verified")
+	}
+
+	// validate an admin did not disable the account
+	if u.IsDisabled() {
+		s.loginFailed(request, u, "account is disabled")
+		return nil, errs.NewAuthorizationError("account is disabled")
+	}
+
+	// the access token is issued after the second factor in /auth/mfa/verify
+	if u.IsMFAEnabled() {
+		return s.challenges.issue(u)
+	}
+
+	// create access and refresh token
+	return s.tokenSrv.IssueTokens(u, domain.ClientInfo{IP: request.IP, UserAgent: request.UserAgent, RequestID: request.RequestID})
+}
 
-	var accessToken string
-	var err error
-	// create token
-	if accessToken, err = jwtClaims.CreateToken(); err != nil {
-		logger.Error(err.Error())
-		return nil, errs.NewUnexpectedError("unexpected error while creating token")
+// loginFailed record the failed attempt, the user is nil when the email is unknown
+func (s DefaultAuthService) loginFailed(request requests.LoginRequest, u *domain.User, reason string) {
+	entry := domain.AuditEntry{Action: domain.AuditLoginFailed, TargetType: "user", Detail: reason}
+	if u != nil {
+		entry.TargetID = strconv.FormatUint(uint64(u.ID), 10)
 	}
 
-	return &responses.LoginResponse{Token: accessToken}, nil
+	s.audit.record(
+		domain.Actor{
+			Email:      request.Email,
+			ClientInfo: domain.ClientInfo{IP: request.IP, UserAgent: request.UserAgent, RequestID: request.RequestID},
+		},
+		entry,
+		nil, nil,
+	)
 }
@@ -0,0 +1,169 @@
+package service
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	realDomain "github.com/karlbehrensg/go-fiber-template/internal/domain"
+	"github.com/karlbehrensg/go-fiber-template/internal/http/requests"
+	"github.com/karlbehrensg/go-fiber-template/mocks/domain"
+	"github.com/karlbehrensg/go-fiber-template/pkg/errs"
+)
+
+var mockLoginAttemptRepo *domain.MockLoginAttemptRepository
+var authService AuthService
+
+func authSetup(t *testing.T) func() {
+	ctrl := gomock.NewController(t)
+	mockUserRepo = domain.NewMockUserRepository(ctrl)
+	mockRefreshTokenRepo = domain.NewMockRefreshTokenRepository(ctrl)
+	mockRevokedTokenRepo = domain.NewMockRevokedTokenRepository(ctrl)
+	mockSessionRepo = domain.NewMockSessionRepository(ctrl)
+	mockLoginAttemptRepo = domain.NewMockLoginAttemptRepository(ctrl)
+	tokenService := NewTokenService(mockUserRepo, mockRefreshTokenRepo, mockRevokedTokenRepo, mockSessionRepo, nil)
+	authService = NewAuthService(mockUserRepo, tokenService, mockLoginAttemptRepo, nil, nil)
+	return func() {
+		authService = nil
+		defer ctrl.Finish()
+	}
+}
+
+func Test_should_return_status_423_without_comparing_password_when_account_is_locked(t *testing.T) {
+	// Arrange
+	teardown := authSetup(t)
+	defer teardown()
+
+	lockedUntil := time.Now().Add(time.Minute)
+	attempt := &realDomain.LoginAttempt{Key: "email:edwyn@example.com", Failures: 10, LastFailure: time.Now(), LockedUntil: &lockedUntil}
+
+	mockLoginAttemptRepo.EXPECT().FindLoginAttempt("email:edwyn@example.com").Return(attempt, nil)
+	// Act
+	_, appError := authService.Login(requests.LoginRequest{Email: "edwyn@example.com", Password: "1234567", IP: "10.0.0.1"})
+
+	// Assert
+	if appError == nil || appError.Code != 423 {
+		t.Error("Test failed while validating locked account")
+	}
+}
+
+func Test_should_return_status_429_when_retrying_before_the_progressive_delay(t *testing.T) {
+	// Arrange
+	teardown := authSetup(t)
+	defer teardown()
+
+	attempt := &realDomain.LoginAttempt{Key: "ip:10.0.0.1", Failures: 5, LastFailure: time.Now()}
+
+	mockLoginAttemptRepo.EXPECT().FindLoginAttempt("email:edwyn@example.com").Return(nil, errs.NewNotFoundError("record not found"))
+	mockLoginAttemptRepo.EXPECT().FindLoginAttempt("ip:10.0.0.1").Return(attempt, nil)
+	// Act
+	_, appError := authService.Login(requests.LoginRequest{Email: "edwyn@example.com", Password: "1234567", IP: "10.0.0.1"})
+
+	// Assert
+	if appError == nil || appError.Code != 429 {
+		t.Error("Test failed while validating progressive delay")
+	}
+}
+
+func Test_should_register_failed_attempt_and_lock_when_threshold_is_reached(t *testing.T) {
+	// Arrange
+	teardown := authSetup(t)
+	defer teardown()
+
+	mockLoginAttemptRepo.EXPECT().FindLoginAttempt(gomock.Any()).Return(nil, errs.NewNotFoundError("record not found")).Times(2)
+	mockUserRepo.EXPECT().FindUserByEmail("edwyn@example.com").Return(nil, errs.NewNotFoundError("record not found"))
+	mockLoginAttemptRepo.EXPECT().RegisterFailedLogin("email:edwyn@example.com", gomock.Any()).
+		Return(&realDomain.LoginAttempt{Key: "email:edwyn@example.com", Failures: 10, LastFailure: time.Now()}, nil)
+	mockLoginAttemptRepo.EXPECT().LockLoginAttempt("email:edwyn@example.com", gomock.Any()).Return(nil)
+	mockLoginAttemptRepo.EXPECT().RegisterFailedLogin("ip:10.0.0.1", gomock.Any()).
+		Return(&realDomain.LoginAttempt{Key: "ip:10.0.0.1", Failures: 1, LastFailure: time.Now()}, nil)
+	// Act
+	_, appError := authService.Login(requests.LoginRequest{Email: "edwyn@example.com", Password: "1234567", IP: "10.0.0.1"})
+
+	// Assert
+	if appError == nil || appError.Code != 401 {
+		t.Error("Test failed while registering failed login")
+	}
+}
+
+func Test_should_upgrade_password_hash_when_hashing_parameters_changed(t *testing.T) {
+	// Arrange
+	teardown := authSetup(t)
+	defer teardown()
+	t.Setenv("PASSWORD_BCRYPT_COST", "4")
+
+	u := &realDomain.User{ID: 1, Email: "edwyn@example.com", Password: "1234567"}
+	t.Setenv("PASSWORD_HASH_ALGORITHM", "bcrypt")
+	u.HashPassword()
+	t.Setenv("PASSWORD_HASH_ALGORITHM", "argon2id")
+	t.Setenv("PASSWORD_ARGON2_MEMORY", "1024")
+	t.Setenv("PASSWORD_ARGON2_TIME", "1")
+
+	mockLoginAttemptRepo.EXPECT().FindLoginAttempt(gomock.Any()).Return(nil, errs.NewNotFoundError("record not found")).Times(2)
+	mockUserRepo.EXPECT().FindUserByEmail(u.Email).Return(u, nil)
+	mockLoginAttemptRepo.EXPECT().ResetLoginAttempts(gomock.Any()).Return(nil).Times(2)
+	mockUserRepo.EXPECT().UpdateUserColumns(uint(1), gomock.Any()).Do(func(id uint, columns map[string]interface{}) {
+		if hash, _ := columns["password"].(string); !strings.HasPrefix(hash, "$argon2id$") {
+			t.Error("Test failed while validating upgraded hash")
+		}
+	}).Return(nil)
+	// Act
+	_, appError := authService.Login(requests.LoginRequest{Email: u.Email, Password: "1234567", IP: "10.0.0.1"})
+
+	// Assert
+	if appError == nil || appError.Code != 403 {
+		t.Error("Test failed while upgrading password hash")
+	}
+}
+
+func Test_should_return_status_403_when_account_is_disabled(t *testing.T) {
+	// Arrange
+	teardown := authSetup(t)
+	defer teardown()
+	t.Setenv("PASSWORD_BCRYPT_COST", "4")
+
+	now := time.Now()
+	u := &realDomain.User{ID: 1, Email: "edwyn@example.com", Password: "1234567", VerifiedAt: &now, DisabledAt: &now}
+	u.HashPassword()
+
+	mockLoginAttemptRepo.EXPECT().FindLoginAttempt(gomock.Any()).Return(nil, errs.NewNotFoundError("record not found")).Times(2)
+	mockUserRepo.EXPECT().FindUserByEmail(u.Email).Return(u, nil)
+	mockLoginAttemptRepo.EXPECT().ResetLoginAttempts(gomock.Any()).Return(nil).Times(2)
+	// Act
+	_, appError := authService.Login(requests.LoginRequest{Email: u.Email, Password: "1234567", IP: "10.0.0.1"})
+
+	// Assert
+	if appError == nil || appError.Code != 403 || appError.Message != "account is disabled" {
+		t.Error("Test failed while validating disabled account")
+	}
+}
+
+func Test_should_record_failed_login_with_the_attempted_email(t *testing.T) {
+	// Arrange
+	teardown := authSetup(t)
+	defer teardown()
+
+	mockAuditRepo := domain.NewMockAuditRepository(gomock.NewController(t))
+	authService = NewAuthService(mockUserRepo, nil, mockLoginAttemptRepo, mockAuditRepo, nil)
+	var entry *realDomain.AuditEntry
+
+	mockLoginAttemptRepo.EXPECT().FindLoginAttempt(gomock.Any()).Return(nil, errs.NewNotFoundError("record not found")).Times(2)
+	mockUserRepo.EXPECT().FindUserByEmail("edwyn@example.com").Return(nil, errs.NewNotFoundError("record not found"))
+	mockLoginAttemptRepo.EXPECT().RegisterFailedLogin(gomock.Any(), gomock.Any()).
+		Return(&realDomain.LoginAttempt{Failures: 1, LastFailure: time.Now()}, nil).Times(2)
+	mockAuditRepo.EXPECT().SaveAuditEntry(gomock.Any()).DoAndReturn(func(e *realDomain.AuditEntry) *errs.AppError {
+		entry = e
+		return nil
+	})
+	// Act
+	_, appError := authService.Login(requests.LoginRequest{Email: "edwyn@example.com", Password: "1234567", IP: "10.0.0.1", RequestID: "req-1"})
+
+	// Assert
+	if appError == nil || appError.Code != 401 {
+		t.Fatal("Test failed while validating failed login")
+	}
+	if entry.Action != realDomain.AuditLoginFailed || entry.ActorEmail != "edwyn@example.com" || entry.IP != "10.0.0.1" || entry.RequestID != "req-1" {
+		t.Errorf("Failed while matching audit entry: %+v", entry)
+	}
+}
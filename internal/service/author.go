@@ -1,34 +1,46 @@
 package service
 
 import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
 	"github.com/karlbehrensg/go-fiber-template/internal/domain"
 	"github.com/karlbehrensg/go-fiber-template/internal/http/requests"
 	"github.com/karlbehrensg/go-fiber-template/internal/http/responses"
 	"github.com/karlbehrensg/go-fiber-template/pkg/errs"
+	"github.com/karlbehrensg/go-fiber-template/pkg/logger"
 )
 
 // AuthorService port primary
 //
 //go:generate mockgen -destination=../../mocks/service/mockAuthorService.go -package=service github.com/karlbehrensg/go-fiber-template/internal/service AuthorService
 type AuthorService interface {
-	CreateAuthor(requests.AuthorRequest) *errs.AppError
-	FindAllAuthor() ([]responses.AuthorResponse, *errs.AppError)
-	FindAuthorById(uint) (*responses.AuthorResponse, *errs.AppError)
-	UpdateAuthor(*requests.AuthorRequest) (*responses.AuthorResponse, *errs.AppError)
-	DeleteAuthor(uint) *errs.AppError
+	CreateAuthor(domain.Actor, requests.AuthorRequest) *errs.AppError
+	FindAllAuthor(requests.ListRequest) (*responses.AuthorListResponse, *errs.AppError)
+	FindAuthorById(id uint, include string) (*responses.AuthorResponse, *errs.AppError)
+	UpdateAuthor(domain.Actor, *requests.AuthorRequest) (*responses.AuthorResponse, *errs.AppError)
+	DeleteAuthor(domain.Actor, requests.DeleteAuthorRequest) *errs.AppError
 }
 
 type DefaultAuthorService struct {
-	repo domain.AuthorRepository
+	repo   domain.AuthorRepository
+	audit  auditLog
+	search searchSync
 }
 
-// NewAuthorService create a new instance of DefaultAuthorService
-func NewAuthorService(repository domain.AuthorRepository) DefaultAuthorService {
-	return DefaultAuthorService{repository}
+// NewAuthorService create a new instance of DefaultAuthorService, the index can be nil
+func NewAuthorService(
+	repository domain.AuthorRepository,
+	auditRepository domain.AuditRepository,
+	searchIndex domain.SearchIndex,
+) DefaultAuthorService {
+	return DefaultAuthorService{repository, newAuditLog(auditRepository), newSearchSync(searchIndex)}
 }
 
 // CreateAuthor use case for create author
-func (s DefaultAuthorService) CreateAuthor(request requests.AuthorRequest) *errs.AppError {
+func (s DefaultAuthorService) CreateAuthor(actor domain.Actor, request requests.AuthorRequest) *errs.AppError {
 
 	author := &domain.Author{
 		FullName: request.FullName,
@@ -39,32 +51,72 @@ func (s DefaultAuthorService) CreateAuthor(request requests.AuthorRequest) *errs
 		return err
 	}
 
+	s.audit.record(actor, authorAuditEntry(domain.AuditAuthorCreate, author.ID), nil, author.ToNewAuthorResponse())
+	s.search.put(author.ToSearchDocument())
+
 	return nil
 }
 
-// FindAllAuthor use case for find all author
-func (s DefaultAuthorService) FindAllAuthor() ([]responses.AuthorResponse, *errs.AppError) {
-	var authors []domain.Author
-	var err *errs.AppError
-	// calls repository to find all author
-	if authors, err = s.repo.FindAllAuthor(); err != nil {
-		return nil, err
+// authorListSchema fields of GET /author
+var authorListSchema = listSchema{
+	fields: map[string]listField{
+		"id":        {column: "id", numeric: true, sortable: true},
+		"full_name": {column: "full_name", sortable: true, operators: []string{domain.ListOperatorEqual, domain.ListOperatorContains}},
+	},
+	include: map[string]string{"books": "Books"},
+}
+
+// FindAllAuthor use case for find a page of authors
+func (s DefaultAuthorService) FindAllAuthor(request requests.ListRequest) (*responses.AuthorListResponse, *errs.AppError) {
+	plan, appErr := newListPlan(authorListSchema, request)
+	if appErr != nil {
+		return nil, appErr
 	}
 
-	response := make([]responses.AuthorResponse, 0)
-	for _, author := range authors {
-		response = append(response, *author.ToNewAuthorResponse())
+	// calls repository to find the page of authors
+	authors, total, appErr := s.repo.FindAllAuthor(plan.query)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	n, more := plan.page(authors)
+	response := &responses.AuthorListResponse{
+		Items: make([]responses.AuthorResponse, 0, n),
+		Total: total,
+		Limit: plan.limit,
+		Links: plan.links(n, more, total, func(i int, column string) interface{} {
+			return authors[i].ListValue(column)
+		}),
+	}
+	for _, author := range authors[:n] {
+		if len(plan.query.Include) > 0 {
+			response.Items = append(response.Items, *author.ToNewAuthorResponseWithBooks())
+		} else {
+			response.Items = append(response.Items, *author.ToNewAuthorResponse())
+		}
 	}
 
 	return response, nil
 }
 
-// FindAuthorById use case for find author by ID
-func (s DefaultAuthorService) FindAuthorById(id uint) (*responses.AuthorResponse, *errs.AppError) {
-	var author *domain.Author
-	var err *errs.AppError
+// FindAuthorById use case for find author by ID, include=books returns its books
+func (s DefaultAuthorService) FindAuthorById(id uint, include string) (*responses.AuthorResponse, *errs.AppError) {
+	associations, err := parseListInclude(authorListSchema, include)
+	if err != nil {
+		return nil, err
+	}
+
 	// calls repository to find author by ID
-	if author, err = s.repo.FindAuthorById(id); err != nil {
+	if len(associations) > 0 {
+		author, err := s.repo.FindAuthorByIdWithBooks(id)
+		if err != nil {
+			return nil, err
+		}
+		return author.ToNewAuthorResponseWithBooks(), nil
+	}
+
+	author, err := s.repo.FindAuthorById(id)
+	if err != nil {
 		return nil, err
 	}
 
@@ -74,31 +126,126 @@ func (s DefaultAuthorService) FindAuthorById(id uint) (*responses.AuthorResponse
 }
 
 // UpdateAuthor use case for update author
-func (s DefaultAuthorService) UpdateAuthor(request *requests.AuthorRequest) (*responses.AuthorResponse, *errs.AppError) {
+func (s DefaultAuthorService) UpdateAuthor(actor domain.Actor, request *requests.AuthorRequest) (*responses.AuthorResponse, *errs.AppError) {
+	// the current state is kept for the audit entry
+	before, err := s.repo.FindAuthorById(request.Id)
+	if err != nil {
+		return nil, err
+	}
+
 	author := &domain.Author{
 		ID:       request.Id,
 		FullName: request.FullName,
 	}
 
-	var err *errs.AppError
 	// calls repository to update author
 	if author, err = s.repo.UpdateAuthor(author); err != nil {
 		return nil, err
 	}
 
 	response := *author.ToNewAuthorResponse()
+	s.audit.record(actor, authorAuditEntry(domain.AuditAuthorUpdate, author.ID), before.ToNewAuthorResponse(), &response)
+	s.reindexAuthor(author.ID)
 
 	return &response, nil
 
 }
 
-// DeleteAuthor use case for delete author
-func (s DefaultAuthorService) DeleteAuthor(id uint) *errs.AppError {
+// DeleteAuthor use case for delete author, its books are restricted, deleted or reassigned by the policy
+func (s DefaultAuthorService) DeleteAuthor(actor domain.Actor, request requests.DeleteAuthorRequest) *errs.AppError {
+	policy := request.OnBooks
+	if policy == "" {
+		policy = authorDeletePolicy()
+	}
+
+	var target *domain.Author
+	if policy == domain.AuthorDeleteReassign {
+		if request.ReassignTo == 0 {
+			return errs.NewValidationError("reassign_to is required to reassign the books")
+		}
+		if request.ReassignTo == request.Id {
+			return errs.NewValidationError("reassign_to must be another author")
+		}
+		// calls repository to validate the new author exists
+		var err *errs.AppError
+		if target, err = s.repo.FindAuthorById(request.ReassignTo); err != nil {
+			if strings.Contains(err.Message, "record not found") {
+				return errs.NewValidationError(fmt.Sprintf("reassign_to %d does not exist", request.ReassignTo))
+			}
+			return err
+		}
+	}
+
+	// the current state is kept for the audit entry
+	before, err := s.repo.FindAuthorById(request.Id)
+	if err != nil {
+		return err
+	}
+
 	// calls repository to delete author
-	if err := s.repo.DeleteAuthor(id); err != nil {
+	books, err := s.repo.DeleteAuthor(request.Id, policy, request.ReassignTo)
+	if err != nil {
 		return err
 	}
+	if len(books) > 0 {
+		logger.Info(fmt.Sprintf("Author id=%d deleted with policy %s, %d books affected", request.Id, policy, len(books)))
+	}
+
+	s.audit.record(actor, authorAuditEntry(domain.AuditAuthorDelete, request.Id), before.ToNewAuthorResponse(), nil)
+	s.search.remove(domain.SearchKindAuthor, request.Id)
+
+	// each book deleted or reassigned has its own entry, as if it was changed through the book endpoints
+	for i := range books {
+		book := &books[i]
+		book.Author = before
+		if policy == domain.AuthorDeleteCascade {
+			s.audit.record(actor, bookAuditEntry(domain.AuditBookDelete, book.ID), book.ToNewBookResponse(), nil)
+			s.search.remove(domain.SearchKindBook, book.ID)
+			continue
+		}
+		previous := book.ToNewBookResponse()
+		book.AuthorID, book.Author = target.ID, target
+		s.audit.record(actor, bookAuditEntry(domain.AuditBookUpdate, book.ID), previous, book.ToNewBookResponse())
+		s.search.put(book.ToSearchDocument())
+	}
 
 	return nil
+}
+
+// reindexAuthor index the author again with its books, they are found by the name of the author
+func (s DefaultAuthorService) reindexAuthor(id uint) {
+	if !s.search.enabled() {
+		return
+	}
+
+	// calls repository to find author by ID with its books
+	author, err := s.repo.FindAuthorByIdWithBooks(id)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Error while indexing author %d: %s", id, err.Message))
+		return
+	}
+
+	s.search.put(author.ToSearchDocument())
+	for _, book := range author.Books {
+		book.Author = author
+		s.search.put(book.ToSearchDocument())
+	}
+}
+
+// authorDeletePolicy default policy for the books of a deleted author, restrict unless AUTHOR_DELETE_POLICY says otherwise
+func authorDeletePolicy() string {
+	policy := os.Getenv("AUTHOR_DELETE_POLICY")
+	if policy == "" {
+		return domain.AuthorDeleteRestrict
+	}
+	if !domain.IsValidAuthorDeletePolicy(policy) {
+		logger.Error(fmt.Sprintf("Invalid AUTHOR_DELETE_POLICY %s, using %s", policy, domain.AuthorDeleteRestrict))
+		return domain.AuthorDeleteRestrict
+	}
+
+	return policy
+}
 
+func authorAuditEntry(action string, id uint) domain.AuditEntry {
+	return domain.AuditEntry{Action: action, TargetType: "author", TargetID: strconv.FormatUint(uint64(id), 10)}
 }
@@ -1,6 +1,7 @@
 package service
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/golang/mock/gomock"
@@ -11,12 +12,15 @@ import (
 )
 
 var mockAuthorRepo *domain.MockAuthorRepository
+var mockAuditRepo *domain.MockAuditRepository
 var authorService AuthorService
+var auditActor = realDomain.Actor{UserID: 1, Email: "edwyn@example.com", ClientInfo: realDomain.ClientInfo{IP: "10.0.0.1", RequestID: "req-1"}}
 
 func authorSetup(t *testing.T) func() {
 	ctrl := gomock.NewController(t)
 	mockAuthorRepo = domain.NewMockAuthorRepository(ctrl)
-	authorService = NewAuthorService(mockAuthorRepo)
+	mockAuditRepo = domain.NewMockAuditRepository(ctrl)
+	authorService = NewAuthorService(mockAuthorRepo, mockAuditRepo, nil)
 	return func() {
 		authorService = nil
 		defer ctrl.Finish()
@@ -38,7 +42,7 @@ func Test_should_return_an_error_from_the_server_side_if_the_new_author_cannot_b
 
 	mockAuthorRepo.EXPECT().SaveAuthor(a).Return(errs.NewUnexpectedError("Unexpected database error"))
 	// Act
-	appError := authorService.CreateAuthor(req)
+	appError := authorService.CreateAuthor(auditActor, req)
 
 	// Assert
 	if appError == nil {
@@ -61,8 +65,9 @@ func Test_should_return_message_response_when_a_new_author_is_saved_successfully
 	}
 
 	mockAuthorRepo.EXPECT().SaveAuthor(a).Return(nil)
+	mockAuditRepo.EXPECT().SaveAuditEntry(gomock.Any()).Return(nil)
 	// Act
-	appError := authorService.CreateAuthor(req)
+	appError := authorService.CreateAuthor(auditActor, req)
 
 	// Assert
 	if appError != nil {
@@ -75,9 +80,9 @@ func Test_should_return_an_error_from_the_server_side_when_get_all_author(t *tes
 	teardown := authorSetup(t)
 	defer teardown()
 
-	mockAuthorRepo.EXPECT().FindAllAuthor().Return(nil, errs.NewUnexpectedError("Unexpected database error"))
+	mockAuthorRepo.EXPECT().FindAllAuthor(gomock.Any()).Return(nil, int64(0), errs.NewUnexpectedError("Unexpected database error"))
 	// Act
-	_, appError := authorService.FindAllAuthor()
+	_, appError := authorService.FindAllAuthor(requests.ListRequest{})
 
 	// Assert
 	if appError == nil {
@@ -95,16 +100,16 @@ func Test_should_return_successfully_response_when_list_author(t *testing.T) {
 		{FullName: "Gabriel García Márquez"},
 	}
 
-	mockAuthorRepo.EXPECT().FindAllAuthor().Return(authors, nil)
+	mockAuthorRepo.EXPECT().FindAllAuthor(gomock.Any()).Return(authors, int64(2), nil)
 
 	// Act
-	listAuthor, appError := authorService.FindAllAuthor()
+	listAuthor, appError := authorService.FindAllAuthor(requests.ListRequest{})
 
 	// Assert
 	if appError != nil {
 		t.Error("Test failed while get author list")
 	}
-	if len(listAuthor) == 0 {
+	if len(listAuthor.Items) == 0 {
 		t.Error("Failed while geting author list")
 	}
 }
@@ -116,7 +121,7 @@ func Test_should_return_an_error_from_the_server_when_get_author_by_id(t *testin
 
 	mockAuthorRepo.EXPECT().FindAuthorById(uint(2)).Return(nil, errs.NewNotFoundError("record not found"))
 	// Act
-	_, appError := authorService.FindAuthorById(2)
+	_, appError := authorService.FindAuthorById(2, "")
 
 	// Assert
 	if appError == nil {
@@ -136,7 +141,7 @@ func Test_should_return_successfully_response_when_find_author_by_id(t *testing.
 	mockAuthorRepo.EXPECT().FindAuthorById(uint(2)).Return(a, nil)
 
 	// Act
-	author, appError := authorService.FindAuthorById(2)
+	author, appError := authorService.FindAuthorById(2, "")
 
 	// Assert
 	if appError != nil {
@@ -159,9 +164,10 @@ func Test_should_return_an_error_from_the_server_side_if_the_author_cannot_be_up
 	a := &realDomain.Author{
 		FullName: "J. J. Benitez",
 	}
+	mockAuthorRepo.EXPECT().FindAuthorById(uint(0)).Return(&realDomain.Author{FullName: "J. J. Benitez"}, nil)
 	mockAuthorRepo.EXPECT().UpdateAuthor(a).Return(nil, errs.NewUnexpectedError("Unexpected database error"))
 	// Act
-	_, appError := authorService.UpdateAuthor(req)
+	_, appError := authorService.UpdateAuthor(auditActor, req)
 
 	// Assert
 	if appError == nil {
@@ -186,9 +192,11 @@ func Test_should_return_author_response_when_a_author_is_updated_successfully(t
 
 	authorWithId := a
 	authorWithId.ID = 2
+	mockAuthorRepo.EXPECT().FindAuthorById(uint(2)).Return(&realDomain.Author{ID: 2, FullName: "J. J. Benitez"}, nil)
 	mockAuthorRepo.EXPECT().UpdateAuthor(a).Return(authorWithId, nil)
+	mockAuditRepo.EXPECT().SaveAuditEntry(gomock.Any()).Return(nil)
 	// Act
-	updateAuthor, appError := authorService.UpdateAuthor(req)
+	updateAuthor, appError := authorService.UpdateAuthor(auditActor, req)
 
 	// Assert
 	if appError != nil {
@@ -205,9 +213,10 @@ func Test_should_return_an_error_from_the_server_side_if_the_author_cannot_be_de
 	teardown := authorSetup(t)
 	defer teardown()
 
-	mockAuthorRepo.EXPECT().DeleteAuthor(uint(1)).Return(errs.NewUnexpectedError("Unexpected database error"))
+	mockAuthorRepo.EXPECT().FindAuthorById(uint(1)).Return(&realDomain.Author{ID: 1, FullName: "J. J. Benitez"}, nil)
+	mockAuthorRepo.EXPECT().DeleteAuthor(uint(1), realDomain.AuthorDeleteRestrict, uint(0)).Return(nil, errs.NewUnexpectedError("Unexpected database error"))
 	// Act
-	appError := authorService.DeleteAuthor(1)
+	appError := authorService.DeleteAuthor(auditActor, requests.DeleteAuthorRequest{Id: 1})
 
 	// Assert
 	if appError == nil {
@@ -221,12 +230,186 @@ func Test_should_return_anything_error_response_when_a_author_is_deleted_success
 	teardown := authorSetup(t)
 	defer teardown()
 
-	mockAuthorRepo.EXPECT().DeleteAuthor(uint(1)).Return(nil)
+	mockAuthorRepo.EXPECT().FindAuthorById(uint(1)).Return(&realDomain.Author{ID: 1, FullName: "J. J. Benitez"}, nil)
+	mockAuthorRepo.EXPECT().DeleteAuthor(uint(1), realDomain.AuthorDeleteRestrict, uint(0)).Return(nil, nil)
+	mockAuditRepo.EXPECT().SaveAuditEntry(gomock.Any()).Return(nil)
 	// Act
-	appError := authorService.DeleteAuthor(1)
+	appError := authorService.DeleteAuthor(auditActor, requests.DeleteAuthorRequest{Id: 1})
 
 	// Assert
 	if appError != nil {
 		t.Error("Test failed while deleting author")
 	}
 }
+
+func Test_should_record_the_actor_and_the_changed_fields_when_a_author_is_updated(t *testing.T) {
+	// Arrange
+	teardown := authorSetup(t)
+	defer teardown()
+
+	req := &requests.AuthorRequest{Id: 2, FullName: "J. J. Benítez"}
+	var entry *realDomain.AuditEntry
+
+	mockAuthorRepo.EXPECT().FindAuthorById(uint(2)).Return(&realDomain.Author{ID: 2, FullName: "J. J. Benitez"}, nil)
+	mockAuthorRepo.EXPECT().UpdateAuthor(gomock.Any()).Return(&realDomain.Author{ID: 2, FullName: "J. J. Benítez"}, nil)
+	mockAuditRepo.EXPECT().SaveAuditEntry(gomock.Any()).DoAndReturn(func(e *realDomain.AuditEntry) *errs.AppError {
+		entry = e
+		return nil
+	})
+	// Act
+	_, appError := authorService.UpdateAuthor(auditActor, req)
+
+	// Assert
+	if appError != nil {
+		t.Fatal("Test failed while updating author")
+	}
+	if entry.Action != realDomain.AuditAuthorUpdate || entry.TargetType != "author" || entry.TargetID != "2" {
+		t.Errorf("Failed while matching audit target: %+v", entry)
+	}
+	if entry.ActorID != 1 || entry.IP != "10.0.0.1" || entry.RequestID != "req-1" {
+		t.Errorf("Failed while matching audit actor: %+v", entry)
+	}
+	if entry.Changes != `{"full_name":{"after":"J. J. Benítez","before":"J. J. Benitez"}}` {
+		t.Errorf("Failed while matching audit changes: %s", entry.Changes)
+	}
+}
+
+func Test_should_not_fail_the_use_case_when_the_audit_entry_cannot_be_saved(t *testing.T) {
+	// Arrange
+	teardown := authorSetup(t)
+	defer teardown()
+
+	mockAuthorRepo.EXPECT().FindAuthorById(uint(1)).Return(&realDomain.Author{ID: 1, FullName: "J. J. Benitez"}, nil)
+	mockAuthorRepo.EXPECT().DeleteAuthor(uint(1), realDomain.AuthorDeleteRestrict, uint(0)).Return(nil, nil)
+	mockAuditRepo.EXPECT().SaveAuditEntry(gomock.Any()).Return(errs.NewUnexpectedError("Unexpected database error"))
+	// Act
+	appError := authorService.DeleteAuthor(auditActor, requests.DeleteAuthorRequest{Id: 1})
+
+	// Assert
+	if appError != nil {
+		t.Error("Test failed while deleting author")
+	}
+}
+
+func Test_should_return_the_books_of_the_author_when_included(t *testing.T) {
+	// Arrange
+	teardown := authorSetup(t)
+	defer teardown()
+
+	a := &realDomain.Author{ID: 2, FullName: "J. J. Benitez", Books: []realDomain.Book{{ID: 1, Title: "Caballo de Troya 1", AuthorID: 2}}}
+
+	mockAuthorRepo.EXPECT().FindAuthorByIdWithBooks(uint(2)).Return(a, nil)
+	// Act
+	author, appError := authorService.FindAuthorById(2, "books")
+
+	// Assert
+	if appError != nil {
+		t.Fatal("Test failed while get author")
+	}
+	if len(author.Books) != 1 || author.Books[0].AuthorName != "J. J. Benitez" {
+		t.Errorf("Failed while matching author books: %+v", author.Books)
+	}
+}
+
+func Test_should_return_status_400_when_including_an_unknown_association(t *testing.T) {
+	// Arrange
+	teardown := authorSetup(t)
+	defer teardown()
+
+	// Act
+	_, appError := authorService.FindAuthorById(2, "reviews")
+
+	// Assert
+	if appError == nil || appError.Code != 400 {
+		t.Error("Test failed while validating include")
+	}
+}
+
+func Test_should_return_status_409_when_an_author_with_books_is_deleted_with_restrict(t *testing.T) {
+	// Arrange
+	teardown := authorSetup(t)
+	defer teardown()
+
+	mockAuthorRepo.EXPECT().FindAuthorById(uint(1)).Return(&realDomain.Author{ID: 1, FullName: "J. J. Benitez"}, nil)
+	mockAuthorRepo.EXPECT().DeleteAuthor(uint(1), realDomain.AuthorDeleteRestrict, uint(0)).
+		Return(nil, errs.NewConflictError("Author has 2 books, delete or reassign them first"))
+	// Act
+	appError := authorService.DeleteAuthor(auditActor, requests.DeleteAuthorRequest{Id: 1, OnBooks: realDomain.AuthorDeleteRestrict})
+
+	// Assert
+	if appError == nil || appError.Code != 409 {
+		t.Error("Test failed while deleting author")
+	}
+}
+
+func Test_should_use_the_policy_of_the_env_when_the_request_has_none(t *testing.T) {
+	// Arrange
+	teardown := authorSetup(t)
+	defer teardown()
+	t.Setenv("AUTHOR_DELETE_POLICY", realDomain.AuthorDeleteCascade)
+
+	mockAuthorRepo.EXPECT().FindAuthorById(uint(1)).Return(&realDomain.Author{ID: 1, FullName: "J. J. Benitez"}, nil)
+	books := []realDomain.Book{{ID: 7, Title: "Caballo de Troya 1", AuthorID: 1}, {ID: 8, Title: "Caballo de Troya 2", AuthorID: 1}}
+	entries := make([]*realDomain.AuditEntry, 0)
+
+	mockAuthorRepo.EXPECT().DeleteAuthor(uint(1), realDomain.AuthorDeleteCascade, uint(0)).Return(books, nil)
+	mockAuditRepo.EXPECT().SaveAuditEntry(gomock.Any()).Do(func(e *realDomain.AuditEntry) { entries = append(entries, e) }).Return(nil).Times(3)
+	// Act
+	appError := authorService.DeleteAuthor(auditActor, requests.DeleteAuthorRequest{Id: 1})
+
+	// Assert
+	if appError != nil {
+		t.Fatal("Test failed while deleting author")
+	}
+	if entries[0].Action != realDomain.AuditAuthorDelete || entries[1].Action != realDomain.AuditBookDelete ||
+		entries[1].TargetID != "7" || entries[2].TargetID != "8" ||
+		!strings.Contains(entries[1].Changes, `"title":{"before":"Caballo de Troya 1"}`) {
+		t.Errorf("Failed while recording the deleted books: %+v", entries)
+	}
+}
+
+func Test_should_reassign_the_books_to_an_existing_author(t *testing.T) {
+	// Arrange
+	teardown := authorSetup(t)
+	defer teardown()
+
+	mockAuthorRepo.EXPECT().FindAuthorById(uint(2)).Return(&realDomain.Author{ID: 2, FullName: "Isaac Asimov"}, nil)
+	mockAuthorRepo.EXPECT().FindAuthorById(uint(1)).Return(&realDomain.Author{ID: 1, FullName: "J. J. Benitez"}, nil)
+	mockAuthorRepo.EXPECT().DeleteAuthor(uint(1), realDomain.AuthorDeleteReassign, uint(2)).
+		Return([]realDomain.Book{{ID: 7, Title: "Caballo de Troya 1", AuthorID: 1}}, nil)
+	entries := make([]*realDomain.AuditEntry, 0)
+	mockAuditRepo.EXPECT().SaveAuditEntry(gomock.Any()).Do(func(e *realDomain.AuditEntry) { entries = append(entries, e) }).Return(nil).Times(2)
+	// Act
+	appError := authorService.DeleteAuthor(auditActor, requests.DeleteAuthorRequest{Id: 1, OnBooks: realDomain.AuthorDeleteReassign, ReassignTo: 2})
+
+	// Assert
+	if appError != nil {
+		t.Fatal("Test failed while deleting author")
+	}
+	if entries[1].Action != realDomain.AuditBookUpdate || entries[1].TargetID != "7" ||
+		entries[1].Changes != `{"author_id":{"after":2,"before":1},"author_name":{"after":"Isaac Asimov","before":"J. J. Benitez"}}` {
+		t.Errorf("Failed while recording the reassigned book: %+v", entries[1])
+	}
+}
+
+func Test_should_return_status_422_when_the_books_are_reassigned_to_an_invalid_author(t *testing.T) {
+	// Arrange
+	teardown := authorSetup(t)
+	defer teardown()
+
+	mockAuthorRepo.EXPECT().FindAuthorById(uint(3)).Return(nil, errs.NewNotFoundError("record not found"))
+
+	for _, req := range []requests.DeleteAuthorRequest{
+		{Id: 1, OnBooks: realDomain.AuthorDeleteReassign},
+		{Id: 1, OnBooks: realDomain.AuthorDeleteReassign, ReassignTo: 1},
+		{Id: 1, OnBooks: realDomain.AuthorDeleteReassign, ReassignTo: 3},
+	} {
+		// Act
+		appError := authorService.DeleteAuthor(auditActor, req)
+
+		// Assert
+		if appError == nil || appError.Code != 422 || !strings.Contains(appError.Message, "reassign_to") {
+			t.Errorf("Test failed while validating %+v: %+v", req, appError)
+		}
+	}
+}
@@ -1,58 +1,130 @@
 package service
 
 import (
+	"fmt"
+	"strconv"
+	"strings"
+
 	"github.com/karlbehrensg/go-fiber-template/internal/domain"
 	"github.com/karlbehrensg/go-fiber-template/internal/http/requests"
 	"github.com/karlbehrensg/go-fiber-template/internal/http/responses"
 	"github.com/karlbehrensg/go-fiber-template/pkg/errs"
+	"github.com/karlbehrensg/go-fiber-template/pkg/utils"
 )
 
 // BookService port primary
 type BookService interface {
-	CreateBook(requests.BookRequest) *errs.AppError
-	FindAllBook() ([]responses.BookResponse, *errs.AppError)
+	CreateBook(domain.Actor, requests.BookRequest) *errs.AppError
+	FindAllBook(requests.ListRequest) (*responses.BookListResponse, *errs.AppError)
+	FindBooksByAuthor(authorID uint, request requests.ListRequest) (*responses.BookListResponse, *errs.AppError)
 	FindBookById(uint) (*responses.BookResponse, *errs.AppError)
-	UpdateBook(*requests.BookRequest) (*responses.BookResponse, *errs.AppError)
-	DeleteBook(uint) *errs.AppError
+	FindBookByISBN(string) (*responses.BookResponse, *errs.AppError)
+	UpdateBook(domain.Actor, *requests.BookRequest) (*responses.BookResponse, *errs.AppError)
+	DeleteBook(domain.Actor, uint) *errs.AppError
 }
 
 type DefaultBookService struct {
-	repo domain.BookRepository
+	repo       domain.BookRepository
+	authorRepo domain.AuthorRepository
+	audit      auditLog
+	search     searchSync
 }
 
-// NewBookService create a new instance of DefaultBookService
-func NewBookService(repository domain.BookRepository) DefaultBookService {
-	return DefaultBookService{repository}
+// NewBookService create a new instance of DefaultBookService, the index can be nil
+func NewBookService(
+	repository domain.BookRepository,
+	authorRepository domain.AuthorRepository,
+	auditRepository domain.AuditRepository,
+	searchIndex domain.SearchIndex,
+) DefaultBookService {
+	return DefaultBookService{repository, authorRepository, newAuditLog(auditRepository), newSearchSync(searchIndex)}
 }
 
 // CreateBook use case for create book
-func (s DefaultBookService) CreateBook(request requests.BookRequest) *errs.AppError {
+func (s DefaultBookService) CreateBook(actor domain.Actor, request requests.BookRequest) *errs.AppError {
+	author, err := s.validateAuthor(request.AuthorID)
+	if err != nil {
+		return err
+	}
+
 	Book := &domain.Book{
 		Title:           request.Title,
 		AuthorID:        request.AuthorID,
 		PublicationYear: request.PublicationYear,
 	}
+	if request.ISBN != nil {
+		if Book.ISBN13, Book.ISBN10, err = bookISBN(*request.ISBN); err != nil {
+			return err
+		}
+	}
 
 	// calls repository to save book
-	if err := s.repo.SaveBook(Book); err != nil {
+	if err = s.repo.SaveBook(Book); err != nil {
 		return err
 	}
 
+	s.audit.record(actor, bookAuditEntry(domain.AuditBookCreate, Book.ID), nil, Book.ToNewBookResponse())
+	Book.Author = author
+	s.search.put(Book.ToSearchDocument())
+
 	return nil
 }
 
-// FindAllBook use case for find all book
-func (s DefaultBookService) FindAllBook() ([]responses.BookResponse, *errs.AppError) {
-	var Books []domain.Book
-	var err *errs.AppError
-	// calls repository to find all book
-	if Books, err = s.repo.FindAllBook(); err != nil {
-		return nil, err
+// bookListSchema fields of GET /book
+var bookListSchema = listSchema{
+	fields: map[string]listField{
+		"id":    {column: "id", numeric: true, sortable: true},
+		"title": {column: "title", sortable: true, operators: []string{domain.ListOperatorEqual, domain.ListOperatorContains}},
+		"publication_year": {
+			column:    "publication_year",
+			integer:   true,
+			sortable:  true,
+			operators: []string{domain.ListOperatorEqual, domain.ListOperatorGreaterEqual, domain.ListOperatorLessEqual},
+		},
+		"author_id": {column: "author_id", numeric: true, sortable: true, operators: []string{domain.ListOperatorEqual}},
+	},
+}
+
+// FindAllBook use case for find a page of books
+func (s DefaultBookService) FindAllBook(request requests.ListRequest) (*responses.BookListResponse, *errs.AppError) {
+	return s.findBooks(request)
+}
+
+// FindBooksByAuthor use case for find a page of the books of the author
+func (s DefaultBookService) FindBooksByAuthor(authorID uint, request requests.ListRequest) (*responses.BookListResponse, *errs.AppError) {
+	// calls repository to validate the author exists
+	if _, appErr := s.authorRepo.FindAuthorById(authorID); appErr != nil {
+		return nil, appErr
 	}
 
-	response := make([]responses.BookResponse, 0)
-	for _, Book := range Books {
-		response = append(response, *Book.ToNewBookResponse())
+	return s.findBooks(request, domain.ListFilter{Column: "author_id", Operator: domain.ListOperatorEqual, Value: authorID})
+}
+
+// findBooks find a page of books, the filters are added to the ones of the request and left out of the links
+func (s DefaultBookService) findBooks(request requests.ListRequest, filters ...domain.ListFilter) (*responses.BookListResponse, *errs.AppError) {
+	plan, appErr := newListPlan(bookListSchema, request)
+	if appErr != nil {
+		return nil, appErr
+	}
+	plan.query.Filters = append(plan.query.Filters, filters...)
+
+	// calls repository to find the page of books
+	Books, total, appErr := s.repo.FindAllBook(plan.query)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	n, more := plan.page(Books)
+	response := &responses.BookListResponse{
+		Items: make([]responses.BookResponse, 0, n),
+		Total: total,
+		Limit: plan.limit,
+		Links: plan.links(n, more, total, func(i int, column string) interface{} {
+			return Books[i].ListValue(column)
+		}),
+	}
+	for _, Book := range Books[:n] {
+		response.Items = append(response.Items, *Book.ToNewBookResponse())
 	}
 
 	return response, nil
@@ -72,34 +144,117 @@ func (s DefaultBookService) FindBookById(id uint) (*responses.BookResponse, *err
 	return &response, nil
 }
 
+// FindBookByISBN use case for find book by ISBN-10 or ISBN-13
+func (s DefaultBookService) FindBookByISBN(isbn string) (*responses.BookResponse, *errs.AppError) {
+	isbn13, err := utils.ISBN13(isbn)
+	if err != nil {
+		return nil, errs.NewBadRequestError(fmt.Sprintf("invalid isbn %s", isbn))
+	}
+
+	// calls repository to find book by ISBN-13
+	Book, appErr := s.repo.FindBookByISBN13(isbn13)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	return Book.ToNewBookResponse(), nil
+}
+
 // UpdateAuthor use case for update book
-func (s DefaultBookService) UpdateBook(request *requests.BookRequest) (*responses.BookResponse, *errs.AppError) {
+func (s DefaultBookService) UpdateBook(actor domain.Actor, request *requests.BookRequest) (*responses.BookResponse, *errs.AppError) {
+	// the current state is kept for the audit entry
+	before, err := s.repo.FindBookById(request.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err = s.validateAuthor(request.AuthorID); err != nil {
+		return nil, err
+	}
+
 	Book := &domain.Book{
 		ID:              request.Id,
 		Title:           request.Title,
 		AuthorID:        request.AuthorID,
 		PublicationYear: request.PublicationYear,
 	}
+	// without isbn in the request the stored one is kept
+	Book.ISBN13, Book.ISBN10 = before.ISBN13, before.ISBN10
+	if request.ISBN != nil {
+		if Book.ISBN13, Book.ISBN10, err = bookISBN(*request.ISBN); err != nil {
+			return nil, err
+		}
+	}
 
-	var err *errs.AppError
 	// calls repository to update author
-	if Book, err = s.repo.UpdateBook(Book); err != nil {
+	if _, err = s.repo.UpdateBook(Book); err != nil {
+		return nil, err
+	}
+
+	// the book is read again to return its author
+	if Book, err = s.repo.FindBookById(request.Id); err != nil {
 		return nil, err
 	}
 
 	response := *Book.ToNewBookResponse()
+	s.audit.record(actor, bookAuditEntry(domain.AuditBookUpdate, Book.ID), before.ToNewBookResponse(), &response)
+	s.search.put(Book.ToSearchDocument())
 
 	return &response, nil
 
 }
 
 // DeleteBook use case for delete book
-func (s DefaultBookService) DeleteBook(id uint) *errs.AppError {
+func (s DefaultBookService) DeleteBook(actor domain.Actor, id uint) *errs.AppError {
+	// the current state is kept for the audit entry
+	before, err := s.repo.FindBookById(id)
+	if err != nil {
+		return err
+	}
+
 	// calls repository to delete book
-	if err := s.repo.DeleteBook(id); err != nil {
+	if err = s.repo.DeleteBook(id); err != nil {
 		return err
 	}
 
+	s.audit.record(actor, bookAuditEntry(domain.AuditBookDelete, id), before.ToNewBookResponse(), nil)
+	s.search.remove(domain.SearchKindBook, id)
+
 	return nil
 
 }
+
+// bookISBN normalize the ISBN of the request to ISBN-13 and its ISBN-10, both are nil without ISBN
+func bookISBN(isbn string) (*string, *string, *errs.AppError) {
+	if isbn == "" {
+		return nil, nil, nil
+	}
+
+	isbn13, err := utils.ISBN13(isbn)
+	if err != nil {
+		return nil, nil, errs.NewValidationError(fmt.Sprintf("invalid isbn %s", isbn))
+	}
+	if isbn10, ok := utils.ISBN10(isbn13); ok {
+		return &isbn13, &isbn10, nil
+	}
+
+	return &isbn13, nil, nil
+}
+
+// validateAuthor validate the author of the book exists, deleted authors can not receive books
+func (s DefaultBookService) validateAuthor(authorID uint) (*domain.Author, *errs.AppError) {
+	// calls repository to find author by ID
+	author, err := s.authorRepo.FindAuthorById(authorID)
+	if err != nil {
+		if strings.Contains(err.Message, "record not found") {
+			return nil, errs.NewValidationError(fmt.Sprintf("author_id %d does not exist", authorID))
+		}
+		return nil, err
+	}
+
+	return author, nil
+}
+
+func bookAuditEntry(action string, id uint) domain.AuditEntry {
+	return domain.AuditEntry{Action: action, TargetType: "book", TargetID: strconv.FormatUint(uint64(id), 10)}
+}
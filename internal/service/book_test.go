@@ -0,0 +1,287 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	realDomain "github.com/karlbehrensg/go-fiber-template/internal/domain"
+	"github.com/karlbehrensg/go-fiber-template/internal/http/requests"
+	"github.com/karlbehrensg/go-fiber-template/mocks/domain"
+	"github.com/karlbehrensg/go-fiber-template/pkg/errs"
+)
+
+var mockBookRepo *domain.MockBookRepository
+var bookService BookService
+
+func bookSetup(t *testing.T) func() {
+	ctrl := gomock.NewController(t)
+	mockBookRepo = domain.NewMockBookRepository(ctrl)
+	mockAuthorRepo = domain.NewMockAuthorRepository(ctrl)
+	mockAuditRepo = domain.NewMockAuditRepository(ctrl)
+	bookService = NewBookService(mockBookRepo, mockAuthorRepo, mockAuditRepo, nil)
+	return func() {
+		bookService = nil
+		defer ctrl.Finish()
+	}
+}
+
+func Test_should_record_the_new_book_when_a_book_is_saved_successfully(t *testing.T) {
+	// Arrange
+	teardown := bookSetup(t)
+	defer teardown()
+
+	req := requests.BookRequest{Title: "Caballo de Troya 1", AuthorID: 30, PublicationYear: "1984"}
+	var entry *realDomain.AuditEntry
+
+	mockAuthorRepo.EXPECT().FindAuthorById(uint(30)).Return(&realDomain.Author{ID: 30}, nil)
+	mockBookRepo.EXPECT().SaveBook(gomock.Any()).DoAndReturn(func(b *realDomain.Book) *errs.AppError {
+		b.ID = 1
+		return nil
+	})
+	mockAuditRepo.EXPECT().SaveAuditEntry(gomock.Any()).DoAndReturn(func(e *realDomain.AuditEntry) *errs.AppError {
+		entry = e
+		return nil
+	})
+	// Act
+	appError := bookService.CreateBook(auditActor, req)
+
+	// Assert
+	if appError != nil {
+		t.Fatal("Test failed while creating book")
+	}
+	if entry.Action != realDomain.AuditBookCreate || entry.TargetID != "1" || entry.Changes == "" {
+		t.Errorf("Failed while matching audit entry: %+v", entry)
+	}
+}
+
+func Test_should_return_status_422_naming_author_id_when_the_author_of_a_new_book_does_not_exist(t *testing.T) {
+	// Arrange
+	teardown := bookSetup(t)
+	defer teardown()
+
+	req := requests.BookRequest{Title: "Caballo de Troya 1", AuthorID: 30, PublicationYear: "1984"}
+
+	mockAuthorRepo.EXPECT().FindAuthorById(uint(30)).Return(nil, errs.NewNotFoundError("record not found"))
+	// Act
+	appError := bookService.CreateBook(auditActor, req)
+
+	// Assert
+	if appError == nil || appError.Code != 422 || !strings.Contains(appError.Message, "author_id 30") {
+		t.Errorf("Test failed while validating the author: %+v", appError)
+	}
+}
+
+func Test_should_return_status_422_when_a_book_is_moved_to_an_author_that_does_not_exist(t *testing.T) {
+	// Arrange
+	teardown := bookSetup(t)
+	defer teardown()
+
+	req := &requests.BookRequest{Id: 1, Title: "Caballo de Troya 1", AuthorID: 31, PublicationYear: "1984"}
+
+	mockBookRepo.EXPECT().FindBookById(uint(1)).Return(&realDomain.Book{ID: 1, AuthorID: 30}, nil)
+	mockAuthorRepo.EXPECT().FindAuthorById(uint(31)).Return(nil, errs.NewNotFoundError("record not found"))
+	// Act
+	_, appError := bookService.UpdateBook(auditActor, req)
+
+	// Assert
+	if appError == nil || appError.Code != 422 || !strings.Contains(appError.Message, "author_id 31") {
+		t.Errorf("Test failed while validating the author: %+v", appError)
+	}
+}
+
+func Test_should_return_an_error_from_the_server_side_when_get_all_book(t *testing.T) {
+	// Arrange
+	teardown := bookSetup(t)
+	defer teardown()
+
+	mockBookRepo.EXPECT().FindAllBook(gomock.Any()).Return(nil, int64(0), errs.NewUnexpectedError("Unexpected database error"))
+	// Act
+	_, appError := bookService.FindAllBook(requests.ListRequest{})
+
+	// Assert
+	if appError == nil {
+		t.Error("Test failed while validating error for list book")
+	}
+}
+
+func Test_should_return_the_author_name_when_find_book_by_id(t *testing.T) {
+	// Arrange
+	teardown := bookSetup(t)
+	defer teardown()
+
+	b := &realDomain.Book{ID: 1, Title: "Caballo de Troya 1", AuthorID: 30, Author: &realDomain.Author{ID: 30, FullName: "J. J. Benítez"}}
+
+	mockBookRepo.EXPECT().FindBookById(uint(1)).Return(b, nil)
+	// Act
+	book, appError := bookService.FindBookById(1)
+
+	// Assert
+	if appError != nil {
+		t.Fatal("Test failed while get book")
+	}
+	if book.AuthorName != "J. J. Benítez" {
+		t.Error("Failed while matching author name")
+	}
+}
+
+func Test_should_filter_by_the_author_without_adding_it_to_the_links(t *testing.T) {
+	// Arrange
+	teardown := bookSetup(t)
+	defer teardown()
+
+	var query realDomain.ListQuery
+	books := []realDomain.Book{{ID: 1, AuthorID: 30}, {ID: 2, AuthorID: 30}}
+
+	mockAuthorRepo.EXPECT().FindAuthorById(uint(30)).Return(&realDomain.Author{ID: 30}, nil)
+	mockBookRepo.EXPECT().FindAllBook(gomock.Any()).DoAndReturn(func(q realDomain.ListQuery) ([]realDomain.Book, int64, *errs.AppError) {
+		query = q
+		return books, 3, nil
+	})
+	// Act
+	response, appError := bookService.FindBooksByAuthor(30, requests.ListRequest{Limit: 1, Path: "/author/30/books"})
+
+	// Assert
+	if appError != nil {
+		t.Fatal("Test failed while listing the books of the author")
+	}
+	if len(query.Filters) != 1 || query.Filters[0].Column != "author_id" || query.Filters[0].Value != uint(30) {
+		t.Errorf("Failed while matching author filter: %+v", query.Filters)
+	}
+	if strings.Contains(response.Links.Next, "author_id") || !strings.HasPrefix(response.Links.Next, "/author/30/books?") {
+		t.Errorf("Failed while matching next link: %s", response.Links.Next)
+	}
+}
+
+func Test_should_return_status_404_when_listing_the_books_of_a_missing_author(t *testing.T) {
+	// Arrange
+	teardown := bookSetup(t)
+	defer teardown()
+
+	mockAuthorRepo.EXPECT().FindAuthorById(uint(30)).Return(nil, errs.NewNotFoundError("record not found"))
+	// Act
+	_, appError := bookService.FindBooksByAuthor(30, requests.ListRequest{})
+
+	// Assert
+	if appError == nil || appError.Code != 404 {
+		t.Error("Test failed while validating missing author")
+	}
+}
+
+func Test_should_store_the_isbn_as_isbn_13_with_its_isbn_10(t *testing.T) {
+	// Arrange
+	teardown := bookSetup(t)
+	defer teardown()
+
+	isbn := "0-306-40615-2"
+	req := requests.BookRequest{Title: "Caballo de Troya 1", AuthorID: 30, PublicationYear: "1984", ISBN: &isbn}
+	var saved *realDomain.Book
+
+	mockAuthorRepo.EXPECT().FindAuthorById(uint(30)).Return(&realDomain.Author{ID: 30}, nil)
+	mockBookRepo.EXPECT().SaveBook(gomock.Any()).DoAndReturn(func(b *realDomain.Book) *errs.AppError {
+		saved = b
+		return nil
+	})
+	mockAuditRepo.EXPECT().SaveAuditEntry(gomock.Any()).Return(nil)
+	// Act
+	appError := bookService.CreateBook(auditActor, req)
+
+	// Assert
+	if appError != nil {
+		t.Fatal("Test failed while creating book")
+	}
+	if saved.ISBN13 == nil || *saved.ISBN13 != "9780306406157" || saved.ISBN10 == nil || *saved.ISBN10 != "0306406152" {
+		t.Errorf("Failed while matching ISBN: %v %v", saved.ISBN13, saved.ISBN10)
+	}
+}
+
+func Test_should_keep_the_stored_isbn_when_update_book_without_isbn(t *testing.T) {
+	// Arrange
+	teardown := bookSetup(t)
+	defer teardown()
+
+	isbn13, isbn10 := "9780306406157", "0306406152"
+	stored := &realDomain.Book{ID: 1, AuthorID: 30, ISBN13: &isbn13, ISBN10: &isbn10}
+	req := &requests.BookRequest{Id: 1, Title: "Caballo de Troya 1", AuthorID: 30, PublicationYear: "1984"}
+	var updated *realDomain.Book
+
+	mockBookRepo.EXPECT().FindBookById(uint(1)).Return(stored, nil).Times(2)
+	mockAuthorRepo.EXPECT().FindAuthorById(uint(30)).Return(&realDomain.Author{ID: 30}, nil)
+	mockBookRepo.EXPECT().UpdateBook(gomock.Any()).DoAndReturn(func(b *realDomain.Book) (*realDomain.Book, *errs.AppError) {
+		updated = b
+		return b, nil
+	})
+	mockAuditRepo.EXPECT().SaveAuditEntry(gomock.Any()).Return(nil)
+	// Act
+	_, appError := bookService.UpdateBook(auditActor, req)
+
+	// Assert
+	if appError != nil {
+		t.Fatal("Test failed while updating book")
+	}
+	if updated.ISBN13 == nil || *updated.ISBN13 != isbn13 || updated.ISBN10 == nil || *updated.ISBN10 != isbn10 {
+		t.Errorf("Failed while keeping ISBN: %v %v", updated.ISBN13, updated.ISBN10)
+	}
+}
+
+func Test_should_clear_the_isbn_when_update_book_with_an_empty_isbn(t *testing.T) {
+	// Arrange
+	teardown := bookSetup(t)
+	defer teardown()
+
+	isbn13, empty := "9780306406157", ""
+	stored := &realDomain.Book{ID: 1, AuthorID: 30, ISBN13: &isbn13}
+	req := &requests.BookRequest{Id: 1, Title: "Caballo de Troya 1", AuthorID: 30, PublicationYear: "1984", ISBN: &empty}
+	var updated *realDomain.Book
+
+	mockBookRepo.EXPECT().FindBookById(uint(1)).Return(stored, nil).Times(2)
+	mockAuthorRepo.EXPECT().FindAuthorById(uint(30)).Return(&realDomain.Author{ID: 30}, nil)
+	mockBookRepo.EXPECT().UpdateBook(gomock.Any()).DoAndReturn(func(b *realDomain.Book) (*realDomain.Book, *errs.AppError) {
+		updated = b
+		return b, nil
+	})
+	mockAuditRepo.EXPECT().SaveAuditEntry(gomock.Any()).Return(nil)
+	// Act
+	_, appError := bookService.UpdateBook(auditActor, req)
+
+	// Assert
+	if appError != nil {
+		t.Fatal("Test failed while updating book")
+	}
+	if updated.ISBN13 != nil || updated.ISBN10 != nil {
+		t.Errorf("Failed while clearing ISBN: %v %v", updated.ISBN13, updated.ISBN10)
+	}
+}
+
+func Test_should_find_the_book_by_isbn_10_or_isbn_13(t *testing.T) {
+	// Arrange
+	teardown := bookSetup(t)
+	defer teardown()
+
+	isbn13 := "9780306406157"
+	mockBookRepo.EXPECT().FindBookByISBN13(isbn13).Return(&realDomain.Book{ID: 1, ISBN13: &isbn13}, nil).Times(2)
+
+	for _, isbn := range []string{"0-306-40615-2", "978-0-306-40615-7"} {
+		// Act
+		book, appError := bookService.FindBookByISBN(isbn)
+
+		// Assert
+		if appError != nil || book.ISBN13 != isbn13 {
+			t.Errorf("Test failed while finding book by %s", isbn)
+		}
+	}
+}
+
+func Test_should_return_status_400_when_find_book_by_an_invalid_isbn(t *testing.T) {
+	// Arrange
+	teardown := bookSetup(t)
+	defer teardown()
+
+	// Act
+	_, appError := bookService.FindBookByISBN("978-0-306-40615-8")
+
+	// Assert
+	if appError == nil || appError.Code != 400 {
+		t.Error("Test failed while validating isbn")
+	}
+}
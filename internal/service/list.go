@@ -0,0 +1,321 @@
+package service
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/karlbehrensg/go-fiber-template/internal/domain"
+	"github.com/karlbehrensg/go-fiber-template/internal/http/requests"
+	"github.com/karlbehrensg/go-fiber-template/internal/http/responses"
+	"github.com/karlbehrensg/go-fiber-template/pkg/errs"
+)
+
+// listField field of a resource exposed by a list endpoint, integer is a text column holding whole numbers
+// that is filtered and sorted as a number
+type listField struct {
+	column    string
+	numeric   bool
+	integer   bool
+	sortable  bool
+	operators []string
+}
+
+// listSchema fields accepted in the sort and the filters of a list endpoint and associations accepted in include,
+// by query string name
+type listSchema struct {
+	fields  map[string]listField
+	include map[string]string
+}
+
+// listCursor opaque position of a keyset cursor, it is only valid for the sort it was created with
+type listCursor struct {
+	Sort     string        `json:"s"`
+	Values   []interface{} `json:"v"`
+	Backward bool          `json:"b,omitempty"`
+}
+
+// listPlan query of a list request and what is needed to build the links to the pages around it
+type listPlan struct {
+	request  requests.ListRequest
+	query    domain.ListQuery
+	limit    int
+	sort     string
+	backward bool
+}
+
+// newListPlan validate the sort, the filters and the cursor of the request against the schema
+func newListPlan(schema listSchema, request requests.ListRequest) (*listPlan, *errs.AppError) {
+	plan := &listPlan{request: request, limit: request.Limit}
+	if plan.limit < 1 {
+		plan.limit = 20
+	}
+
+	var appErr *errs.AppError
+	if plan.query.Sort, plan.sort, appErr = parseListSort(schema, request.Sort); appErr != nil {
+		return nil, appErr
+	}
+	if plan.query.Filters, appErr = parseListFilters(schema, request.Filters); appErr != nil {
+		return nil, appErr
+	}
+	if plan.query.Include, appErr = parseListInclude(schema, request.Include); appErr != nil {
+		return nil, appErr
+	}
+
+	// page numbers use offsets, otherwise one more row is fetched to know if there is a next page
+	if request.Page > 0 {
+		plan.query.Offset = (request.Page - 1) * plan.limit
+		plan.query.Limit = plan.limit
+		return plan, nil
+	}
+	plan.query.Limit = plan.limit + 1
+
+	if request.Cursor == "" {
+		return plan, nil
+	}
+
+	cursor, appErr := decodeListCursor(schema, request.Cursor, plan.query.Sort, plan.sort)
+	if appErr != nil {
+		return nil, appErr
+	}
+	plan.query.After = &domain.ListCursor{Values: cursor.Values}
+
+	// the previous page is read backwards from the cursor and reversed afterwards
+	if plan.backward = cursor.Backward; plan.backward {
+		for i := range plan.query.Sort {
+			plan.query.Sort[i].Desc = !plan.query.Sort[i].Desc
+		}
+	}
+
+	return plan, nil
+}
+
+// page put back in order the rows of a previous page, items must be the slice of rows fetched,
+// returns how many rows belong to the page and if the extra row was found
+func (p *listPlan) page(items interface{}) (int, bool) {
+	n := reflect.ValueOf(items).Len()
+	more := p.request.Page == 0 && n > p.limit
+	if more {
+		n = p.limit
+	}
+
+	if p.backward {
+		// the extra row is the last one, it is left out of the reverse
+		swap := reflect.Swapper(items)
+		for i := 0; i < n/2; i++ {
+			swap(i, n-1-i)
+		}
+	}
+
+	return n, more
+}
+
+// links create the links to the pages around the n rows of the page, value return the column of the row i
+func (p *listPlan) links(n int, more bool, total int64, value func(i int, column string) interface{}) responses.ListLinks {
+	links := responses.ListLinks{}
+
+	if p.request.Page > 0 {
+		if int64(p.query.Offset+n) < total {
+			links.Next = p.link("page", strconv.Itoa(p.request.Page+1))
+		}
+		if p.request.Page > 1 {
+			links.Prev = p.link("page", strconv.Itoa(p.request.Page-1))
+		}
+		return links
+	}
+
+	if n == 0 {
+		return links
+	}
+
+	// reading forward there is a next page when the extra row was found, backward the same applies to the previous one
+	hasNext, hasPrev := more, p.request.Cursor != ""
+	if p.backward {
+		hasNext, hasPrev = true, more
+	}
+	if hasNext {
+		links.Next = p.link("cursor", p.encodeCursor(n-1, false, value))
+	}
+	if hasPrev {
+		links.Prev = p.link("cursor", p.encodeCursor(0, true, value))
+	}
+
+	return links
+}
+
+// link create the URL of the list keeping the sort, the filters and the limit of the request
+func (p *listPlan) link(name string, value string) string {
+	query := url.Values{}
+	for key, filter := range p.request.Filters {
+		query.Set(key, filter)
+	}
+	if p.request.Sort != "" {
+		query.Set("sort", p.request.Sort)
+	}
+	if p.request.Limit > 0 {
+		query.Set("limit", strconv.Itoa(p.request.Limit))
+	}
+	if p.request.Include != "" {
+		query.Set("include", p.request.Include)
+	}
+	query.Set(name, value)
+
+	return p.request.Path + "?" + query.Encode()
+}
+
+// encodeCursor create the cursor of the row i
+func (p *listPlan) encodeCursor(i int, backward bool, value func(i int, column string) interface{}) string {
+	cursor := listCursor{Sort: p.sort, Values: make([]interface{}, 0, len(p.query.Sort)), Backward: backward}
+	for _, s := range p.query.Sort {
+		cursor.Values = append(cursor.Values, value(i, s.Column))
+	}
+
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// parseListSort parse "-publication_year,title", the ID is added as the last column so the order is unique
+func parseListSort(schema listSchema, value string) ([]domain.ListSort, string, *errs.AppError) {
+	sorts := make([]domain.ListSort, 0)
+	names := make([]string, 0)
+	seen := map[string]bool{}
+	for _, name := range strings.Split(value, ",") {
+		name = strings.TrimSpace(name)
+		desc := strings.HasPrefix(name, "-")
+		name = strings.TrimPrefix(name, "-")
+		if name == "" || seen[name] {
+			continue
+		}
+
+		field, ok := schema.fields[name]
+		if !ok || !field.sortable {
+			return nil, "", errs.NewBadRequestError(fmt.Sprintf("unknown sort field %s", name))
+		}
+		seen[name] = true
+		sorts = append(sorts, domain.ListSort{Column: field.column, Desc: desc, Integer: field.integer})
+		if desc {
+			name = "-" + name
+		}
+		names = append(names, name)
+	}
+
+	if !seen["id"] {
+		sorts = append(sorts, domain.ListSort{Column: schema.fields["id"].column})
+		names = append(names, "id")
+	}
+
+	return sorts, strings.Join(names, ","), nil
+}
+
+// parseListFilters parse the filters "field=value" and "field[operator]=value"
+func parseListFilters(schema listSchema, values map[string]string) ([]domain.ListFilter, *errs.AppError) {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	filters := make([]domain.ListFilter, 0, len(keys))
+	for _, key := range keys {
+		name, operator := key, domain.ListOperatorEqual
+		if i := strings.Index(key, "["); i > 0 && strings.HasSuffix(key, "]") {
+			name, operator = key[:i], key[i+1:len(key)-1]
+		}
+
+		field, ok := schema.fields[name]
+		if !ok || !containsString(field.operators, operator) {
+			return nil, errs.NewBadRequestError(fmt.Sprintf("unknown filter %s", key))
+		}
+
+		var value interface{} = values[key]
+		if field.numeric || field.integer {
+			number, err := strconv.ParseUint(values[key], 10, 64)
+			if err != nil {
+				return nil, errs.NewBadRequestError(fmt.Sprintf("invalid value for filter %s", key))
+			}
+			value = number
+		}
+
+		filters = append(filters, domain.ListFilter{Column: field.column, Operator: operator, Value: value, Integer: field.integer})
+	}
+
+	return filters, nil
+}
+
+// parseListInclude parse the comma separated associations to load with the rows
+func parseListInclude(schema listSchema, value string) ([]string, *errs.AppError) {
+	include := make([]string, 0)
+	for _, name := range strings.Split(value, ",") {
+		if name = strings.TrimSpace(name); name == "" {
+			continue
+		}
+
+		association, ok := schema.include[name]
+		if !ok {
+			return nil, errs.NewBadRequestError(fmt.Sprintf("unknown include %s", name))
+		}
+		include = append(include, association)
+	}
+
+	return include, nil
+}
+
+// decodeListCursor decode the cursor and validate it was created for the same sort
+func decodeListCursor(schema listSchema, value string, sorts []domain.ListSort, sortName string) (*listCursor, *errs.AppError) {
+	invalid := errs.NewBadRequestError("invalid cursor")
+
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, invalid
+	}
+
+	var cursor listCursor
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err = decoder.Decode(&cursor); err != nil || cursor.Sort != sortName || len(cursor.Values) != len(sorts) {
+		return nil, invalid
+	}
+
+	// numbers are decoded as json.Number, they are converted back by the type of the column
+	numeric := map[string]bool{}
+	for _, field := range schema.fields {
+		numeric[field.column] = field.numeric || field.integer
+	}
+	for i, s := range sorts {
+		switch v := cursor.Values[i].(type) {
+		case json.Number:
+			if !numeric[s.Column] {
+				return nil, invalid
+			}
+			number, err := strconv.ParseUint(v.String(), 10, 64)
+			if err != nil {
+				return nil, invalid
+			}
+			cursor.Values[i] = number
+		case string:
+			if numeric[s.Column] {
+				return nil, invalid
+			}
+		default:
+			return nil, invalid
+		}
+	}
+
+	return &cursor, nil
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
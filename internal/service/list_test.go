@@ -0,0 +1,185 @@
+package service
+
+import (
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	realDomain "github.com/karlbehrensg/go-fiber-template/internal/domain"
+	"github.com/karlbehrensg/go-fiber-template/internal/http/requests"
+	"github.com/karlbehrensg/go-fiber-template/pkg/errs"
+)
+
+func Test_should_return_status_400_when_sorting_or_filtering_by_unknown_fields(t *testing.T) {
+	// Arrange
+	cases := []requests.ListRequest{
+		{Sort: "-password"},
+		{Filters: map[string]string{"password": "secret"}},
+		{Filters: map[string]string{"publication_year[contains]": "19"}},
+		{Filters: map[string]string{"author_id": "one"}},
+		{Filters: map[string]string{"publication_year[gte]": "nineteen"}},
+		{Cursor: "not-a-cursor"},
+	}
+
+	for _, request := range cases {
+		// Act
+		_, appError := newListPlan(bookListSchema, request)
+
+		// Assert
+		if appError == nil || appError.Code != 400 {
+			t.Errorf("Test failed while validating list request %+v", request)
+		}
+	}
+}
+
+func Test_should_build_sorted_and_filtered_query_ending_with_the_id(t *testing.T) {
+	// Arrange
+	request := requests.ListRequest{
+		Sort:    "-publication_year,title",
+		Filters: map[string]string{"publication_year[gte]": "1990", "author_id": "30"},
+		Limit:   10,
+	}
+
+	// Act
+	plan, appError := newListPlan(bookListSchema, request)
+
+	// Assert
+	if appError != nil {
+		t.Fatal("Test failed while building list query")
+	}
+	expectedSort := []realDomain.ListSort{{Column: "publication_year", Desc: true, Integer: true}, {Column: "title"}, {Column: "id"}}
+	if !reflect.DeepEqual(plan.query.Sort, expectedSort) {
+		t.Errorf("Failed while matching sort: %+v", plan.query.Sort)
+	}
+	expectedFilters := []realDomain.ListFilter{
+		{Column: "author_id", Operator: realDomain.ListOperatorEqual, Value: uint64(30)},
+		{Column: "publication_year", Operator: realDomain.ListOperatorGreaterEqual, Value: uint64(1990), Integer: true},
+	}
+	if !reflect.DeepEqual(plan.query.Filters, expectedFilters) {
+		t.Errorf("Failed while matching filters: %+v", plan.query.Filters)
+	}
+	if plan.query.Limit != 11 {
+		t.Error("Failed while fetching the extra row of the next page")
+	}
+}
+
+func Test_should_follow_next_and_prev_cursors_through_the_list(t *testing.T) {
+	// Arrange
+	teardown := bookSetup(t)
+	defer teardown()
+
+	page1 := []realDomain.Book{{ID: 1, Title: "A"}, {ID: 2, Title: "B"}, {ID: 3, Title: "C"}}
+	mockBookRepo.EXPECT().FindAllBook(gomock.Any()).Return(page1, int64(5), nil)
+	// Act
+	first, appError := bookService.FindAllBook(requests.ListRequest{Sort: "title", Limit: 2, Path: "/book"})
+
+	// Assert
+	if appError != nil || len(first.Items) != 2 || first.Links.Prev != "" || first.Links.Next == "" {
+		t.Fatalf("Test failed while listing the first page: %+v", first)
+	}
+
+	// Arrange
+	next := linkParam(t, first.Links.Next, "cursor")
+	var query realDomain.ListQuery
+	mockBookRepo.EXPECT().FindAllBook(gomock.Any()).DoAndReturn(func(q realDomain.ListQuery) ([]realDomain.Book, int64, *errs.AppError) {
+		query = q
+		return []realDomain.Book{{ID: 3, Title: "C"}, {ID: 4, Title: "D"}, {ID: 5, Title: "E"}}, 5, nil
+	})
+	// Act
+	second, appError := bookService.FindAllBook(requests.ListRequest{Sort: "title", Limit: 2, Cursor: next, Path: "/book"})
+
+	// Assert
+	if appError != nil || !reflect.DeepEqual(query.After.Values, []interface{}{"B", uint64(2)}) {
+		t.Fatalf("Test failed while decoding next cursor: %+v", query.After)
+	}
+	if second.Links.Prev == "" || second.Links.Next == "" || second.Items[0].Id != 3 {
+		t.Fatalf("Test failed while listing the second page: %+v", second)
+	}
+
+	// Arrange, the previous page is read backwards from the first row of the second page
+	prev := linkParam(t, second.Links.Prev, "cursor")
+	mockBookRepo.EXPECT().FindAllBook(gomock.Any()).DoAndReturn(func(q realDomain.ListQuery) ([]realDomain.Book, int64, *errs.AppError) {
+		query = q
+		return []realDomain.Book{{ID: 2, Title: "B"}, {ID: 1, Title: "A"}}, 5, nil
+	})
+	// Act
+	back, appError := bookService.FindAllBook(requests.ListRequest{Sort: "title", Limit: 2, Cursor: prev, Path: "/book"})
+
+	// Assert
+	if appError != nil || !query.Sort[0].Desc || !reflect.DeepEqual(query.After.Values, []interface{}{"C", uint64(3)}) {
+		t.Fatalf("Test failed while decoding prev cursor: %+v", query)
+	}
+	if back.Items[0].Id != 1 || back.Items[1].Id != 2 || back.Links.Prev != "" || back.Links.Next == "" {
+		t.Errorf("Test failed while listing the previous page: %+v", back)
+	}
+}
+
+func Test_should_page_publication_years_of_mixed_width_as_integers(t *testing.T) {
+	// Arrange
+	teardown := bookSetup(t)
+	defer teardown()
+
+	var query realDomain.ListQuery
+	page1 := []realDomain.Book{{ID: 1, PublicationYear: "999"}, {ID: 2, PublicationYear: "1984"}, {ID: 3, PublicationYear: "2001"}}
+	mockBookRepo.EXPECT().FindAllBook(gomock.Any()).DoAndReturn(func(q realDomain.ListQuery) ([]realDomain.Book, int64, *errs.AppError) {
+		query = q
+		return page1, 3, nil
+	})
+	// Act
+	first, appError := bookService.FindAllBook(requests.ListRequest{
+		Sort:    "publication_year",
+		Filters: map[string]string{"publication_year[gte]": "999"},
+		Limit:   2,
+		Path:    "/book",
+	})
+
+	// Assert
+	if appError != nil || !query.Sort[0].Integer || !reflect.DeepEqual(query.Filters[0].Value, uint64(999)) {
+		t.Fatalf("Test failed while filtering years as integers: %+v", query)
+	}
+
+	// Arrange
+	next := linkParam(t, first.Links.Next, "cursor")
+	mockBookRepo.EXPECT().FindAllBook(gomock.Any()).DoAndReturn(func(q realDomain.ListQuery) ([]realDomain.Book, int64, *errs.AppError) {
+		query = q
+		return page1[2:], 3, nil
+	})
+	// Act
+	_, appError = bookService.FindAllBook(requests.ListRequest{
+		Sort:    "publication_year",
+		Filters: map[string]string{"publication_year[gte]": "999"},
+		Limit:   2,
+		Cursor:  next,
+		Path:    "/book",
+	})
+
+	// Assert, the cursor keeps the year as a number so 1984 is compared after 999
+	if appError != nil || !reflect.DeepEqual(query.After.Values, []interface{}{uint64(1984), uint64(2)}) {
+		t.Errorf("Test failed while decoding year cursor: %+v", query.After)
+	}
+}
+
+func Test_should_reject_cursor_created_for_another_sort(t *testing.T) {
+	// Arrange
+	plan, _ := newListPlan(bookListSchema, requests.ListRequest{Sort: "title"})
+	cursor := plan.encodeCursor(0, false, func(i int, column string) interface{} { return "A" })
+
+	// Act
+	_, appError := newListPlan(bookListSchema, requests.ListRequest{Sort: "-title", Cursor: cursor})
+
+	// Assert
+	if appError == nil || !strings.Contains(appError.Message, "invalid cursor") {
+		t.Error("Test failed while validating cursor sort")
+	}
+}
+
+func linkParam(t *testing.T, link string, name string) string {
+	u, err := url.Parse(link)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return u.Query().Get(name)
+}
@@ -0,0 +1,161 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/karlbehrensg/go-fiber-template/internal/domain"
+	"github.com/karlbehrensg/go-fiber-template/pkg/errs"
+	"github.com/karlbehrensg/go-fiber-template/pkg/logger"
+	"github.com/karlbehrensg/go-fiber-template/pkg/utils"
+)
+
+// loginThrottle policy to protect the login against brute-force attacks,
+// failures are tracked per email and per client IP
+type loginThrottle struct {
+	repo            domain.LoginAttemptRepository
+	window          time.Duration
+	delayThreshold  int
+	delayBase       time.Duration
+	delayMax        time.Duration
+	maxEmailFailure int
+	maxIPFailure    int
+	lockout         time.Duration
+	magicLinkWindow time.Duration
+	maxMagicLinks   int
+}
+
+// newLoginThrottle create the login throttle policy from env
+func newLoginThrottle(repository domain.LoginAttemptRepository) loginThrottle {
+	return loginThrottle{
+		repo:            repository,
+		window:          utils.GetEnvDuration("LOGIN_ATTEMPT_WINDOW", time.Minute*15),
+		delayThreshold:  utils.GetEnvInt("LOGIN_DELAY_THRESHOLD", 3),
+		delayBase:       utils.GetEnvDuration("LOGIN_DELAY_BASE", time.Second),
+		delayMax:        utils.GetEnvDuration("LOGIN_DELAY_MAX", time.Minute),
+		maxEmailFailure: utils.GetEnvInt("LOGIN_MAX_ATTEMPTS", 10),
+		maxIPFailure:    utils.GetEnvInt("LOGIN_MAX_ATTEMPTS_PER_IP", 50),
+		lockout:         utils.GetEnvDuration("LOGIN_LOCKOUT_DURATION", time.Minute*15),
+		magicLinkWindow: utils.GetEnvDuration("MAGIC_LINK_WINDOW", time.Minute*15),
+		maxMagicLinks:   utils.GetEnvInt("MAGIC_LINK_MAX_REQUESTS", 3),
+	}
+}
+
+// check validate the email and IP are allowed to try a login now
+func (t loginThrottle) check(email string, ip string) *errs.AppError {
+	if appErr := t.checkKey(emailKey(email), errs.NewLockedError("account temporarily locked, try again later")); appErr != nil {
+		return appErr
+	}
+
+	return t.checkKey(ipKey(ip), errs.NewTooManyRequestsError("too many login attempts, try again later"))
+}
+
+// fail register a failed login for the email and IP, locking them when the threshold is reached
+func (t loginThrottle) fail(email string, ip string) {
+	t.failKey(emailKey(email), t.maxEmailFailure)
+	t.failKey(ipKey(ip), t.maxIPFailure)
+}
+
+// reset delete the failures of the email and IP after a successful login
+func (t loginThrottle) reset(email string, ip string) {
+	t.repo.ResetLoginAttempts(emailKey(email))
+	t.repo.ResetLoginAttempts(ipKey(ip))
+}
+
+func (t loginThrottle) checkKey(key string, lockedErr *errs.AppError) *errs.AppError {
+	attempt, appErr := t.repo.FindLoginAttempt(key)
+	if appErr != nil {
+		if strings.Contains(appErr.Message, "record not found") {
+			return nil
+		}
+		return appErr
+	}
+
+	if attempt.IsLocked() {
+		return lockedErr
+	}
+
+	// failures older than the window are forgotten
+	if time.Since(attempt.LastFailure) > t.window {
+		return nil
+	}
+
+	if wait := t.delay(attempt.Failures) - time.Since(attempt.LastFailure); wait > 0 {
+		return errs.NewTooManyRequestsError(fmt.Sprintf("too many login attempts, retry in %d seconds", int(math.Ceil(wait.Seconds()))))
+	}
+
+	return nil
+}
+
+func (t loginThrottle) failKey(key string, max int) {
+	attempt, appErr := t.repo.RegisterFailedLogin(key, t.window)
+	if appErr != nil {
+		return
+	}
+
+	if attempt.Failures >= max {
+		logger.Info(fmt.Sprintf("Login locked for %s after %d failed attempts", key, attempt.Failures))
+		t.repo.LockLoginAttempt(key, time.Now().Add(t.lockout))
+	}
+}
+
+// delay progressive delay required after the given failures, it doubles on every failure over the threshold
+func (t loginThrottle) delay(failures int) time.Duration {
+	if failures < t.delayThreshold {
+		return 0
+	}
+
+	delay := t.delayBase * time.Duration(math.Pow(2, float64(failures-t.delayThreshold)))
+	if delay <= 0 || delay > t.delayMax {
+		return t.delayMax
+	}
+
+	return delay
+}
+
+func emailKey(email string) string {
+	return "email:" + strings.ToLower(email)
+}
+
+func ipKey(ip string) string {
+	return "ip:" + ip
+}
+
+// checkMFA validate the user is allowed to try a two-factor code now
+func (t loginThrottle) checkMFA(userID uint) *errs.AppError {
+	return t.checkKey(mfaKey(userID), errs.NewLockedError("account temporarily locked, try again later"))
+}
+
+// failMFA register a failed two-factor code for the user
+func (t loginThrottle) failMFA(userID uint) {
+	t.failKey(mfaKey(userID), t.maxEmailFailure)
+}
+
+// resetMFA delete the two-factor failures of the user
+func (t loginThrottle) resetMFA(userID uint) {
+	t.repo.ResetLoginAttempts(mfaKey(userID))
+}
+
+// limitMagicLink count a magic link request for the email, it is applied whether the account exists or not
+func (t loginThrottle) limitMagicLink(email string) *errs.AppError {
+	attempt, appErr := t.repo.RegisterFailedLogin(magicLinkKey(email), t.magicLinkWindow)
+	if appErr != nil {
+		return appErr
+	}
+
+	if attempt.Failures > t.maxMagicLinks {
+		return errs.NewTooManyRequestsError("too many login links requested, try again later")
+	}
+
+	return nil
+}
+
+func magicLinkKey(email string) string {
+	return "magic:" + strings.ToLower(email)
+}
+
+func mfaKey(userID uint) string {
+	return fmt.Sprintf("mfa:%d", userID)
+}
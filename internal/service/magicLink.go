@@ -0,0 +1,143 @@
+package service
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/karlbehrensg/go-fiber-template/internal/domain"
+	"github.com/karlbehrensg/go-fiber-template/internal/http/requests"
+	"github.com/karlbehrensg/go-fiber-template/internal/http/responses"
+	"github.com/karlbehrensg/go-fiber-template/pkg/errs"
+	"github.com/karlbehrensg/go-fiber-template/pkg/logger"
+	"github.com/karlbehrensg/go-fiber-template/pkg/utils"
+)
+
+// MagicLinkService port primary
+type MagicLinkService interface {
+	RequestMagicLink(requests.MagicLinkRequest) *errs.AppError
+	LoginWithMagicLink(requests.MagicLinkLoginRequest) (*responses.LoginResponse, *errs.AppError)
+}
+
+type DefaultMagicLinkService struct {
+	userRepo   domain.UserRepository
+	tokenRepo  domain.MagicLinkTokenRepository
+	tokenSrv   TokenService
+	mailer     domain.Mailer
+	throttle   loginThrottle
+	challenges mfaChallenges
+}
+
+// NewMagicLinkService create a new instance of DefaultMagicLinkService
+func NewMagicLinkService(
+	userRepository domain.UserRepository,
+	magicLinkTokenRepository domain.MagicLinkTokenRepository,
+	tokenService TokenService,
+	mailer domain.Mailer,
+	loginAttemptRepository domain.LoginAttemptRepository,
+	mfaChallengeRepository domain.MFAChallengeRepository,
+) DefaultMagicLinkService {
+	return DefaultMagicLinkService{
+		userRepository,
+		magicLinkTokenRepository,
+		tokenService,
+		mailer,
+		newLoginThrottle(loginAttemptRepository),
+		newMFAChallenges(mfaChallengeRepository),
+	}
+}
+
+// RequestMagicLink use case for send a single-use login link, it never reveals if the account exists
+func (s DefaultMagicLinkService) RequestMagicLink(request requests.MagicLinkRequest) *errs.AppError {
+	// the limit is counted before looking up the account so both cases answer the same
+	if appErr := s.throttle.limitMagicLink(request.Email); appErr != nil {
+		return appErr
+	}
+
+	u, appErr := s.userRepo.FindUserByEmail(request.Email)
+	if appErr != nil {
+		if strings.Contains(appErr.Message, "record not found") {
+			return nil
+		}
+		return appErr
+	}
+
+	token, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		logger.Error(err.Error())
+		return errs.NewUnexpectedError("unexpected error while creating login link")
+	}
+
+	ttl := utils.GetEnvDuration("MAGIC_LINK_TTL", time.Minute*15)
+	// calls repository to save the hashed token
+	if appErr = s.tokenRepo.SaveMagicLinkToken(&domain.MagicLinkToken{
+		UserID:    u.ID,
+		TokenHash: utils.HashToken(token),
+		ExpiresAt: time.Now().Add(ttl),
+	}); appErr != nil {
+		return appErr
+	}
+
+	// a mailer failure answers the same as a missing account, it is only logged
+	if appErr = s.mailer.Send(domain.Mail{
+		To:      u.Email,
+		Subject: "Your login link",
+		Body: fmt.Sprintf(
+			"Use the following link to log in, it can be used once and expires in %s:\n%s?token=%s",
+			ttl, os.Getenv("MAGIC_LINK_URL"), token,
+		),
+	}); appErr != nil {
+		logger.Error(fmt.Sprintf("Error while sending login link to user id=%d: %s", u.ID, appErr.Message))
+	}
+
+	return nil
+}
+
+// LoginWithMagicLink use case for consume the login link and return token
+func (s DefaultMagicLinkService) LoginWithMagicLink(request requests.MagicLinkLoginRequest) (*responses.LoginResponse, *errs.AppError) {
+	var magicLink *domain.MagicLinkToken
+	var appErr *errs.AppError
+
+	// find magic link token by hash
+	if magicLink, appErr = s.tokenRepo.FindMagicLinkTokenByHash(utils.HashToken(request.Token)); appErr != nil {
+		if strings.Contains(appErr.Message, "record not found") {
+			return nil, errs.NewAuthenticationError("invalid or expired magic link")
+		}
+		return nil, appErr
+	}
+
+	if magicLink.UsedAt != nil || magicLink.IsExpired() {
+		return nil, errs.NewAuthenticationError("invalid or expired magic link")
+	}
+
+	// consume the token before login, so it cannot be used twice
+	if appErr = s.tokenRepo.ConsumeMagicLinkToken(magicLink.ID); appErr != nil {
+		return nil, appErr
+	}
+
+	var u *domain.User
+	if u, appErr = s.userRepo.FindUserById(magicLink.UserID); appErr != nil {
+		if strings.Contains(appErr.Message, "record not found") {
+			return nil, errs.NewAuthenticationError("invalid or expired magic link")
+		}
+		return nil, appErr
+	}
+
+	// opening the link proves the ownership of the email
+	if !u.IsVerified() {
+		now := time.Now()
+		if appErr = s.userRepo.UpdateUserColumns(u.ID, map[string]interface{}{"verified_at": now}); appErr != nil {
+			return nil, appErr
+		}
+		u.VerifiedAt = &now
+	}
+
+	// the link replaces the password, not the second factor
+	if u.IsMFAEnabled() {
+		return s.challenges.issue(u)
+	}
+
+	// create access and refresh token
+	return s.tokenSrv.IssueTokens(u, domain.ClientInfo{IP: request.IP, UserAgent: request.UserAgent, RequestID: request.RequestID})
+}
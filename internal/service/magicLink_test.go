@@ -0,0 +1,145 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	realDomain "github.com/karlbehrensg/go-fiber-template/internal/domain"
+	"github.com/karlbehrensg/go-fiber-template/internal/http/requests"
+	"github.com/karlbehrensg/go-fiber-template/mocks/domain"
+	"github.com/karlbehrensg/go-fiber-template/pkg/errs"
+	"github.com/karlbehrensg/go-fiber-template/pkg/utils"
+)
+
+var mockMagicLinkTokenRepo *domain.MockMagicLinkTokenRepository
+var magicLinkService MagicLinkService
+
+func magicLinkSetup(t *testing.T) func() {
+	ctrl := gomock.NewController(t)
+	mockUserRepo = domain.NewMockUserRepository(ctrl)
+	mockRefreshTokenRepo = domain.NewMockRefreshTokenRepository(ctrl)
+	mockRevokedTokenRepo = domain.NewMockRevokedTokenRepository(ctrl)
+	mockSessionRepo = domain.NewMockSessionRepository(ctrl)
+	mockLoginAttemptRepo = domain.NewMockLoginAttemptRepository(ctrl)
+	mockMagicLinkTokenRepo = domain.NewMockMagicLinkTokenRepository(ctrl)
+	mockMailer = domain.NewMockMailer(ctrl)
+	tokenService := NewTokenService(mockUserRepo, mockRefreshTokenRepo, mockRevokedTokenRepo, mockSessionRepo, nil)
+	magicLinkService = NewMagicLinkService(mockUserRepo, mockMagicLinkTokenRepo, tokenService, mockMailer, mockLoginAttemptRepo, nil)
+	return func() {
+		magicLinkService = nil
+		defer ctrl.Finish()
+	}
+}
+
+func Test_should_not_reveal_missing_account_when_magic_link_is_requested(t *testing.T) {
+	// Arrange
+	teardown := magicLinkSetup(t)
+	defer teardown()
+
+	mockLoginAttemptRepo.EXPECT().RegisterFailedLogin("magic:unknown@example.com", gomock.Any()).Return(&realDomain.LoginAttempt{Failures: 1}, nil)
+	mockUserRepo.EXPECT().FindUserByEmail("unknown@example.com").Return(nil, errs.NewNotFoundError("record not found"))
+	// Act
+	appError := magicLinkService.RequestMagicLink(requests.MagicLinkRequest{Email: "unknown@example.com"})
+
+	// Assert
+	if appError != nil {
+		t.Error("Test failed while requesting magic link for missing account")
+	}
+}
+
+func Test_should_return_status_429_when_magic_links_are_requested_too_often(t *testing.T) {
+	// Arrange
+	teardown := magicLinkSetup(t)
+	defer teardown()
+
+	mockLoginAttemptRepo.EXPECT().RegisterFailedLogin("magic:edwyn@example.com", gomock.Any()).Return(&realDomain.LoginAttempt{Failures: 4}, nil)
+	// Act
+	appError := magicLinkService.RequestMagicLink(requests.MagicLinkRequest{Email: "Edwyn@example.com"})
+
+	// Assert
+	if appError == nil || appError.Code != 429 {
+		t.Error("Test failed while rate limiting magic links")
+	}
+}
+
+func Test_should_send_magic_link_when_account_exists(t *testing.T) {
+	// Arrange
+	teardown := magicLinkSetup(t)
+	defer teardown()
+
+	u := &realDomain.User{ID: 1, Email: "edwyn@example.com"}
+
+	mockLoginAttemptRepo.EXPECT().RegisterFailedLogin(gomock.Any(), gomock.Any()).Return(&realDomain.LoginAttempt{Failures: 1}, nil)
+	mockUserRepo.EXPECT().FindUserByEmail("edwyn@example.com").Return(u, nil)
+	mockMagicLinkTokenRepo.EXPECT().SaveMagicLinkToken(gomock.Any()).Return(nil)
+	mockMailer.EXPECT().Send(gomock.Any()).Return(nil)
+	// Act
+	appError := magicLinkService.RequestMagicLink(requests.MagicLinkRequest{Email: "edwyn@example.com"})
+
+	// Assert
+	if appError != nil {
+		t.Error("Test failed while sending magic link")
+	}
+}
+
+func Test_should_not_reveal_the_account_when_the_magic_link_mail_fails(t *testing.T) {
+	// Arrange
+	teardown := magicLinkSetup(t)
+	defer teardown()
+
+	u := &realDomain.User{ID: 1, Email: "edwyn@example.com"}
+
+	mockLoginAttemptRepo.EXPECT().RegisterFailedLogin(gomock.Any(), gomock.Any()).Return(&realDomain.LoginAttempt{Failures: 1}, nil)
+	mockUserRepo.EXPECT().FindUserByEmail("edwyn@example.com").Return(u, nil)
+	mockMagicLinkTokenRepo.EXPECT().SaveMagicLinkToken(gomock.Any()).Return(nil)
+	mockMailer.EXPECT().Send(gomock.Any()).Return(errs.NewUnexpectedError("smtp unavailable"))
+	// Act
+	appError := magicLinkService.RequestMagicLink(requests.MagicLinkRequest{Email: "edwyn@example.com"})
+
+	// Assert
+	if appError != nil {
+		t.Error("Test failed while hiding the mailer error")
+	}
+}
+
+func Test_should_return_tokens_when_magic_link_is_valid(t *testing.T) {
+	// Arrange
+	teardown := magicLinkSetup(t)
+	defer teardown()
+
+	verifiedAt := time.Now()
+	u := &realDomain.User{ID: 1, Email: "edwyn@example.com", VerifiedAt: &verifiedAt}
+	token := &realDomain.MagicLinkToken{ID: 2, UserID: 1, ExpiresAt: time.Now().Add(time.Minute)}
+
+	mockMagicLinkTokenRepo.EXPECT().FindMagicLinkTokenByHash(utils.HashToken("link")).Return(token, nil)
+	mockMagicLinkTokenRepo.EXPECT().ConsumeMagicLinkToken(uint(2)).Return(nil)
+	mockUserRepo.EXPECT().FindUserById(uint(1)).Return(u, nil)
+	mockSessionRepo.EXPECT().SaveSession(gomock.Any()).Return(nil)
+	mockRefreshTokenRepo.EXPECT().SaveRefreshToken(gomock.Any()).Return(nil)
+	// Act
+	response, appError := magicLinkService.LoginWithMagicLink(requests.MagicLinkLoginRequest{Token: "link"})
+
+	// Assert
+	if appError != nil || response.Token == "" || response.RefreshToken == "" {
+		t.Error("Test failed while login with magic link")
+	}
+}
+
+func Test_should_return_status_401_when_magic_link_was_already_used(t *testing.T) {
+	// Arrange
+	teardown := magicLinkSetup(t)
+	defer teardown()
+
+	usedAt := time.Now()
+	token := &realDomain.MagicLinkToken{ID: 2, UserID: 1, ExpiresAt: time.Now().Add(time.Minute), UsedAt: &usedAt}
+
+	mockMagicLinkTokenRepo.EXPECT().FindMagicLinkTokenByHash(gomock.Any()).Return(token, nil)
+	// Act
+	_, appError := magicLinkService.LoginWithMagicLink(requests.MagicLinkLoginRequest{Token: "link"})
+
+	// Assert
+	if appError == nil || appError.Code != 401 {
+		t.Error("Test failed while validating used magic link")
+	}
+}
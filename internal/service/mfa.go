@@ -0,0 +1,342 @@
+package service
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/karlbehrensg/go-fiber-template/internal/domain"
+	"github.com/karlbehrensg/go-fiber-template/internal/http/requests"
+	"github.com/karlbehrensg/go-fiber-template/internal/http/responses"
+	"github.com/karlbehrensg/go-fiber-template/pkg/errs"
+	"github.com/karlbehrensg/go-fiber-template/pkg/logger"
+	"github.com/karlbehrensg/go-fiber-template/pkg/utils"
+)
+
+// MFAService port primary
+type MFAService interface {
+	EnrollTOTP(uint) (*responses.TOTPEnrollmentResponse, *errs.AppError)
+	ConfirmTOTP(uint, requests.MFACodeRequest) (*responses.RecoveryCodesResponse, *errs.AppError)
+	DisableTOTP(uint, requests.MFACodeRequest) *errs.AppError
+	VerifyMFA(requests.MFAVerifyRequest) (*responses.LoginResponse, *errs.AppError)
+}
+
+type DefaultMFAService struct {
+	userRepo         domain.UserRepository
+	recoveryCodeRepo domain.RecoveryCodeRepository
+	tokenSrv         TokenService
+	throttle         loginThrottle
+	challenges       mfaChallenges
+}
+
+// NewMFAService create a new instance of DefaultMFAService
+func NewMFAService(
+	userRepository domain.UserRepository,
+	recoveryCodeRepository domain.RecoveryCodeRepository,
+	tokenService TokenService,
+	loginAttemptRepository domain.LoginAttemptRepository,
+	mfaChallengeRepository domain.MFAChallengeRepository,
+) DefaultMFAService {
+	return DefaultMFAService{
+		userRepository,
+		recoveryCodeRepository,
+		tokenService,
+		newLoginThrottle(loginAttemptRepository),
+		newMFAChallenges(mfaChallengeRepository),
+	}
+}
+
+// EnrollTOTP use case for create a TOTP secret, it is not required on login until it is confirmed
+func (s DefaultMFAService) EnrollTOTP(userID uint) (*responses.TOTPEnrollmentResponse, *errs.AppError) {
+	u, appErr := s.userRepo.FindUserById(userID)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	if u.IsMFAEnabled() {
+		return nil, errs.NewBadRequestError("two-factor authentication is already enabled")
+	}
+
+	secret, err := utils.GenerateTOTPSecret()
+	if err != nil {
+		logger.Error(err.Error())
+		return nil, errs.NewUnexpectedError("unexpected error while creating two-factor secret")
+	}
+
+	if appErr = s.userRepo.UpdateUserColumns(u.ID, map[string]interface{}{
+		"totp_secret":    secret,
+		"totp_last_step": 0,
+	}); appErr != nil {
+		return nil, appErr
+	}
+
+	return &responses.TOTPEnrollmentResponse{
+		Secret: secret,
+		URI:    utils.TOTPURI(mfaIssuer(), u.Email, secret),
+	}, nil
+}
+
+// ConfirmTOTP use case for enable two-factor authentication with a first valid code, returns the recovery codes
+func (s DefaultMFAService) ConfirmTOTP(userID uint, request requests.MFACodeRequest) (*responses.RecoveryCodesResponse, *errs.AppError) {
+	u, appErr := s.userRepo.FindUserById(userID)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	if u.IsMFAEnabled() {
+		return nil, errs.NewBadRequestError("two-factor authentication is already enabled")
+	}
+	if u.TOTPSecret == "" {
+		return nil, errs.NewBadRequestError("two-factor enrollment not started")
+	}
+
+	if appErr = s.throttle.checkMFA(u.ID); appErr != nil {
+		return nil, appErr
+	}
+
+	step, ok := utils.ValidateTOTP(u.TOTPSecret, strings.TrimSpace(request.Code), time.Now(), 1)
+	if !ok {
+		s.throttle.failMFA(u.ID)
+		return nil, errs.NewBadRequestError("invalid two-factor code")
+	}
+	s.throttle.resetMFA(u.ID)
+
+	codes, records, appErr := newRecoveryCodes(u.ID)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	// calls repository to replace the recovery codes
+	if appErr = s.recoveryCodeRepo.ReplaceRecoveryCodes(u.ID, records); appErr != nil {
+		return nil, appErr
+	}
+
+	if appErr = s.userRepo.UpdateUserColumns(u.ID, map[string]interface{}{
+		"mfa_enabled_at": time.Now(),
+		"totp_last_step": step,
+	}); appErr != nil {
+		return nil, appErr
+	}
+
+	return &responses.RecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+// DisableTOTP use case for disable two-factor authentication with a valid code
+func (s DefaultMFAService) DisableTOTP(userID uint, request requests.MFACodeRequest) *errs.AppError {
+	u, appErr := s.userRepo.FindUserById(userID)
+	if appErr != nil {
+		return appErr
+	}
+
+	if !u.IsMFAEnabled() {
+		return errs.NewBadRequestError("two-factor authentication is not enabled")
+	}
+
+	// the user is already authenticated, a wrong code is not an authentication failure
+	if appErr = s.checkCode(u, request.Code); appErr != nil {
+		if appErr.Code == http.StatusUnauthorized {
+			return errs.NewBadRequestError(appErr.Message)
+		}
+		return appErr
+	}
+
+	if appErr = s.userRepo.UpdateUserColumns(u.ID, map[string]interface{}{
+		"totp_secret":    "",
+		"totp_last_step": 0,
+		"mfa_enabled_at": nil,
+	}); appErr != nil {
+		return appErr
+	}
+
+	return s.recoveryCodeRepo.DeleteRecoveryCodes(u.ID)
+}
+
+// VerifyMFA use case for exchange the MFA challenge and a valid code for the access token
+func (s DefaultMFAService) VerifyMFA(request requests.MFAVerifyRequest) (*responses.LoginResponse, *errs.AppError) {
+	challenge, appErr := s.challenges.find(request.MFAToken)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	var u *domain.User
+	if u, appErr = s.userRepo.FindUserById(challenge.UserID); appErr != nil {
+		if strings.Contains(appErr.Message, "record not found") {
+			return nil, errs.NewAuthenticationError("invalid or expired MFA challenge")
+		}
+		return nil, appErr
+	}
+
+	if !u.IsMFAEnabled() {
+		return nil, errs.NewAuthenticationError("invalid or expired MFA challenge")
+	}
+
+	if appErr = s.checkCode(u, request.Code); appErr != nil {
+		return nil, appErr
+	}
+
+	// consume the challenge after a valid code, so a wrong code can be retried but the challenge is never used twice
+	if appErr = s.challenges.repo.ConsumeMFAChallenge(challenge.ID); appErr != nil {
+		return nil, appErr
+	}
+
+	// create access and refresh token
+	return s.tokenSrv.IssueTokens(u, domain.ClientInfo{IP: request.IP, UserAgent: request.UserAgent, RequestID: request.RequestID})
+}
+
+// checkCode validate a TOTP code or consume a recovery code, failures are throttled per user
+func (s DefaultMFAService) checkCode(u *domain.User, code string) *errs.AppError {
+	if appErr := s.throttle.checkMFA(u.ID); appErr != nil {
+		return appErr
+	}
+
+	code = strings.TrimSpace(code)
+	if _, err := strconv.Atoi(code); err == nil && len(code) == 6 {
+		// a code can not be used twice, nor an older one once a newer was used
+		if step, ok := utils.ValidateTOTP(u.TOTPSecret, code, time.Now(), 1); ok && step > u.TOTPLastStep {
+			s.throttle.resetMFA(u.ID)
+			return s.userRepo.UpdateUserColumns(u.ID, map[string]interface{}{"totp_last_step": step})
+		}
+	} else {
+		appErr := s.recoveryCodeRepo.ConsumeRecoveryCode(u.ID, utils.HashToken(normalizeRecoveryCode(code)))
+		if appErr == nil {
+			logger.Info(fmt.Sprintf("Recovery code used by user id=%d", u.ID))
+			s.throttle.resetMFA(u.ID)
+			return nil
+		}
+		if !strings.Contains(appErr.Message, "record not found") {
+			return appErr
+		}
+	}
+
+	s.throttle.failMFA(u.ID)
+	return errs.NewAuthenticationError("invalid two-factor code")
+}
+
+// mfaChallenges issue the MFA challenges of the logins and find them on verify, each challenge is stored to be used once
+type mfaChallenges struct {
+	repo domain.MFAChallengeRepository
+}
+
+func newMFAChallenges(repository domain.MFAChallengeRepository) mfaChallenges {
+	return mfaChallenges{repository}
+}
+
+// issue create the login response asking for the second factor
+func (c mfaChallenges) issue(u *domain.User) (*responses.LoginResponse, *errs.AppError) {
+	secret := mfaChallengeSecret()
+	if secret == "" {
+		logger.Error("MFA_CHALLENGE_SECRET or JWT_SECRET must be defined to sign MFA challenges")
+		return nil, errs.NewUnexpectedError("unexpected error while creating MFA challenge")
+	}
+
+	nonce, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		logger.Error(err.Error())
+		return nil, errs.NewUnexpectedError("unexpected error while creating MFA challenge")
+	}
+
+	ttl := utils.GetEnvDuration("MFA_CHALLENGE_TTL", time.Minute*5)
+	// calls repository to save the hashed nonce
+	if appErr := c.repo.SaveMFAChallenge(&domain.MFAChallenge{
+		UserID:    u.ID,
+		NonceHash: utils.HashToken(nonce),
+		ExpiresAt: time.Now().Add(ttl),
+	}); appErr != nil {
+		return nil, appErr
+	}
+
+	return &responses.LoginResponse{
+		MFARequired: true,
+		MFAToken:    utils.CreateSignedToken(utils.SignedTokenMFAChallenge, fmt.Sprintf("%d:%s", u.ID, nonce), ttl, secret),
+	}, nil
+}
+
+// find validate the MFA challenge and return it while it is not used
+func (c mfaChallenges) find(token string) (*domain.MFAChallenge, *errs.AppError) {
+	secret := mfaChallengeSecret()
+	if secret == "" {
+		return nil, errs.NewAuthenticationError("invalid or expired MFA challenge")
+	}
+
+	payload, err := utils.ParseSignedToken(utils.SignedTokenMFAChallenge, token, secret)
+	if err != nil {
+		return nil, errs.NewAuthenticationError("invalid or expired MFA challenge")
+	}
+
+	// payload is "<user id>:<nonce>"
+	parts := strings.SplitN(payload, ":", 2)
+	if len(parts) != 2 {
+		return nil, errs.NewAuthenticationError("invalid or expired MFA challenge")
+	}
+	id, err := strconv.ParseUint(parts[0], 10, 64)
+	if err != nil {
+		return nil, errs.NewAuthenticationError("invalid or expired MFA challenge")
+	}
+
+	challenge, appErr := c.repo.FindMFAChallengeByHash(utils.HashToken(parts[1]))
+	if appErr != nil {
+		if strings.Contains(appErr.Message, "record not found") {
+			return nil, errs.NewAuthenticationError("invalid or expired MFA challenge")
+		}
+		return nil, appErr
+	}
+
+	if challenge.UserID != uint(id) || challenge.UsedAt != nil || challenge.IsExpired() {
+		return nil, errs.NewAuthenticationError("invalid or expired MFA challenge")
+	}
+
+	return challenge, nil
+}
+
+// newRecoveryCodes create the one-time recovery codes and their records to be stored
+func newRecoveryCodes(userID uint) ([]string, []*domain.RecoveryCode, *errs.AppError) {
+	count := utils.GetEnvInt("MFA_RECOVERY_CODES", 10)
+	codes := make([]string, 0, count)
+	records := make([]*domain.RecoveryCode, 0, count)
+
+	for i := 0; i < count; i++ {
+		raw := make([]byte, 8)
+		if _, err := rand.Read(raw); err != nil {
+			logger.Error(err.Error())
+			return nil, nil, errs.NewUnexpectedError("unexpected error while creating recovery codes")
+		}
+		code := hex.EncodeToString(raw)
+		code = fmt.Sprintf("%s-%s-%s-%s", code[0:4], code[4:8], code[8:12], code[12:16])
+
+		codes = append(codes, code)
+		records = append(records, &domain.RecoveryCode{
+			UserID:   userID,
+			CodeHash: utils.HashToken(normalizeRecoveryCode(code)),
+		})
+	}
+
+	return codes, records, nil
+}
+
+// normalizeRecoveryCode ignore case, spaces and dashes typed by the user
+func normalizeRecoveryCode(code string) string {
+	return strings.NewReplacer("-", "", " ", "").Replace(strings.ToLower(code))
+}
+
+// mfaIssuer issuer shown in authenticator apps
+func mfaIssuer() string {
+	if issuer := os.Getenv("MFA_ISSUER"); issuer != "" {
+		return issuer
+	}
+
+	return "go-fiber-template"
+}
+
+// mfaChallengeSecret secret used to sign MFA challenges
+func mfaChallengeSecret() string {
+	if secret := os.Getenv("MFA_CHALLENGE_SECRET"); secret != "" {
+		return secret
+	}
+
+	return os.Getenv("JWT_SECRET")
+}
@@ -0,0 +1,242 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	realDomain "github.com/karlbehrensg/go-fiber-template/internal/domain"
+	"github.com/karlbehrensg/go-fiber-template/internal/http/requests"
+	"github.com/karlbehrensg/go-fiber-template/mocks/domain"
+	"github.com/karlbehrensg/go-fiber-template/pkg/errs"
+	"github.com/karlbehrensg/go-fiber-template/pkg/utils"
+)
+
+var mockRecoveryCodeRepo *domain.MockRecoveryCodeRepository
+var mockMFAChallengeRepo *domain.MockMFAChallengeRepository
+var mfaService MFAService
+
+func mfaSetup(t *testing.T) func() {
+	t.Setenv("MFA_CHALLENGE_SECRET", "secret")
+	ctrl := gomock.NewController(t)
+	mockUserRepo = domain.NewMockUserRepository(ctrl)
+	mockRefreshTokenRepo = domain.NewMockRefreshTokenRepository(ctrl)
+	mockRevokedTokenRepo = domain.NewMockRevokedTokenRepository(ctrl)
+	mockSessionRepo = domain.NewMockSessionRepository(ctrl)
+	mockLoginAttemptRepo = domain.NewMockLoginAttemptRepository(ctrl)
+	mockRecoveryCodeRepo = domain.NewMockRecoveryCodeRepository(ctrl)
+	mockMFAChallengeRepo = domain.NewMockMFAChallengeRepository(ctrl)
+	tokenService := NewTokenService(mockUserRepo, mockRefreshTokenRepo, mockRevokedTokenRepo, mockSessionRepo, nil)
+	mfaService = NewMFAService(mockUserRepo, mockRecoveryCodeRepo, tokenService, mockLoginAttemptRepo, mockMFAChallengeRepo)
+	return func() {
+		mfaService = nil
+		defer ctrl.Finish()
+	}
+}
+
+// mfaUser user with two-factor authentication enabled
+func mfaUser() *realDomain.User {
+	secret, _ := utils.GenerateTOTPSecret()
+	enabledAt := time.Now()
+	return &realDomain.User{ID: 1, Email: "edwyn@example.com", TOTPSecret: secret, MFAEnabledAt: &enabledAt}
+}
+
+// mfaChallenge issue a MFA challenge for the user and expect it to be found on verify
+func mfaChallenge(u *realDomain.User) string {
+	var challenge *realDomain.MFAChallenge
+	mockMFAChallengeRepo.EXPECT().SaveMFAChallenge(gomock.Any()).DoAndReturn(func(c *realDomain.MFAChallenge) *errs.AppError {
+		c.ID = 7
+		challenge = c
+		return nil
+	})
+	response, _ := newMFAChallenges(mockMFAChallengeRepo).issue(u)
+	mockMFAChallengeRepo.EXPECT().FindMFAChallengeByHash(challenge.NonceHash).Return(challenge, nil)
+
+	return response.MFAToken
+}
+
+func Test_should_return_mfa_challenge_instead_of_tokens_when_login_with_mfa_enabled(t *testing.T) {
+	// Arrange
+	teardown := mfaSetup(t)
+	defer teardown()
+	authService = NewAuthService(mockUserRepo, nil, mockLoginAttemptRepo, nil, mockMFAChallengeRepo)
+
+	u := mfaUser()
+	u.Password = "1234567"
+	u.HashPassword()
+	verifiedAt := time.Now()
+	u.VerifiedAt = &verifiedAt
+
+	mockLoginAttemptRepo.EXPECT().FindLoginAttempt(gomock.Any()).Return(nil, errs.NewNotFoundError("record not found")).Times(2)
+	mockUserRepo.EXPECT().FindUserByEmail(u.Email).Return(u, nil)
+	mockLoginAttemptRepo.EXPECT().ResetLoginAttempts(gomock.Any()).Return(nil).Times(2)
+	mockMFAChallengeRepo.EXPECT().SaveMFAChallenge(gomock.Any()).Return(nil)
+	// Act
+	response, appError := authService.Login(requests.LoginRequest{Email: u.Email, Password: "1234567", IP: "10.0.0.1"})
+
+	// Assert
+	if appError != nil || !response.MFARequired || response.MFAToken == "" || response.Token != "" {
+		t.Error("Test failed while validating MFA challenge on login")
+	}
+}
+
+func Test_should_return_tokens_when_mfa_challenge_and_totp_code_are_valid(t *testing.T) {
+	// Arrange
+	teardown := mfaSetup(t)
+	defer teardown()
+
+	u := mfaUser()
+	challenge := mfaChallenge(u)
+	step := utils.TOTPStep(time.Now())
+	code, _ := utils.TOTPCode(u.TOTPSecret, step)
+
+	mockUserRepo.EXPECT().FindUserById(uint(1)).Return(u, nil)
+	mockLoginAttemptRepo.EXPECT().FindLoginAttempt("mfa:1").Return(nil, errs.NewNotFoundError("record not found"))
+	mockLoginAttemptRepo.EXPECT().ResetLoginAttempts("mfa:1").Return(nil)
+	mockUserRepo.EXPECT().UpdateUserColumns(uint(1), map[string]interface{}{"totp_last_step": step}).Return(nil)
+	mockMFAChallengeRepo.EXPECT().ConsumeMFAChallenge(uint(7)).Return(nil)
+	mockSessionRepo.EXPECT().SaveSession(gomock.Any()).Return(nil)
+	mockRefreshTokenRepo.EXPECT().SaveRefreshToken(gomock.Any()).Return(nil)
+	// Act
+	response, appError := mfaService.VerifyMFA(requests.MFAVerifyRequest{MFAToken: challenge, Code: code})
+
+	// Assert
+	if appError != nil || response.Token == "" {
+		t.Error("Test failed while verifying MFA")
+	}
+}
+
+func Test_should_return_an_error_when_mfa_challenge_was_already_used(t *testing.T) {
+	// Arrange
+	teardown := mfaSetup(t)
+	defer teardown()
+
+	u := mfaUser()
+	challenge := mfaChallenge(u)
+	step := utils.TOTPStep(time.Now())
+	code, _ := utils.TOTPCode(u.TOTPSecret, step)
+
+	mockUserRepo.EXPECT().FindUserById(uint(1)).Return(u, nil)
+	mockLoginAttemptRepo.EXPECT().FindLoginAttempt("mfa:1").Return(nil, errs.NewNotFoundError("record not found"))
+	mockLoginAttemptRepo.EXPECT().ResetLoginAttempts("mfa:1").Return(nil)
+	mockUserRepo.EXPECT().UpdateUserColumns(uint(1), map[string]interface{}{"totp_last_step": step}).Return(nil)
+	// a concurrent verify consumed the challenge first
+	mockMFAChallengeRepo.EXPECT().ConsumeMFAChallenge(uint(7)).Return(errs.NewAuthenticationError("invalid or expired MFA challenge"))
+	// Act
+	_, appError := mfaService.VerifyMFA(requests.MFAVerifyRequest{MFAToken: challenge, Code: code})
+
+	// Assert
+	if appError == nil || appError.Message != "invalid or expired MFA challenge" {
+		t.Error("Test failed while validating used MFA challenge")
+	}
+}
+
+func Test_should_return_an_error_without_checking_the_code_when_mfa_challenge_is_replayed(t *testing.T) {
+	// Arrange
+	teardown := mfaSetup(t)
+	defer teardown()
+
+	u := mfaUser()
+	var challenge *realDomain.MFAChallenge
+	mockMFAChallengeRepo.EXPECT().SaveMFAChallenge(gomock.Any()).DoAndReturn(func(c *realDomain.MFAChallenge) *errs.AppError {
+		challenge = c
+		return nil
+	})
+	response, _ := newMFAChallenges(mockMFAChallengeRepo).issue(u)
+	usedAt := time.Now()
+	challenge.UsedAt = &usedAt
+
+	mockMFAChallengeRepo.EXPECT().FindMFAChallengeByHash(challenge.NonceHash).Return(challenge, nil)
+	// Act
+	_, appError := mfaService.VerifyMFA(requests.MFAVerifyRequest{MFAToken: response.MFAToken, Code: "123456"})
+
+	// Assert
+	if appError == nil || appError.Message != "invalid or expired MFA challenge" {
+		t.Error("Test failed while validating replayed MFA challenge")
+	}
+}
+
+func Test_should_return_an_error_when_mfa_token_was_signed_for_another_purpose(t *testing.T) {
+	// Arrange
+	teardown := mfaSetup(t)
+	defer teardown()
+
+	token := utils.CreateSignedToken(utils.SignedTokenEmailVerification, "1:nonce", time.Minute, "secret")
+
+	// Act
+	_, appError := mfaService.VerifyMFA(requests.MFAVerifyRequest{MFAToken: token, Code: "123456"})
+
+	// Assert
+	if appError == nil || appError.Message != "invalid or expired MFA challenge" {
+		t.Error("Test failed while validating the purpose of the MFA challenge")
+	}
+}
+
+func Test_should_return_an_error_when_totp_code_is_replayed(t *testing.T) {
+	// Arrange
+	teardown := mfaSetup(t)
+	defer teardown()
+
+	u := mfaUser()
+	u.TOTPLastStep = utils.TOTPStep(time.Now())
+	challenge := mfaChallenge(u)
+	code, _ := utils.TOTPCode(u.TOTPSecret, u.TOTPLastStep)
+
+	mockUserRepo.EXPECT().FindUserById(uint(1)).Return(u, nil)
+	mockLoginAttemptRepo.EXPECT().FindLoginAttempt("mfa:1").Return(nil, errs.NewNotFoundError("record not found"))
+	mockLoginAttemptRepo.EXPECT().RegisterFailedLogin("mfa:1", gomock.Any()).Return(&realDomain.LoginAttempt{Failures: 1}, nil)
+	// Act
+	_, appError := mfaService.VerifyMFA(requests.MFAVerifyRequest{MFAToken: challenge, Code: code})
+
+	// Assert
+	if appError == nil || appError.Message != "invalid two-factor code" {
+		t.Error("Test failed while validating replayed TOTP code")
+	}
+}
+
+func Test_should_return_tokens_when_a_recovery_code_is_used(t *testing.T) {
+	// Arrange
+	teardown := mfaSetup(t)
+	defer teardown()
+
+	u := mfaUser()
+	challenge := mfaChallenge(u)
+
+	mockUserRepo.EXPECT().FindUserById(uint(1)).Return(u, nil)
+	mockLoginAttemptRepo.EXPECT().FindLoginAttempt("mfa:1").Return(nil, errs.NewNotFoundError("record not found"))
+	mockRecoveryCodeRepo.EXPECT().ConsumeRecoveryCode(uint(1), utils.HashToken("3f9a1c2b77de0a41")).Return(nil)
+	mockLoginAttemptRepo.EXPECT().ResetLoginAttempts("mfa:1").Return(nil)
+	mockMFAChallengeRepo.EXPECT().ConsumeMFAChallenge(uint(7)).Return(nil)
+	mockSessionRepo.EXPECT().SaveSession(gomock.Any()).Return(nil)
+	mockRefreshTokenRepo.EXPECT().SaveRefreshToken(gomock.Any()).Return(nil)
+	// Act
+	response, appError := mfaService.VerifyMFA(requests.MFAVerifyRequest{MFAToken: challenge, Code: "3F9A-1C2B-77DE-0A41"})
+
+	// Assert
+	if appError != nil || response.Token == "" {
+		t.Error("Test failed while verifying MFA with recovery code")
+	}
+}
+
+func Test_should_enable_mfa_and_return_recovery_codes_when_totp_is_confirmed(t *testing.T) {
+	// Arrange
+	teardown := mfaSetup(t)
+	defer teardown()
+
+	u := mfaUser()
+	u.MFAEnabledAt = nil
+	code, _ := utils.TOTPCode(u.TOTPSecret, utils.TOTPStep(time.Now()))
+
+	mockUserRepo.EXPECT().FindUserById(uint(1)).Return(u, nil)
+	mockLoginAttemptRepo.EXPECT().FindLoginAttempt("mfa:1").Return(nil, errs.NewNotFoundError("record not found"))
+	mockLoginAttemptRepo.EXPECT().ResetLoginAttempts("mfa:1").Return(nil)
+	mockRecoveryCodeRepo.EXPECT().ReplaceRecoveryCodes(uint(1), gomock.Len(10)).Return(nil)
+	mockUserRepo.EXPECT().UpdateUserColumns(uint(1), gomock.Any()).Return(nil)
+	// Act
+	response, appError := mfaService.ConfirmTOTP(1, requests.MFACodeRequest{Code: code})
+
+	// Assert
+	if appError != nil || len(response.RecoveryCodes) != 10 {
+		t.Error("Test failed while confirming TOTP")
+	}
+}
@@ -0,0 +1,212 @@
+package service
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/karlbehrensg/go-fiber-template/internal/domain"
+	"github.com/karlbehrensg/go-fiber-template/internal/http/requests"
+	"github.com/karlbehrensg/go-fiber-template/internal/http/responses"
+	"github.com/karlbehrensg/go-fiber-template/pkg/errs"
+	"github.com/karlbehrensg/go-fiber-template/pkg/logger"
+	"github.com/karlbehrensg/go-fiber-template/pkg/utils"
+)
+
+// OIDCService port primary
+type OIDCService interface {
+	BeginLogin() (redirectURL string, flow string, appErr *errs.AppError)
+	CompleteLogin(requests.OIDCCallbackRequest) (*responses.LoginResponse, *errs.AppError)
+}
+
+type DefaultOIDCService struct {
+	provider   domain.IdentityProvider
+	repo       domain.UserRepository
+	tokenSrv   TokenService
+	challenges mfaChallenges
+}
+
+// NewOIDCService create a new instance of DefaultOIDCService
+func NewOIDCService(
+	provider domain.IdentityProvider,
+	repository domain.UserRepository,
+	tokenService TokenService,
+	mfaChallengeRepository domain.MFAChallengeRepository,
+) DefaultOIDCService {
+	return DefaultOIDCService{provider, repository, tokenService, newMFAChallenges(mfaChallengeRepository)}
+}
+
+// BeginLogin use case for start the authorization code flow, the returned flow must be kept
+// by the client (e.g. in a cookie) and sent back on the callback
+func (s DefaultOIDCService) BeginLogin() (string, string, *errs.AppError) {
+	secret := oidcFlowSecret()
+	if secret == "" {
+		logger.Error("OIDC_STATE_SECRET or JWT_SECRET must be defined to sign the OIDC flow")
+		return "", "", errs.NewUnexpectedError("unexpected error while starting OIDC login")
+	}
+
+	values := make([]string, 3)
+	for i := range values {
+		value, err := utils.GenerateRandomToken(32)
+		if err != nil {
+			logger.Error(err.Error())
+			return "", "", errs.NewUnexpectedError("unexpected error while starting OIDC login")
+		}
+		values[i] = value
+	}
+	state, nonce, verifier := values[0], values[1], values[2]
+
+	// PKCE S256 challenge
+	sum := sha256.Sum256([]byte(verifier))
+	redirectURL, appErr := s.provider.AuthCodeURL(state, nonce, base64.RawURLEncoding.EncodeToString(sum[:]))
+	if appErr != nil {
+		return "", "", appErr
+	}
+
+	flow := utils.CreateSignedToken(utils.SignedTokenOIDCFlow, strings.Join(values, ":"), time.Minute*10, secret)
+	return redirectURL, flow, nil
+}
+
+// CompleteLogin use case for exchange the authorization code, provision the user and return token
+func (s DefaultOIDCService) CompleteLogin(request requests.OIDCCallbackRequest) (*responses.LoginResponse, *errs.AppError) {
+	if request.Error != "" {
+		return nil, errs.NewAuthenticationError(fmt.Sprintf("identity provider error: %s", request.Error))
+	}
+
+	payload, err := utils.ParseSignedToken(utils.SignedTokenOIDCFlow, request.Flow, oidcFlowSecret())
+	if err != nil || oidcFlowSecret() == "" {
+		return nil, errs.NewAuthenticationError("invalid or expired OIDC login")
+	}
+
+	// payload is "<state>:<nonce>:<verifier>"
+	values := strings.Split(payload, ":")
+	if len(values) != 3 || values[0] != request.State {
+		return nil, errs.NewAuthenticationError("invalid or expired OIDC login")
+	}
+
+	identity, appErr := s.provider.Exchange(request.Code, values[2], values[1])
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	u, appErr := s.provisionUser(identity)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	// the identity provider replaces the password, not the second factor
+	if u.IsMFAEnabled() {
+		return s.challenges.issue(u)
+	}
+
+	// create access and refresh token
+	return s.tokenSrv.IssueTokens(u, domain.ClientInfo{IP: request.IP, UserAgent: request.UserAgent, RequestID: request.RequestID})
+}
+
+// provisionUser find the user linked to the subject, linking or creating it just in time; the mapped role
+// is only applied to users created by the identity provider, linked local accounts keep their role
+func (s DefaultOIDCService) provisionUser(identity *domain.IdentityClaims) (*domain.User, *errs.AppError) {
+	role, mapped := mapOIDCRole(identity.Roles)
+
+	u, appErr := s.repo.FindUserByOIDCSubject(identity.Subject)
+	if appErr == nil {
+		if mapped && u.OIDCProvisioned && u.Role != role {
+			if appErr = s.repo.UpdateUserColumns(u.ID, map[string]interface{}{"role": role}); appErr != nil {
+				return nil, appErr
+			}
+			logger.Info(fmt.Sprintf("User id=%d role changed from %s to %s by OIDC", u.ID, u.Role, role))
+			u.Role = role
+		}
+		return u, nil
+	}
+	if !strings.Contains(appErr.Message, "record not found") {
+		return nil, appErr
+	}
+
+	if identity.Email == "" || !identity.EmailVerified {
+		return nil, errs.NewAuthenticationError("identity provider did not return a verified email")
+	}
+
+	// an existing local account with the same verified email is linked to the subject
+	if u, appErr = s.repo.FindUserByEmail(identity.Email); appErr == nil {
+		columns := map[string]interface{}{"oidc_subject": identity.Subject}
+		if !u.IsVerified() {
+			columns["verified_at"] = time.Now()
+		}
+		if appErr = s.repo.UpdateUserColumns(u.ID, columns); appErr != nil {
+			return nil, appErr
+		}
+		logger.Info(fmt.Sprintf("User id=%d linked to OIDC subject", u.ID))
+		return u, nil
+	}
+	if !strings.Contains(appErr.Message, "record not found") {
+		return nil, appErr
+	}
+
+	// the password is random, these users login through the identity provider
+	password, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		logger.Error(err.Error())
+		return nil, errs.NewUnexpectedError("unexpected error while creating user")
+	}
+
+	now := time.Now()
+	subject := identity.Subject
+	u = &domain.User{
+		Name:            identity.Name,
+		Email:           identity.Email,
+		Password:        password,
+		Role:            role,
+		VerifiedAt:      &now,
+		OIDCSubject:     &subject,
+		OIDCProvisioned: true,
+	}
+	if err = u.HashPassword(); err != nil {
+		logger.Error(err.Error())
+		return nil, errs.NewUnexpectedError("unexpected error while creating user")
+	}
+
+	// calls repository to save user
+	if appErr = s.repo.SaveUser(u); appErr != nil {
+		return nil, appErr
+	}
+
+	return u, nil
+}
+
+// mapOIDCRole map the provider roles to the highest local role using OIDC_ROLE_MAP,
+// e.g. "library-admins=admin,library-staff=librarian"; returns false when no mapping is configured
+func mapOIDCRole(roles []string) (string, bool) {
+	mapping := os.Getenv("OIDC_ROLE_MAP")
+	if mapping == "" {
+		return domain.RoleReader, false
+	}
+
+	rank := map[string]int{domain.RoleReader: 0, domain.RoleLibrarian: 1, domain.RoleAdmin: 2}
+	role := domain.RoleReader
+	for _, pair := range strings.Split(mapping, ",") {
+		external, local, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok || !domain.IsValidRole(local) {
+			continue
+		}
+		for _, r := range roles {
+			if r == external && rank[local] > rank[role] {
+				role = local
+			}
+		}
+	}
+
+	return role, true
+}
+
+// oidcFlowSecret secret used to sign the OIDC login flow
+func oidcFlowSecret() string {
+	if secret := os.Getenv("OIDC_STATE_SECRET"); secret != "" {
+		return secret
+	}
+
+	return os.Getenv("JWT_SECRET")
+}
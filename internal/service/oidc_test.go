@@ -0,0 +1,227 @@
+package service
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	realDomain "github.com/karlbehrensg/go-fiber-template/internal/domain"
+	"github.com/karlbehrensg/go-fiber-template/internal/http/requests"
+	"github.com/karlbehrensg/go-fiber-template/mocks/domain"
+	"github.com/karlbehrensg/go-fiber-template/pkg/errs"
+	"github.com/karlbehrensg/go-fiber-template/pkg/utils"
+)
+
+var mockIdentityProvider *domain.MockIdentityProvider
+var oidcService OIDCService
+
+func oidcSetup(t *testing.T) func() {
+	t.Setenv("OIDC_STATE_SECRET", "secret")
+	t.Setenv("OIDC_ROLE_MAP", "library-admins=admin,library-staff=librarian")
+	ctrl := gomock.NewController(t)
+	mockUserRepo = domain.NewMockUserRepository(ctrl)
+	mockRefreshTokenRepo = domain.NewMockRefreshTokenRepository(ctrl)
+	mockRevokedTokenRepo = domain.NewMockRevokedTokenRepository(ctrl)
+	mockSessionRepo = domain.NewMockSessionRepository(ctrl)
+	mockIdentityProvider = domain.NewMockIdentityProvider(ctrl)
+	tokenService := NewTokenService(mockUserRepo, mockRefreshTokenRepo, mockRevokedTokenRepo, mockSessionRepo, nil)
+	mockMFAChallengeRepo = domain.NewMockMFAChallengeRepository(ctrl)
+	oidcService = NewOIDCService(mockIdentityProvider, mockUserRepo, tokenService, mockMFAChallengeRepo)
+	return func() {
+		oidcService = nil
+		defer ctrl.Finish()
+	}
+}
+
+// beginOIDCLogin start the flow and return the state sent to the provider and the flow cookie
+func beginOIDCLogin(t *testing.T) (string, string, string) {
+	var state, nonce string
+	mockIdentityProvider.EXPECT().AuthCodeURL(gomock.Any(), gomock.Any(), gomock.Any()).
+		Do(func(s string, n string, challenge string) { state, nonce = s, n }).
+		Return("http://idp/authorize", nil)
+
+	_, flow, appErr := oidcService.BeginLogin()
+	if appErr != nil {
+		t.Fatal(appErr.Message)
+	}
+
+	return state, nonce, flow
+}
+
+func Test_should_provision_user_with_mapped_role_when_oidc_subject_is_new(t *testing.T) {
+	// Arrange
+	teardown := oidcSetup(t)
+	defer teardown()
+
+	state, nonce, flow := beginOIDCLogin(t)
+	identity := &realDomain.IdentityClaims{
+		Subject: "subject-1", Email: "edwyn@example.com", EmailVerified: true, Roles: []string{"library-staff"},
+	}
+
+	var saved *realDomain.User
+	mockIdentityProvider.EXPECT().Exchange("code", gomock.Any(), nonce).Return(identity, nil)
+	mockUserRepo.EXPECT().FindUserByOIDCSubject("subject-1").Return(nil, errs.NewNotFoundError("record not found"))
+	mockUserRepo.EXPECT().FindUserByEmail("edwyn@example.com").Return(nil, errs.NewNotFoundError("record not found"))
+	mockUserRepo.EXPECT().SaveUser(gomock.Any()).Do(func(u *realDomain.User) { saved = u }).Return(nil)
+	mockSessionRepo.EXPECT().SaveSession(gomock.Any()).Return(nil)
+	mockRefreshTokenRepo.EXPECT().SaveRefreshToken(gomock.Any()).Return(nil)
+	// Act
+	response, appError := oidcService.CompleteLogin(requests.OIDCCallbackRequest{Code: "code", State: state, Flow: flow})
+
+	// Assert
+	if appError != nil || response.Token == "" {
+		t.Fatal("Test failed while completing OIDC login")
+	}
+	if saved.Role != realDomain.RoleLibrarian || *saved.OIDCSubject != "subject-1" || !saved.IsVerified() {
+		t.Error("Failed while provisioning OIDC user")
+	}
+	if !saved.OIDCProvisioned {
+		t.Error("Failed while marking the user as provisioned by OIDC")
+	}
+}
+
+func Test_should_link_existing_account_without_changing_its_role_when_oidc_email_is_verified(t *testing.T) {
+	// Arrange
+	teardown := oidcSetup(t)
+	defer teardown()
+
+	state, _, flow := beginOIDCLogin(t)
+	verifiedAt := time.Now()
+	u := &realDomain.User{ID: 7, Email: "edwyn@example.com", Role: realDomain.RoleReader, VerifiedAt: &verifiedAt}
+	identity := &realDomain.IdentityClaims{
+		Subject: "subject-1", Email: "edwyn@example.com", EmailVerified: true, Roles: []string{"library-admins"},
+	}
+
+	mockIdentityProvider.EXPECT().Exchange(gomock.Any(), gomock.Any(), gomock.Any()).Return(identity, nil)
+	mockUserRepo.EXPECT().FindUserByOIDCSubject("subject-1").Return(nil, errs.NewNotFoundError("record not found"))
+	mockUserRepo.EXPECT().FindUserByEmail("edwyn@example.com").Return(u, nil)
+	mockUserRepo.EXPECT().UpdateUserColumns(uint(7), map[string]interface{}{"oidc_subject": "subject-1"}).Return(nil)
+	mockSessionRepo.EXPECT().SaveSession(gomock.Any()).Return(nil)
+	mockRefreshTokenRepo.EXPECT().SaveRefreshToken(gomock.Any()).Return(nil)
+	// Act
+	_, appError := oidcService.CompleteLogin(requests.OIDCCallbackRequest{Code: "code", State: state, Flow: flow})
+
+	// Assert
+	if appError != nil || u.Role != realDomain.RoleReader {
+		t.Error("Test failed while linking OIDC account")
+	}
+}
+
+func Test_should_update_role_when_oidc_user_was_provisioned_by_the_identity_provider(t *testing.T) {
+	// Arrange
+	teardown := oidcSetup(t)
+	defer teardown()
+
+	state, _, flow := beginOIDCLogin(t)
+	verifiedAt := time.Now()
+	subject := "subject-1"
+	u := &realDomain.User{
+		ID: 7, Email: "edwyn@example.com", Role: realDomain.RoleReader, VerifiedAt: &verifiedAt,
+		OIDCSubject: &subject, OIDCProvisioned: true,
+	}
+	identity := &realDomain.IdentityClaims{
+		Subject: "subject-1", Email: "edwyn@example.com", EmailVerified: true, Roles: []string{"library-admins"},
+	}
+
+	mockIdentityProvider.EXPECT().Exchange(gomock.Any(), gomock.Any(), gomock.Any()).Return(identity, nil)
+	mockUserRepo.EXPECT().FindUserByOIDCSubject("subject-1").Return(u, nil)
+	mockUserRepo.EXPECT().UpdateUserColumns(uint(7), map[string]interface{}{"role": realDomain.RoleAdmin}).Return(nil)
+	mockSessionRepo.EXPECT().SaveSession(gomock.Any()).Return(nil)
+	mockRefreshTokenRepo.EXPECT().SaveRefreshToken(gomock.Any()).Return(nil)
+	// Act
+	_, appError := oidcService.CompleteLogin(requests.OIDCCallbackRequest{Code: "code", State: state, Flow: flow})
+
+	// Assert
+	if appError != nil || u.Role != realDomain.RoleAdmin {
+		t.Error("Test failed while updating role of OIDC account")
+	}
+}
+
+func Test_should_keep_the_role_of_a_linked_local_account_on_later_oidc_logins(t *testing.T) {
+	// Arrange
+	teardown := oidcSetup(t)
+	defer teardown()
+
+	state, _, flow := beginOIDCLogin(t)
+	verifiedAt := time.Now()
+	subject := "subject-1"
+	u := &realDomain.User{ID: 1, Email: "admin@example.com", Role: realDomain.RoleAdmin, VerifiedAt: &verifiedAt, OIDCSubject: &subject}
+	identity := &realDomain.IdentityClaims{
+		Subject: "subject-1", Email: "admin@example.com", EmailVerified: true, Roles: []string{"library-staff"},
+	}
+
+	mockIdentityProvider.EXPECT().Exchange(gomock.Any(), gomock.Any(), gomock.Any()).Return(identity, nil)
+	mockUserRepo.EXPECT().FindUserByOIDCSubject("subject-1").Return(u, nil)
+	mockSessionRepo.EXPECT().SaveSession(gomock.Any()).Return(nil)
+	mockRefreshTokenRepo.EXPECT().SaveRefreshToken(gomock.Any()).Return(nil)
+	// Act
+	_, appError := oidcService.CompleteLogin(requests.OIDCCallbackRequest{Code: "code", State: state, Flow: flow})
+
+	// Assert
+	if appError != nil || u.Role != realDomain.RoleAdmin {
+		t.Error("Test failed while keeping the role of a linked account")
+	}
+}
+
+func Test_should_return_mfa_challenge_instead_of_tokens_when_oidc_user_has_mfa_enabled(t *testing.T) {
+	// Arrange
+	teardown := oidcSetup(t)
+	defer teardown()
+	t.Setenv("MFA_CHALLENGE_SECRET", "secret")
+
+	state, _, flow := beginOIDCLogin(t)
+	verifiedAt := time.Now()
+	subject := "subject-1"
+	u := &realDomain.User{
+		ID: 7, Email: "edwyn@example.com", Role: realDomain.RoleReader, VerifiedAt: &verifiedAt,
+		OIDCSubject: &subject, TOTPSecret: "secret", MFAEnabledAt: &verifiedAt,
+	}
+	identity := &realDomain.IdentityClaims{Subject: "subject-1", Email: "edwyn@example.com", EmailVerified: true}
+
+	mockIdentityProvider.EXPECT().Exchange(gomock.Any(), gomock.Any(), gomock.Any()).Return(identity, nil)
+	mockUserRepo.EXPECT().FindUserByOIDCSubject("subject-1").Return(u, nil)
+	mockMFAChallengeRepo.EXPECT().SaveMFAChallenge(gomock.Any()).Return(nil)
+	// Act
+	response, appError := oidcService.CompleteLogin(requests.OIDCCallbackRequest{Code: "code", State: state, Flow: flow})
+
+	// Assert
+	if appError != nil || !response.MFARequired || response.MFAToken == "" || response.Token != "" {
+		t.Error("Test failed while validating MFA challenge on OIDC login")
+	}
+}
+
+func Test_should_return_status_401_when_oidc_state_does_not_match(t *testing.T) {
+	// Arrange
+	teardown := oidcSetup(t)
+	defer teardown()
+
+	_, _, flow := beginOIDCLogin(t)
+	// Act
+	_, appError := oidcService.CompleteLogin(requests.OIDCCallbackRequest{Code: "code", State: "forged", Flow: flow})
+
+	// Assert
+	if appError == nil || appError.Code != 401 {
+		t.Error("Test failed while validating OIDC state")
+	}
+}
+
+func Test_should_send_s256_challenge_of_the_verifier_when_oidc_login_begins(t *testing.T) {
+	// Arrange
+	teardown := oidcSetup(t)
+	defer teardown()
+
+	var challenge string
+	mockIdentityProvider.EXPECT().AuthCodeURL(gomock.Any(), gomock.Any(), gomock.Any()).
+		Do(func(s string, n string, c string) { challenge = c }).
+		Return("http://idp/authorize", nil)
+	// Act
+	_, flow, _ := oidcService.BeginLogin()
+
+	// Assert
+	payload, _ := utils.ParseSignedToken(utils.SignedTokenOIDCFlow, flow, "secret")
+	values := strings.Split(payload, ":")
+	if len(values) != 3 || challenge == "" || challenge == values[2] {
+		t.Error("Test failed while creating PKCE challenge")
+	}
+}
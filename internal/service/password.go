@@ -0,0 +1,184 @@
+package service
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/karlbehrensg/go-fiber-template/internal/domain"
+	"github.com/karlbehrensg/go-fiber-template/internal/http/requests"
+	"github.com/karlbehrensg/go-fiber-template/pkg/errs"
+	"github.com/karlbehrensg/go-fiber-template/pkg/logger"
+	"github.com/karlbehrensg/go-fiber-template/pkg/utils"
+)
+
+// PasswordService port primary
+type PasswordService interface {
+	ForgotPassword(requests.ForgotPasswordRequest) *errs.AppError
+	ResetPassword(requests.ResetPasswordRequest) *errs.AppError
+	ChangePassword(uint, requests.ChangePasswordRequest) *errs.AppError
+}
+
+type DefaultPasswordService struct {
+	userRepo         domain.UserRepository
+	resetTokenRepo   domain.PasswordResetTokenRepository
+	refreshTokenRepo domain.RefreshTokenRepository
+	sessionRepo      domain.SessionRepository
+	mailer           domain.Mailer
+}
+
+// NewPasswordService create a new instance of DefaultPasswordService
+func NewPasswordService(
+	userRepository domain.UserRepository,
+	resetTokenRepository domain.PasswordResetTokenRepository,
+	refreshTokenRepository domain.RefreshTokenRepository,
+	sessionRepository domain.SessionRepository,
+	mailer domain.Mailer,
+) DefaultPasswordService {
+	return DefaultPasswordService{userRepository, resetTokenRepository, refreshTokenRepository, sessionRepository, mailer}
+}
+
+// ForgotPassword use case for send a single-use reset token, it never reveals if the account exists
+func (s DefaultPasswordService) ForgotPassword(request requests.ForgotPasswordRequest) *errs.AppError {
+	u, appErr := s.userRepo.FindUserByEmail(request.Email)
+	if appErr != nil {
+		if strings.Contains(appErr.Message, "record not found") {
+			return nil
+		}
+		return appErr
+	}
+
+	token, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		logger.Error(err.Error())
+		return errs.NewUnexpectedError("unexpected error while creating reset token")
+	}
+
+	ttl := utils.GetEnvDuration("PASSWORD_RESET_TTL", time.Minute*30)
+	// calls repository to save the hashed reset token
+	if appErr = s.resetTokenRepo.SavePasswordResetToken(&domain.PasswordResetToken{
+		UserID:    u.ID,
+		TokenHash: utils.HashToken(token),
+		ExpiresAt: time.Now().Add(ttl),
+	}); appErr != nil {
+		return appErr
+	}
+
+	// a mailer failure answers the same as a missing account, it is only logged
+	if appErr = s.mailer.Send(domain.Mail{
+		To:      u.Email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf(
+			"Use the following link to reset your password, it expires in %s:\n%s?token=%s",
+			ttl, os.Getenv("PASSWORD_RESET_URL"), token,
+		),
+	}); appErr != nil {
+		logger.Error(fmt.Sprintf("Error while sending reset mail to user id=%d: %s", u.ID, appErr.Message))
+	}
+
+	return nil
+}
+
+// ResetPassword use case for consume the reset token and change the password
+func (s DefaultPasswordService) ResetPassword(request requests.ResetPasswordRequest) *errs.AppError {
+	var token *domain.PasswordResetToken
+	var appErr *errs.AppError
+
+	// find reset token by hash
+	if token, appErr = s.resetTokenRepo.FindPasswordResetTokenByHash(utils.HashToken(request.Token)); appErr != nil {
+		if strings.Contains(appErr.Message, "record not found") {
+			return errs.NewBadRequestError("invalid or expired reset token")
+		}
+		return appErr
+	}
+
+	if token.UsedAt != nil || token.IsExpired() {
+		return errs.NewBadRequestError("invalid or expired reset token")
+	}
+
+	// the password is validated before consuming the token, so a rejected password can be retried
+	current, appErr := s.userRepo.FindUserById(token.UserID)
+	if appErr != nil {
+		return appErr
+	}
+	if appErr = validatePassword(request.Password, current.Email); appErr != nil {
+		return appErr
+	}
+
+	hash, appErr := hashPassword(request.Password)
+	if appErr != nil {
+		return appErr
+	}
+
+	// calls repository to consume the token and change the password together, the token can not be used twice
+	if appErr = s.resetTokenRepo.ConsumePasswordResetToken(token, hash); appErr != nil {
+		return appErr
+	}
+
+	return s.closeSessions(token.UserID)
+}
+
+// ChangePassword use case for change the password of the authenticated user, every session is closed
+func (s DefaultPasswordService) ChangePassword(userID uint, request requests.ChangePasswordRequest) *errs.AppError {
+	u, appErr := s.userRepo.FindUserById(userID)
+	if appErr != nil {
+		return appErr
+	}
+
+	if u.ComparePassword(request.CurrentPassword) != nil {
+		return errs.NewBadRequestError("current password is invalid")
+	}
+	if request.Password == request.CurrentPassword {
+		return errs.NewBadRequestError("new password must be different from the current one")
+	}
+	if appErr = validatePassword(request.Password, u.Email); appErr != nil {
+		return appErr
+	}
+
+	return s.updatePassword(u.ID, request.Password)
+}
+
+// updatePassword save the new password and close the sessions opened with the old one
+func (s DefaultPasswordService) updatePassword(userID uint, password string) *errs.AppError {
+	hash, appErr := hashPassword(password)
+	if appErr != nil {
+		return appErr
+	}
+
+	// the token version is increased, access tokens issued before are rejected
+	if appErr = s.userRepo.UpdatePassword(userID, hash); appErr != nil {
+		return appErr
+	}
+
+	return s.closeSessions(userID)
+}
+
+// closeSessions revoke the refresh tokens and sessions opened with the old password
+func (s DefaultPasswordService) closeSessions(userID uint) *errs.AppError {
+	if appErr := s.refreshTokenRepo.RevokeUserRefreshTokens(userID); appErr != nil {
+		return appErr
+	}
+
+	return s.sessionRepo.RevokeUserSessions(userID, 0)
+}
+
+// hashPassword hash the new password with the configured algorithm
+func hashPassword(password string) (string, *errs.AppError) {
+	u := &domain.User{Password: password}
+	if err := u.HashPassword(); err != nil {
+		logger.Error(fmt.Sprintf("Error while encripting password: %s", err.Error()))
+		return "", errs.NewUnexpectedError("Unexpected error while encripting password")
+	}
+
+	return u.Password, nil
+}
+
+// validatePassword validate a new password against the configured policy
+func validatePassword(password string, email string) *errs.AppError {
+	if err := utils.GetPasswordPolicy().Validate(password, email); err != nil {
+		return errs.NewBadRequestError(err.Error())
+	}
+
+	return nil
+}
@@ -0,0 +1,207 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	realDomain "github.com/karlbehrensg/go-fiber-template/internal/domain"
+	"github.com/karlbehrensg/go-fiber-template/internal/http/requests"
+	"github.com/karlbehrensg/go-fiber-template/mocks/domain"
+	"github.com/karlbehrensg/go-fiber-template/pkg/errs"
+	"github.com/karlbehrensg/go-fiber-template/pkg/utils"
+)
+
+var mockPasswordResetTokenRepo *domain.MockPasswordResetTokenRepository
+var mockMailer *domain.MockMailer
+var passwordService PasswordService
+
+func passwordSetup(t *testing.T) func() {
+	ctrl := gomock.NewController(t)
+	mockUserRepo = domain.NewMockUserRepository(ctrl)
+	mockPasswordResetTokenRepo = domain.NewMockPasswordResetTokenRepository(ctrl)
+	mockRefreshTokenRepo = domain.NewMockRefreshTokenRepository(ctrl)
+	mockSessionRepo = domain.NewMockSessionRepository(ctrl)
+	mockMailer = domain.NewMockMailer(ctrl)
+	passwordService = NewPasswordService(mockUserRepo, mockPasswordResetTokenRepo, mockRefreshTokenRepo, mockSessionRepo, mockMailer)
+	return func() {
+		passwordService = nil
+		defer ctrl.Finish()
+	}
+}
+
+func Test_should_not_reveal_missing_account_when_forgot_password(t *testing.T) {
+	// Arrange
+	teardown := passwordSetup(t)
+	defer teardown()
+
+	mockUserRepo.EXPECT().FindUserByEmail("unknown@example.com").Return(nil, errs.NewNotFoundError("record not found"))
+	// Act
+	appError := passwordService.ForgotPassword(requests.ForgotPasswordRequest{Email: "unknown@example.com"})
+
+	// Assert
+	if appError != nil {
+		t.Error("Test failed while requesting password reset for missing account")
+	}
+}
+
+func Test_should_send_reset_mail_when_forgot_password(t *testing.T) {
+	// Arrange
+	teardown := passwordSetup(t)
+	defer teardown()
+
+	u := &realDomain.User{ID: 1, Email: "edwyn.rangel.externo@zeleri.com"}
+
+	mockUserRepo.EXPECT().FindUserByEmail(u.Email).Return(u, nil)
+	mockPasswordResetTokenRepo.EXPECT().SavePasswordResetToken(gomock.Any()).Return(nil)
+	mockMailer.EXPECT().Send(gomock.Any()).Return(nil)
+	// Act
+	appError := passwordService.ForgotPassword(requests.ForgotPasswordRequest{Email: u.Email})
+
+	// Assert
+	if appError != nil {
+		t.Error("Test failed while requesting password reset")
+	}
+}
+
+func Test_should_not_reveal_the_account_when_the_reset_mail_cannot_be_sent(t *testing.T) {
+	// Arrange
+	teardown := passwordSetup(t)
+	defer teardown()
+
+	u := &realDomain.User{ID: 1, Email: "edwyn.rangel.externo@zeleri.com"}
+
+	mockUserRepo.EXPECT().FindUserByEmail(u.Email).Return(u, nil)
+	mockPasswordResetTokenRepo.EXPECT().SavePasswordResetToken(gomock.Any()).Return(nil)
+	mockMailer.EXPECT().Send(gomock.Any()).Return(errs.NewUnexpectedError("smtp unavailable"))
+	// Act
+	appError := passwordService.ForgotPassword(requests.ForgotPasswordRequest{Email: u.Email})
+
+	// Assert
+	if appError != nil {
+		t.Error("Test failed while hiding the mailer error")
+	}
+}
+
+func Test_should_return_an_error_when_reset_token_was_already_used(t *testing.T) {
+	// Arrange
+	teardown := passwordSetup(t)
+	defer teardown()
+
+	usedAt := time.Now()
+	token := &realDomain.PasswordResetToken{ID: 1, UserID: 1, ExpiresAt: time.Now().Add(time.Minute), UsedAt: &usedAt}
+
+	mockPasswordResetTokenRepo.EXPECT().FindPasswordResetTokenByHash(utils.HashToken("reset")).Return(token, nil)
+	// Act
+	appError := passwordService.ResetPassword(requests.ResetPasswordRequest{Token: "reset", Password: "1234567"})
+
+	// Assert
+	if appError == nil || appError.Message != "invalid or expired reset token" {
+		t.Error("Test failed while validating used reset token")
+	}
+}
+
+func Test_should_return_an_error_when_reset_token_is_expired(t *testing.T) {
+	// Arrange
+	teardown := passwordSetup(t)
+	defer teardown()
+
+	token := &realDomain.PasswordResetToken{ID: 1, UserID: 1, ExpiresAt: time.Now().Add(-time.Minute)}
+
+	mockPasswordResetTokenRepo.EXPECT().FindPasswordResetTokenByHash(utils.HashToken("reset")).Return(token, nil)
+	// Act
+	appError := passwordService.ResetPassword(requests.ResetPasswordRequest{Token: "reset", Password: "1234567"})
+
+	// Assert
+	if appError == nil {
+		t.Error("Test failed while validating expired reset token")
+	}
+}
+
+func Test_should_return_status_400_when_current_password_is_invalid(t *testing.T) {
+	// Arrange
+	teardown := passwordSetup(t)
+	defer teardown()
+	t.Setenv("PASSWORD_BCRYPT_COST", "4")
+
+	u := &realDomain.User{ID: 1, Email: "edwyn@example.com", Password: "1234567"}
+	u.HashPassword()
+
+	mockUserRepo.EXPECT().FindUserById(uint(1)).Return(u, nil)
+	// Act
+	appError := passwordService.ChangePassword(1, requests.ChangePasswordRequest{CurrentPassword: "wrong-password", Password: "7654321"})
+
+	// Assert
+	if appError == nil || appError.Code != 400 {
+		t.Error("Test failed while validating current password")
+	}
+}
+
+func Test_should_increase_token_version_and_revoke_sessions_when_password_is_changed(t *testing.T) {
+	// Arrange
+	teardown := passwordSetup(t)
+	defer teardown()
+	t.Setenv("PASSWORD_BCRYPT_COST", "4")
+
+	u := &realDomain.User{ID: 1, Email: "edwyn@example.com", Password: "1234567"}
+	u.HashPassword()
+
+	mockUserRepo.EXPECT().FindUserById(uint(1)).Return(u, nil)
+	mockUserRepo.EXPECT().UpdatePassword(uint(1), gomock.Any()).Return(nil)
+	mockRefreshTokenRepo.EXPECT().RevokeUserRefreshTokens(uint(1)).Return(nil)
+	mockSessionRepo.EXPECT().RevokeUserSessions(uint(1), uint(0)).Return(nil)
+	// Act
+	appError := passwordService.ChangePassword(1, requests.ChangePasswordRequest{CurrentPassword: "1234567", Password: "87654321"})
+
+	// Assert
+	if appError != nil {
+		t.Error("Test failed while changing password")
+	}
+}
+
+func Test_should_keep_reset_token_when_new_password_is_rejected(t *testing.T) {
+	// Arrange
+	teardown := passwordSetup(t)
+	defer teardown()
+
+	token := &realDomain.PasswordResetToken{ID: 1, UserID: 1, ExpiresAt: time.Now().Add(time.Minute)}
+
+	mockPasswordResetTokenRepo.EXPECT().FindPasswordResetTokenByHash(utils.HashToken("reset")).Return(token, nil)
+	mockUserRepo.EXPECT().FindUserById(uint(1)).Return(&realDomain.User{ID: 1, Email: "edwyn@example.com"}, nil)
+	mockPasswordResetTokenRepo.EXPECT().ConsumePasswordResetToken(gomock.Any(), gomock.Any()).Times(0)
+	// Act
+	appError := passwordService.ResetPassword(requests.ResetPasswordRequest{Token: "reset", Password: "short"})
+
+	// Assert
+	if appError == nil || appError.Code != 400 {
+		t.Error("Test failed while validating rejected password on reset")
+	}
+}
+
+func Test_should_consume_reset_token_and_change_password_together_when_password_is_reset(t *testing.T) {
+	// Arrange
+	teardown := passwordSetup(t)
+	defer teardown()
+	t.Setenv("PASSWORD_BCRYPT_COST", "4")
+
+	token := &realDomain.PasswordResetToken{ID: 1, UserID: 1, ExpiresAt: time.Now().Add(time.Minute)}
+	var hash string
+
+	mockPasswordResetTokenRepo.EXPECT().FindPasswordResetTokenByHash(utils.HashToken("reset")).Return(token, nil)
+	mockUserRepo.EXPECT().FindUserById(uint(1)).Return(&realDomain.User{ID: 1, Email: "edwyn@example.com"}, nil)
+	mockPasswordResetTokenRepo.EXPECT().ConsumePasswordResetToken(token, gomock.Any()).
+		DoAndReturn(func(_ *realDomain.PasswordResetToken, h string) *errs.AppError {
+			hash = h
+			return nil
+		})
+	mockRefreshTokenRepo.EXPECT().RevokeUserRefreshTokens(uint(1)).Return(nil)
+	mockSessionRepo.EXPECT().RevokeUserSessions(uint(1), uint(0)).Return(nil)
+	// Act
+	appError := passwordService.ResetPassword(requests.ResetPasswordRequest{Token: "reset", Password: "87654321"})
+
+	// Assert
+	u := &realDomain.User{Password: hash}
+	if appError != nil || u.ComparePassword("87654321") != nil {
+		t.Error("Test failed while resetting password")
+	}
+}
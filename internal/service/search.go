@@ -0,0 +1,155 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/karlbehrensg/go-fiber-template/internal/domain"
+	"github.com/karlbehrensg/go-fiber-template/internal/http/requests"
+	"github.com/karlbehrensg/go-fiber-template/internal/http/responses"
+	"github.com/karlbehrensg/go-fiber-template/pkg/errs"
+	"github.com/karlbehrensg/go-fiber-template/pkg/logger"
+)
+
+// searchRebuildBatch rows read from the database on each query of the rebuild
+const searchRebuildBatch = 500
+
+// SearchService port primary
+type SearchService interface {
+	Search(requests.SearchRequest) (*responses.SearchResponse, *errs.AppError)
+	RebuildIndex() (*responses.SearchRebuildResponse, *errs.AppError)
+}
+
+type DefaultSearchService struct {
+	index      domain.SearchIndex
+	authorRepo domain.AuthorRepository
+	bookRepo   domain.BookRepository
+}
+
+// NewSearchService create a new instance of DefaultSearchService
+func NewSearchService(
+	index domain.SearchIndex,
+	authorRepository domain.AuthorRepository,
+	bookRepository domain.BookRepository,
+) DefaultSearchService {
+	return DefaultSearchService{index, authorRepository, bookRepository}
+}
+
+// Search use case for find the books and authors matching the text
+func (s DefaultSearchService) Search(request requests.SearchRequest) (*responses.SearchResponse, *errs.AppError) {
+	query := domain.SearchQuery{Text: request.Q, Limit: request.Limit}
+	if query.Limit < 1 {
+		query.Limit = 20
+	}
+	if request.Type != "" {
+		query.Kinds = []string{request.Type}
+	}
+
+	// calls index to find the best hits
+	hits, total, appErr := s.index.Search(query)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	response := &responses.SearchResponse{Items: make([]responses.SearchHitResponse, 0, len(hits)), Total: total}
+	for _, hit := range hits {
+		response.Items = append(response.Items, responses.SearchHitResponse{
+			Type:       hit.Kind,
+			Id:         hit.ID,
+			Score:      hit.Score,
+			Fields:     hit.Fields,
+			Highlights: hit.Highlights,
+		})
+	}
+
+	return response, nil
+}
+
+// RebuildIndex use case for replace the content of the index with the authors and books of the database
+func (s DefaultSearchService) RebuildIndex() (*responses.SearchRebuildResponse, *errs.AppError) {
+	response := &responses.SearchRebuildResponse{}
+
+	// calls index to swap its content with the documents read from the database
+	if appErr := s.index.RebuildDocuments(func() ([]domain.SearchDocument, *errs.AppError) {
+		return s.loadDocuments(response)
+	}); appErr != nil {
+		return nil, appErr
+	}
+	logger.Info(fmt.Sprintf("Search index rebuilt with %d authors and %d books", response.Authors, response.Books))
+
+	return response, nil
+}
+
+// loadDocuments read the authors and books of the database by batches of IDs, counting them in the response
+func (s DefaultSearchService) loadDocuments(response *responses.SearchRebuildResponse) ([]domain.SearchDocument, *errs.AppError) {
+	docs := make([]domain.SearchDocument, 0)
+
+	query := domain.ListQuery{Sort: []domain.ListSort{{Column: "id"}}, Limit: searchRebuildBatch}
+	for {
+		authors, _, appErr := s.authorRepo.FindAllAuthor(query)
+		if appErr != nil {
+			return nil, appErr
+		}
+		for _, author := range authors {
+			docs = append(docs, author.ToSearchDocument())
+		}
+		response.Authors += len(authors)
+		if len(authors) < searchRebuildBatch {
+			break
+		}
+		query.After = &domain.ListCursor{Values: []interface{}{authors[len(authors)-1].ID}}
+	}
+
+	query.After = nil
+	for {
+		books, _, appErr := s.bookRepo.FindAllBook(query)
+		if appErr != nil {
+			return nil, appErr
+		}
+		for _, book := range books {
+			docs = append(docs, book.ToSearchDocument())
+		}
+		response.Books += len(books)
+		if len(books) < searchRebuildBatch {
+			break
+		}
+		query.After = &domain.ListCursor{Values: []interface{}{books[len(books)-1].ID}}
+	}
+
+	return docs, nil
+}
+
+// searchSync keeps the search index in step with the catalog writes, a failure is logged and never stops the use case
+type searchSync struct {
+	index domain.SearchIndex
+}
+
+func newSearchSync(index domain.SearchIndex) searchSync {
+	return searchSync{index}
+}
+
+// enabled the catalog services can be used without index
+func (s searchSync) enabled() bool {
+	return s.index != nil
+}
+
+// put add or replace the document
+func (s searchSync) put(doc domain.SearchDocument) {
+	if s.index == nil {
+		return
+	}
+
+	if appErr := s.index.IndexDocument(doc); appErr != nil {
+		logger.Error(fmt.Sprintf("Error while indexing %s %d: %s", doc.Kind, doc.ID, appErr.Message))
+	}
+}
+
+// remove drop the document
+func (s searchSync) remove(kind string, id uint) {
+	if s.index == nil {
+		return
+	}
+
+	if appErr := s.index.RemoveDocument(kind, id); appErr != nil {
+		logger.Error(fmt.Sprintf("Error while removing %s %d from the index: %s", kind, id, appErr.Message))
+	}
+}
@@ -0,0 +1,127 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	realDomain "github.com/karlbehrensg/go-fiber-template/internal/domain"
+	"github.com/karlbehrensg/go-fiber-template/internal/http/requests"
+	"github.com/karlbehrensg/go-fiber-template/mocks/domain"
+	"github.com/karlbehrensg/go-fiber-template/pkg/errs"
+)
+
+var mockSearchIndex *domain.MockSearchIndex
+var searchService SearchService
+
+func searchSetup(t *testing.T) func() {
+	ctrl := gomock.NewController(t)
+	mockSearchIndex = domain.NewMockSearchIndex(ctrl)
+	mockBookRepo = domain.NewMockBookRepository(ctrl)
+	mockAuthorRepo = domain.NewMockAuthorRepository(ctrl)
+	searchService = NewSearchService(mockSearchIndex, mockAuthorRepo, mockBookRepo)
+	bookService = NewBookService(mockBookRepo, mockAuthorRepo, nil, mockSearchIndex)
+	authorService = NewAuthorService(mockAuthorRepo, nil, mockSearchIndex)
+	return func() {
+		searchService = nil
+		defer ctrl.Finish()
+	}
+}
+
+func Test_should_rebuild_the_index_by_batches(t *testing.T) {
+	// Arrange
+	teardown := searchSetup(t)
+	defer teardown()
+
+	authors := make([]realDomain.Author, searchRebuildBatch)
+	for i := range authors {
+		authors[i] = realDomain.Author{ID: uint(i + 1), FullName: "J. J. Benítez"}
+	}
+	var docs []realDomain.SearchDocument
+
+	gomock.InOrder(
+		mockAuthorRepo.EXPECT().FindAllAuthor(gomock.Any()).DoAndReturn(func(q realDomain.ListQuery) ([]realDomain.Author, int64, *errs.AppError) {
+			if q.After != nil || q.Limit != searchRebuildBatch {
+				t.Errorf("Failed while matching first batch: %+v", q)
+			}
+			return authors, 0, nil
+		}),
+		mockAuthorRepo.EXPECT().FindAllAuthor(gomock.Any()).DoAndReturn(func(q realDomain.ListQuery) ([]realDomain.Author, int64, *errs.AppError) {
+			if q.After == nil || q.After.Values[0] != uint(searchRebuildBatch) {
+				t.Errorf("Failed while matching second batch: %+v", q)
+			}
+			return []realDomain.Author{}, 0, nil
+		}),
+	)
+	mockBookRepo.EXPECT().FindAllBook(gomock.Any()).Return([]realDomain.Book{{ID: 1, Title: "Caballo de Troya 1", Author: &authors[0]}}, int64(1), nil)
+	mockSearchIndex.EXPECT().RebuildDocuments(gomock.Any()).DoAndReturn(func(load func() ([]realDomain.SearchDocument, *errs.AppError)) *errs.AppError {
+		var appErr *errs.AppError
+		docs, appErr = load()
+		return appErr
+	})
+	// Act
+	response, appError := searchService.RebuildIndex()
+
+	// Assert
+	if appError != nil {
+		t.Fatal("Test failed while rebuilding the index")
+	}
+	if response.Authors != searchRebuildBatch || response.Books != 1 || len(docs) != searchRebuildBatch+1 {
+		t.Errorf("Failed while matching rebuild: %+v", response)
+	}
+	if book := docs[len(docs)-1]; book.Kind != realDomain.SearchKindBook || book.Fields["author_name"] != "J. J. Benítez" {
+		t.Errorf("Failed while matching book document: %+v", book)
+	}
+}
+
+func Test_should_index_the_new_book_with_the_name_of_its_author(t *testing.T) {
+	// Arrange
+	teardown := searchSetup(t)
+	defer teardown()
+
+	req := requests.BookRequest{Title: "Caballo de Troya 1", AuthorID: 30, PublicationYear: "1984"}
+
+	mockAuthorRepo.EXPECT().FindAuthorById(uint(30)).Return(&realDomain.Author{ID: 30, FullName: "J. J. Benítez"}, nil)
+	mockBookRepo.EXPECT().SaveBook(gomock.Any()).DoAndReturn(func(b *realDomain.Book) *errs.AppError {
+		b.ID = 1
+		return nil
+	})
+	mockSearchIndex.EXPECT().IndexDocument(realDomain.SearchDocument{
+		Kind:   realDomain.SearchKindBook,
+		ID:     1,
+		Fields: map[string]string{"title": "Caballo de Troya 1", "author_name": "J. J. Benítez"},
+	}).Return(nil)
+	// Act
+	appError := bookService.CreateBook(auditActor, req)
+
+	// Assert
+	if appError != nil {
+		t.Fatal("Test failed while creating book")
+	}
+}
+
+func Test_should_move_the_books_in_the_index_when_they_are_reassigned(t *testing.T) {
+	// Arrange
+	teardown := searchSetup(t)
+	defer teardown()
+
+	target := &realDomain.Author{ID: 2, FullName: "Isaac Asimov"}
+	author := &realDomain.Author{ID: 1, FullName: "J. J. Benitez"}
+
+	mockAuthorRepo.EXPECT().FindAuthorById(uint(2)).Return(target, nil)
+	mockAuthorRepo.EXPECT().FindAuthorById(uint(1)).Return(author, nil)
+	mockAuthorRepo.EXPECT().DeleteAuthor(uint(1), realDomain.AuthorDeleteReassign, uint(2)).
+		Return([]realDomain.Book{{ID: 7, Title: "Caballo de Troya 1", AuthorID: 1}}, nil)
+	mockSearchIndex.EXPECT().RemoveDocument(realDomain.SearchKindAuthor, uint(1)).Return(nil)
+	mockSearchIndex.EXPECT().IndexDocument(realDomain.SearchDocument{
+		Kind:   realDomain.SearchKindBook,
+		ID:     7,
+		Fields: map[string]string{"title": "Caballo de Troya 1", "author_name": "Isaac Asimov"},
+	}).Return(nil)
+	// Act
+	appError := authorService.DeleteAuthor(auditActor, requests.DeleteAuthorRequest{Id: 1, OnBooks: realDomain.AuthorDeleteReassign, ReassignTo: 2})
+
+	// Assert
+	if appError != nil {
+		t.Fatal("Test failed while deleting author")
+	}
+}
@@ -0,0 +1,60 @@
+package service
+
+import (
+	"strings"
+
+	"github.com/karlbehrensg/go-fiber-template/internal/domain"
+	"github.com/karlbehrensg/go-fiber-template/internal/http/responses"
+	"github.com/karlbehrensg/go-fiber-template/pkg/errs"
+	"github.com/karlbehrensg/go-fiber-template/pkg/utils"
+)
+
+// SessionService port primary
+type SessionService interface {
+	FindSessions(*utils.JWTClaims) ([]responses.SessionResponse, *errs.AppError)
+	RevokeSession(*utils.JWTClaims, uint) *errs.AppError
+	RevokeOtherSessions(*utils.JWTClaims) *errs.AppError
+}
+
+type DefaultSessionService struct {
+	repo domain.SessionRepository
+}
+
+// NewSessionService create a new instance of DefaultSessionService
+func NewSessionService(repository domain.SessionRepository) DefaultSessionService {
+	return DefaultSessionService{repository}
+}
+
+// FindSessions use case for list the sessions of the user, marking the one of the token
+func (s DefaultSessionService) FindSessions(claims *utils.JWTClaims) ([]responses.SessionResponse, *errs.AppError) {
+	// calls repository to find the active sessions
+	sessions, appErr := s.repo.FindActiveSessionsByUser(claims.UserID)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	response := make([]responses.SessionResponse, 0, len(sessions))
+	for _, session := range sessions {
+		response = append(response, *session.ToNewSessionResponse(session.ID == claims.SessionID))
+	}
+
+	return response, nil
+}
+
+// RevokeSession use case for sign out one session of the user, its tokens are rejected right away
+func (s DefaultSessionService) RevokeSession(claims *utils.JWTClaims, id uint) *errs.AppError {
+	// calls repository to revoke the session, only the own sessions are found
+	if appErr := s.repo.RevokeSession(claims.UserID, id); appErr != nil {
+		if strings.Contains(appErr.Message, "record not found") {
+			return errs.NewNotFoundError("session not found")
+		}
+		return appErr
+	}
+
+	return nil
+}
+
+// RevokeOtherSessions use case for sign out everywhere except the session of the token
+func (s DefaultSessionService) RevokeOtherSessions(claims *utils.JWTClaims) *errs.AppError {
+	return s.repo.RevokeUserSessions(claims.UserID, claims.SessionID)
+}
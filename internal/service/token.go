@@ -0,0 +1,296 @@
+package service
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/karlbehrensg/go-fiber-template/internal/domain"
+	"github.com/karlbehrensg/go-fiber-template/internal/http/requests"
+	"github.com/karlbehrensg/go-fiber-template/internal/http/responses"
+	"github.com/karlbehrensg/go-fiber-template/pkg/errs"
+	"github.com/karlbehrensg/go-fiber-template/pkg/logger"
+	"github.com/karlbehrensg/go-fiber-template/pkg/utils"
+)
+
+// TokenService port primary
+type TokenService interface {
+	IssueTokens(*domain.User, domain.ClientInfo) (*responses.LoginResponse, *errs.AppError)
+	RefreshTokens(requests.RefreshTokenRequest) (*responses.LoginResponse, *errs.AppError)
+	RevokeTokens(*utils.JWTClaims, requests.LogoutRequest) *errs.AppError
+	Impersonate(actor domain.Actor, userID uint) (*responses.ImpersonationResponse, *errs.AppError)
+}
+
+type DefaultTokenService struct {
+	userRepo         domain.UserRepository
+	refreshTokenRepo domain.RefreshTokenRepository
+	revokedTokenRepo domain.RevokedTokenRepository
+	sessionRepo      domain.SessionRepository
+	audit            auditLog
+}
+
+// NewTokenService create a new instance of DefaultTokenService
+func NewTokenService(
+	userRepository domain.UserRepository,
+	refreshTokenRepository domain.RefreshTokenRepository,
+	revokedTokenRepository domain.RevokedTokenRepository,
+	sessionRepository domain.SessionRepository,
+	auditRepository domain.AuditRepository,
+) DefaultTokenService {
+	return DefaultTokenService{
+		userRepository,
+		refreshTokenRepository,
+		revokedTokenRepository,
+		sessionRepository,
+		newAuditLog(auditRepository),
+	}
+}
+
+// IssueTokens use case for open a session, create the access token and a new refresh token family
+func (s DefaultTokenService) IssueTokens(u *domain.User, client domain.ClientInfo) (*responses.LoginResponse, *errs.AppError) {
+	if u.IsDisabled() {
+		return nil, errs.NewAuthorizationError("account is disabled")
+	}
+
+	familyID, err := utils.GenerateRandomToken(16)
+	if err != nil {
+		logger.Error(err.Error())
+		return nil, errs.NewUnexpectedError("unexpected error while creating token")
+	}
+
+	refreshToken, token, appErr := newRefreshToken(u.ID, familyID)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	// calls repository to save the session owning the refresh token family
+	session := &domain.Session{
+		UserID:     u.ID,
+		FamilyID:   familyID,
+		UserAgent:  client.UserAgent,
+		IP:         client.IP,
+		LastSeenAt: time.Now(),
+	}
+	if appErr = s.sessionRepo.SaveSession(session); appErr != nil {
+		return nil, appErr
+	}
+
+	// calls repository to save refresh token
+	if appErr = s.refreshTokenRepo.SaveRefreshToken(token); appErr != nil {
+		return nil, appErr
+	}
+
+	s.audit.record(
+		domain.Actor{UserID: u.ID, Email: u.Email, ClientInfo: client},
+		domain.AuditEntry{
+			Action:     domain.AuditLogin,
+			TargetType: "session",
+			TargetID:   strconv.FormatUint(uint64(session.ID), 10),
+			Detail:     client.UserAgent,
+		},
+		nil, nil,
+	)
+
+	return createLoginResponse(u, refreshToken, session.ID)
+}
+
+// RefreshTokens use case for rotate the refresh token and create a new access token
+func (s DefaultTokenService) RefreshTokens(request requests.RefreshTokenRequest) (*responses.LoginResponse, *errs.AppError) {
+	var current *domain.RefreshToken
+	var appErr *errs.AppError
+
+	// find refresh token by hash
+	if current, appErr = s.refreshTokenRepo.FindRefreshTokenByHash(utils.HashToken(request.RefreshToken)); appErr != nil {
+		if strings.Contains(appErr.Message, "record not found") {
+			return nil, errs.NewAuthenticationError("invalid refresh token")
+		}
+		return nil, appErr
+	}
+
+	if current.RevokedAt != nil {
+		return nil, errs.NewAuthenticationError("invalid refresh token")
+	}
+
+	// an already rotated token is being reused, the whole family is compromised
+	if current.RotatedAt != nil {
+		return nil, s.revokeFamily(current)
+	}
+
+	if current.IsExpired() {
+		return nil, errs.NewAuthenticationError("refresh token expired")
+	}
+
+	var u *domain.User
+	if u, appErr = s.userRepo.FindUserById(current.UserID); appErr != nil {
+		if strings.Contains(appErr.Message, "record not found") {
+			return nil, errs.NewAuthenticationError("invalid refresh token")
+		}
+		return nil, appErr
+	}
+
+	if u.IsDisabled() {
+		return nil, errs.NewAuthorizationError("account is disabled")
+	}
+
+	// the session was signed out remotely
+	var session *domain.Session
+	if session, appErr = s.sessionRepo.FindSessionByFamily(current.FamilyID); appErr != nil {
+		if strings.Contains(appErr.Message, "record not found") {
+			return nil, errs.NewAuthenticationError("invalid refresh token")
+		}
+		return nil, appErr
+	}
+	if !session.IsActive() {
+		return nil, errs.NewAuthenticationError("session has been signed out")
+	}
+
+	refreshToken, next, appErr := newRefreshToken(u.ID, current.FamilyID)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	// calls repository to rotate refresh token
+	if appErr = s.refreshTokenRepo.RotateRefreshToken(current, next); appErr != nil {
+		if appErr.Code == http.StatusUnauthorized {
+			return nil, s.revokeFamily(current)
+		}
+		return nil, appErr
+	}
+
+	if appErr = s.sessionRepo.TouchSession(session.ID, request.IP, time.Now()); appErr != nil {
+		logger.Error(appErr.Message)
+	}
+
+	return createLoginResponse(u, refreshToken, session.ID)
+}
+
+// RevokeTokens use case for revoke the access token and, when given, the refresh token family
+func (s DefaultTokenService) RevokeTokens(claims *utils.JWTClaims, request requests.LogoutRequest) *errs.AppError {
+	// calls repository to revoke the access token until it is no longer accepted, leeway included
+	if appErr := s.revokedTokenRepo.SaveRevokedToken(&domain.RevokedToken{
+		JTI:       claims.Id,
+		ExpiresAt: claims.AcceptedUntil(),
+	}); appErr != nil {
+		return appErr
+	}
+
+	// calls repository to sign out the session of the token
+	if claims.SessionID != 0 {
+		if appErr := s.sessionRepo.RevokeSession(claims.UserID, claims.SessionID); appErr != nil &&
+			!strings.Contains(appErr.Message, "record not found") {
+			return appErr
+		}
+	}
+
+	if request.RefreshToken == "" {
+		return nil
+	}
+
+	token, appErr := s.refreshTokenRepo.FindRefreshTokenByHash(utils.HashToken(request.RefreshToken))
+	if appErr != nil {
+		if strings.Contains(appErr.Message, "record not found") {
+			return nil
+		}
+		return appErr
+	}
+
+	// a user can only revoke its own sessions
+	if token.UserID != claims.UserID {
+		return nil
+	}
+
+	return s.refreshTokenRepo.RevokeRefreshTokenFamily(token.FamilyID)
+}
+
+// Impersonate use case for mint a short-lived access token of the user for the admin actor, it can not be refreshed
+func (s DefaultTokenService) Impersonate(actor domain.Actor, userID uint) (*responses.ImpersonationResponse, *errs.AppError) {
+	if actor.ImpersonatorID != 0 {
+		return nil, errs.NewAuthorizationError("not allowed while impersonating")
+	}
+	if actor.UserID == userID {
+		return nil, errs.NewBadRequestError("you can not impersonate yourself")
+	}
+
+	u, appErr := s.userRepo.FindUserById(userID)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	// impersonating an admin would hide who made the changes behind another admin
+	if u.Role == domain.RoleAdmin {
+		return nil, errs.NewAuthorizationError("admins can not be impersonated")
+	}
+	if u.IsDisabled() {
+		return nil, errs.NewAuthorizationError("account is disabled")
+	}
+
+	claims := u.ToNewUtilsJWTClaims()
+	claims.Actor = &utils.JWTActor{UserID: actor.UserID, Email: actor.Email}
+	token, err := claims.CreateToken()
+	if err != nil {
+		logger.Error(err.Error())
+		return nil, errs.NewUnexpectedError("unexpected error while creating token")
+	}
+
+	s.audit.record(
+		actor,
+		domain.AuditEntry{
+			Action:     domain.AuditImpersonate,
+			TargetType: "user",
+			TargetID:   strconv.FormatUint(uint64(u.ID), 10),
+			Detail:     fmt.Sprintf("token %s expires at %s", claims.Id, time.Unix(claims.ExpiresAt, 0).Format(time.RFC3339)),
+		},
+		nil, nil,
+	)
+
+	return &responses.ImpersonationResponse{
+		Token:     token,
+		UserID:    u.ID,
+		ExpiresAt: time.Unix(claims.ExpiresAt, 0),
+	}, nil
+}
+
+// revokeFamily revoke every refresh token issued from the same login
+func (s DefaultTokenService) revokeFamily(token *domain.RefreshToken) *errs.AppError {
+	logger.Info("Refresh token reuse detected, revoking token family")
+	if appErr := s.refreshTokenRepo.RevokeRefreshTokenFamily(token.FamilyID); appErr != nil {
+		return appErr
+	}
+
+	return errs.NewAuthenticationError("refresh token reuse detected")
+}
+
+// newRefreshToken create an opaque refresh token and its record to be stored
+func newRefreshToken(userID uint, familyID string) (string, *domain.RefreshToken, *errs.AppError) {
+	refreshToken, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		logger.Error(err.Error())
+		return "", nil, errs.NewUnexpectedError("unexpected error while creating token")
+	}
+
+	return refreshToken, &domain.RefreshToken{
+		UserID:    userID,
+		FamilyID:  familyID,
+		TokenHash: utils.HashToken(refreshToken),
+		ExpiresAt: time.Now().Add(utils.GetEnvDuration("REFRESH_TOKEN_TTL", time.Hour*24*30)),
+	}, nil
+}
+
+// createLoginResponse create the access token for the user, tied to the session
+func createLoginResponse(u *domain.User, refreshToken string, sessionID uint) (*responses.LoginResponse, *errs.AppError) {
+	// create claim
+	jwtClaims := u.ToNewUtilsJWTClaims()
+	jwtClaims.SessionID = sessionID
+
+	var accessToken string
+	var err error
+	// create token
+	if accessToken, err = jwtClaims.CreateToken(); err != nil {
+		logger.Error(err.Error())
+		return nil, errs.NewUnexpectedError("unexpected error while creating token")
+	}
+
+	return &responses.LoginResponse{Token: accessToken, RefreshToken: refreshToken}, nil
+}
@@ -0,0 +1,296 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	realDomain "github.com/karlbehrensg/go-fiber-template/internal/domain"
+	"github.com/karlbehrensg/go-fiber-template/internal/http/requests"
+	"github.com/karlbehrensg/go-fiber-template/mocks/domain"
+	"github.com/karlbehrensg/go-fiber-template/pkg/errs"
+	"github.com/karlbehrensg/go-fiber-template/pkg/utils"
+)
+
+var mockUserRepo *domain.MockUserRepository
+var mockRefreshTokenRepo *domain.MockRefreshTokenRepository
+var mockRevokedTokenRepo *domain.MockRevokedTokenRepository
+var mockSessionRepo *domain.MockSessionRepository
+var tokenService TokenService
+
+func tokenSetup(t *testing.T) func() {
+	ctrl := gomock.NewController(t)
+	mockUserRepo = domain.NewMockUserRepository(ctrl)
+	mockRefreshTokenRepo = domain.NewMockRefreshTokenRepository(ctrl)
+	mockRevokedTokenRepo = domain.NewMockRevokedTokenRepository(ctrl)
+	mockSessionRepo = domain.NewMockSessionRepository(ctrl)
+	tokenService = NewTokenService(mockUserRepo, mockRefreshTokenRepo, mockRevokedTokenRepo, mockSessionRepo, nil)
+	return func() {
+		tokenService = nil
+		defer ctrl.Finish()
+	}
+}
+
+func Test_should_return_access_and_refresh_token_when_tokens_are_issued(t *testing.T) {
+	// Arrange
+	teardown := tokenSetup(t)
+	defer teardown()
+
+	u := &realDomain.User{ID: 1, Email: "edwyn.rangel.externo@zeleri.com"}
+
+	mockSessionRepo.EXPECT().SaveSession(gomock.Any()).Return(nil)
+	mockRefreshTokenRepo.EXPECT().SaveRefreshToken(gomock.Any()).Return(nil)
+	// Act
+	response, appError := tokenService.IssueTokens(u, realDomain.ClientInfo{IP: "10.0.0.1", UserAgent: "curl/7.85.0"})
+
+	// Assert
+	if appError != nil {
+		t.Error("Test failed while issuing tokens")
+	}
+	if response.Token == "" || response.RefreshToken == "" {
+		t.Error("Failed while creating access and refresh token")
+	}
+}
+
+func Test_should_rotate_refresh_token_when_it_is_valid(t *testing.T) {
+	// Arrange
+	teardown := tokenSetup(t)
+	defer teardown()
+
+	current := &realDomain.RefreshToken{
+		ID: 1, UserID: 1, FamilyID: "family", ExpiresAt: time.Now().Add(time.Hour),
+	}
+	u := &realDomain.User{ID: 1, Email: "edwyn.rangel.externo@zeleri.com"}
+
+	mockRefreshTokenRepo.EXPECT().FindRefreshTokenByHash(utils.HashToken("refresh")).Return(current, nil)
+	mockUserRepo.EXPECT().FindUserById(uint(1)).Return(u, nil)
+	mockSessionRepo.EXPECT().FindSessionByFamily("family").Return(&realDomain.Session{ID: 3, UserID: 1, FamilyID: "family"}, nil)
+	mockRefreshTokenRepo.EXPECT().RotateRefreshToken(current, gomock.Any()).Return(nil)
+	mockSessionRepo.EXPECT().TouchSession(uint(3), "10.0.0.1", gomock.Any()).Return(nil)
+	// Act
+	response, appError := tokenService.RefreshTokens(requests.RefreshTokenRequest{RefreshToken: "refresh", IP: "10.0.0.1"})
+
+	// Assert
+	if appError != nil {
+		t.Error("Test failed while rotating refresh token")
+	}
+	if response.RefreshToken == "refresh" {
+		t.Error("Failed while rotating refresh token")
+	}
+}
+
+func Test_should_revoke_token_family_when_a_rotated_refresh_token_is_reused(t *testing.T) {
+	// Arrange
+	teardown := tokenSetup(t)
+	defer teardown()
+
+	rotatedAt := time.Now()
+	current := &realDomain.RefreshToken{
+		ID: 1, UserID: 1, FamilyID: "family", ExpiresAt: time.Now().Add(time.Hour), RotatedAt: &rotatedAt,
+	}
+
+	mockRefreshTokenRepo.EXPECT().FindRefreshTokenByHash(utils.HashToken("refresh")).Return(current, nil)
+	mockRefreshTokenRepo.EXPECT().RevokeRefreshTokenFamily("family").Return(nil)
+	// Act
+	_, appError := tokenService.RefreshTokens(requests.RefreshTokenRequest{RefreshToken: "refresh"})
+
+	// Assert
+	if appError == nil || appError.Message != "refresh token reuse detected" {
+		t.Error("Test failed while validating refresh token reuse")
+	}
+}
+
+func Test_should_revoke_token_family_when_a_concurrent_rotation_wins(t *testing.T) {
+	// Arrange
+	teardown := tokenSetup(t)
+	defer teardown()
+
+	current := &realDomain.RefreshToken{
+		ID: 1, UserID: 1, FamilyID: "family", ExpiresAt: time.Now().Add(time.Hour),
+	}
+	u := &realDomain.User{ID: 1}
+
+	mockRefreshTokenRepo.EXPECT().FindRefreshTokenByHash(gomock.Any()).Return(current, nil)
+	mockUserRepo.EXPECT().FindUserById(uint(1)).Return(u, nil)
+	mockSessionRepo.EXPECT().FindSessionByFamily("family").Return(&realDomain.Session{ID: 3, UserID: 1, FamilyID: "family"}, nil)
+	mockRefreshTokenRepo.EXPECT().RotateRefreshToken(current, gomock.Any()).Return(errs.NewAuthenticationError("refresh token reuse detected"))
+	mockRefreshTokenRepo.EXPECT().RevokeRefreshTokenFamily("family").Return(nil)
+	// Act
+	_, appError := tokenService.RefreshTokens(requests.RefreshTokenRequest{RefreshToken: "refresh"})
+
+	// Assert
+	if appError == nil {
+		t.Error("Test failed while validating concurrent refresh token reuse")
+	}
+}
+
+func Test_should_return_an_error_when_refresh_token_is_expired(t *testing.T) {
+	// Arrange
+	teardown := tokenSetup(t)
+	defer teardown()
+
+	current := &realDomain.RefreshToken{
+		ID: 1, UserID: 1, FamilyID: "family", ExpiresAt: time.Now().Add(-time.Minute),
+	}
+
+	mockRefreshTokenRepo.EXPECT().FindRefreshTokenByHash(gomock.Any()).Return(current, nil)
+	// Act
+	_, appError := tokenService.RefreshTokens(requests.RefreshTokenRequest{RefreshToken: "refresh"})
+
+	// Assert
+	if appError == nil || appError.Message != "refresh token expired" {
+		t.Error("Test failed while validating expired refresh token")
+	}
+}
+
+func Test_should_revoke_access_token_and_refresh_token_family_when_logout(t *testing.T) {
+	// Arrange
+	teardown := tokenSetup(t)
+	defer teardown()
+
+	claims := &utils.JWTClaims{UserID: 1}
+	claims.CreateToken()
+	current := &realDomain.RefreshToken{ID: 1, UserID: 1, FamilyID: "family"}
+
+	// the revocation outlives exp by the leeway the token is still accepted for
+	mockRevokedTokenRepo.EXPECT().SaveRevokedToken(&realDomain.RevokedToken{
+		JTI:       claims.Id,
+		ExpiresAt: time.Unix(claims.ExpiresAt, 0).Add(30 * time.Second),
+	}).Return(nil)
+	mockRefreshTokenRepo.EXPECT().FindRefreshTokenByHash(utils.HashToken("refresh")).Return(current, nil)
+	mockRefreshTokenRepo.EXPECT().RevokeRefreshTokenFamily("family").Return(nil)
+	// Act
+	appError := tokenService.RevokeTokens(claims, requests.LogoutRequest{RefreshToken: "refresh"})
+
+	// Assert
+	if appError != nil {
+		t.Error("Test failed while revoking tokens")
+	}
+}
+
+func Test_should_not_revoke_refresh_token_family_of_another_user_when_logout(t *testing.T) {
+	// Arrange
+	teardown := tokenSetup(t)
+	defer teardown()
+
+	claims := &utils.JWTClaims{UserID: 1}
+	claims.CreateToken()
+	current := &realDomain.RefreshToken{ID: 1, UserID: 2, FamilyID: "family"}
+
+	mockRevokedTokenRepo.EXPECT().SaveRevokedToken(gomock.Any()).Return(nil)
+	mockRefreshTokenRepo.EXPECT().FindRefreshTokenByHash(gomock.Any()).Return(current, nil)
+	// Act
+	appError := tokenService.RevokeTokens(claims, requests.LogoutRequest{RefreshToken: "refresh"})
+
+	// Assert
+	if appError != nil {
+		t.Error("Test failed while revoking tokens")
+	}
+}
+
+func Test_should_return_an_error_when_session_of_refresh_token_was_signed_out(t *testing.T) {
+	// Arrange
+	teardown := tokenSetup(t)
+	defer teardown()
+
+	current := &realDomain.RefreshToken{
+		ID: 1, UserID: 1, FamilyID: "family", ExpiresAt: time.Now().Add(time.Hour),
+	}
+	revokedAt := time.Now()
+
+	mockRefreshTokenRepo.EXPECT().FindRefreshTokenByHash(gomock.Any()).Return(current, nil)
+	mockUserRepo.EXPECT().FindUserById(uint(1)).Return(&realDomain.User{ID: 1}, nil)
+	mockSessionRepo.EXPECT().FindSessionByFamily("family").Return(&realDomain.Session{ID: 3, UserID: 1, RevokedAt: &revokedAt}, nil)
+	// Act
+	_, appError := tokenService.RefreshTokens(requests.RefreshTokenRequest{RefreshToken: "refresh"})
+
+	// Assert
+	if appError == nil || appError.Code != 401 {
+		t.Error("Test failed while validating signed out session")
+	}
+}
+
+func Test_should_tie_access_token_to_the_new_session(t *testing.T) {
+	// Arrange
+	teardown := tokenSetup(t)
+	defer teardown()
+
+	u := &realDomain.User{ID: 1, Email: "edwyn.rangel.externo@zeleri.com"}
+
+	mockSessionRepo.EXPECT().SaveSession(gomock.Any()).DoAndReturn(func(session *realDomain.Session) *errs.AppError {
+		if session.IP != "10.0.0.1" || session.UserAgent != "curl/7.85.0" || session.FamilyID == "" {
+			t.Error("Failed while saving session")
+		}
+		session.ID = 7
+		return nil
+	})
+	mockRefreshTokenRepo.EXPECT().SaveRefreshToken(gomock.Any()).Return(nil)
+	// Act
+	response, _ := tokenService.IssueTokens(u, realDomain.ClientInfo{IP: "10.0.0.1", UserAgent: "curl/7.85.0"})
+
+	// Assert
+	claims := &utils.JWTClaims{}
+	if err := claims.ValidateToken(response.Token); err != nil || claims.SessionID != 7 {
+		t.Error("Test failed while tying access token to session")
+	}
+}
+
+func Test_should_mint_short_lived_token_with_actor_claim_when_admin_impersonates(t *testing.T) {
+	// Arrange
+	teardown := tokenSetup(t)
+	defer teardown()
+
+	t.Setenv("JWT_IMPERSONATION_TTL", "5m")
+	admin := realDomain.Actor{UserID: 1, Email: "admin@example.com"}
+	u := &realDomain.User{ID: 2, Email: "edwyn@example.com", Role: realDomain.RoleReader}
+
+	mockUserRepo.EXPECT().FindUserById(uint(2)).Return(u, nil)
+	// Act
+	response, appError := tokenService.Impersonate(admin, 2)
+
+	// Assert
+	if appError != nil {
+		t.Fatal("Test failed while impersonating user")
+	}
+	claims := &utils.JWTClaims{}
+	if err := claims.ValidateToken(response.Token); err != nil {
+		t.Fatalf("Failed while validating impersonation token: %s", err.Error())
+	}
+	if claims.UserID != 2 || !claims.IsImpersonated() || claims.Actor.UserID != 1 {
+		t.Errorf("Failed while matching impersonation claims: %+v", claims)
+	}
+	if ttl := time.Until(response.ExpiresAt); ttl > time.Minute*5 || ttl < time.Minute*4 {
+		t.Errorf("Failed while matching impersonation TTL: %s", ttl)
+	}
+}
+
+func Test_should_return_status_403_when_impersonating_an_admin(t *testing.T) {
+	// Arrange
+	teardown := tokenSetup(t)
+	defer teardown()
+
+	admin := realDomain.Actor{UserID: 1, Email: "admin@example.com"}
+
+	mockUserRepo.EXPECT().FindUserById(uint(2)).Return(&realDomain.User{ID: 2, Role: realDomain.RoleAdmin}, nil)
+	// Act
+	_, appError := tokenService.Impersonate(admin, 2)
+
+	// Assert
+	if appError == nil || appError.Code != 403 {
+		t.Error("Test failed while validating admin impersonation")
+	}
+}
+
+func Test_should_return_status_403_when_impersonating_while_impersonating(t *testing.T) {
+	// Arrange
+	teardown := tokenSetup(t)
+	defer teardown()
+
+	// Act
+	_, appError := tokenService.Impersonate(realDomain.Actor{UserID: 2, ImpersonatorID: 1}, 3)
+
+	// Assert
+	if appError == nil || appError.Code != 403 {
+		t.Error("Test failed while validating nested impersonation")
+	}
+}
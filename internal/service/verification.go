@@ -0,0 +1,119 @@
+package service
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/karlbehrensg/go-fiber-template/internal/domain"
+	"github.com/karlbehrensg/go-fiber-template/internal/http/requests"
+	"github.com/karlbehrensg/go-fiber-template/pkg/errs"
+	"github.com/karlbehrensg/go-fiber-template/pkg/logger"
+	"github.com/karlbehrensg/go-fiber-template/pkg/utils"
+)
+
+// VerificationService port primary
+type VerificationService interface {
+	VerifyEmail(string) *errs.AppError
+	ResendVerification(requests.ResendVerificationRequest) *errs.AppError
+}
+
+type DefaultVerificationService struct {
+	repo   domain.UserRepository
+	mailer domain.Mailer
+}
+
+// NewVerificationService create a new instance of DefaultVerificationService
+func NewVerificationService(repository domain.UserRepository, mailer domain.Mailer) DefaultVerificationService {
+	return DefaultVerificationService{repository, mailer}
+}
+
+// VerifyEmail use case for confirm the email address with the signed link
+func (s DefaultVerificationService) VerifyEmail(token string) *errs.AppError {
+	payload, err := utils.ParseSignedToken(utils.SignedTokenEmailVerification, token, verificationSecret())
+	if err != nil {
+		logger.Error(err.Error())
+		return errs.NewBadRequestError("invalid or expired verification link")
+	}
+
+	// payload is "<user id>:<email>", a link stops working when the email changes
+	parts := strings.SplitN(payload, ":", 2)
+	if len(parts) != 2 {
+		return errs.NewBadRequestError("invalid or expired verification link")
+	}
+	id, err := strconv.ParseUint(parts[0], 10, 64)
+	if err != nil {
+		return errs.NewBadRequestError("invalid or expired verification link")
+	}
+
+	var u *domain.User
+	var appErr *errs.AppError
+	if u, appErr = s.repo.FindUserById(uint(id)); appErr != nil {
+		if strings.Contains(appErr.Message, "record not found") {
+			return errs.NewBadRequestError("invalid or expired verification link")
+		}
+		return appErr
+	}
+
+	if u.Email != parts[1] {
+		return errs.NewBadRequestError("invalid or expired verification link")
+	}
+
+	if u.IsVerified() {
+		return nil
+	}
+
+	return s.repo.UpdateUserColumns(u.ID, map[string]interface{}{"verified_at": time.Now()})
+}
+
+// ResendVerification use case for send again the verification link, throttled per account
+func (s DefaultVerificationService) ResendVerification(request requests.ResendVerificationRequest) *errs.AppError {
+	u, appErr := s.repo.FindUserByEmail(request.Email)
+	if appErr != nil {
+		if strings.Contains(appErr.Message, "record not found") {
+			return nil
+		}
+		return appErr
+	}
+
+	if u.IsVerified() {
+		return nil
+	}
+
+	interval := utils.GetEnvDuration("EMAIL_VERIFICATION_RESEND_INTERVAL", time.Minute)
+	if u.VerificationSentAt != nil && time.Since(*u.VerificationSentAt) < interval {
+		return errs.NewTooManyRequestsError("verification email was sent recently, try again later")
+	}
+
+	return sendVerificationMail(s.repo, s.mailer, u)
+}
+
+// sendVerificationMail send the signed verification link and record when it was sent
+func sendVerificationMail(repository domain.UserRepository, mailer domain.Mailer, u *domain.User) *errs.AppError {
+	ttl := utils.GetEnvDuration("EMAIL_VERIFICATION_TTL", time.Hour*24)
+	token := utils.CreateSignedToken(utils.SignedTokenEmailVerification, fmt.Sprintf("%d:%s", u.ID, u.Email), ttl, verificationSecret())
+
+	if appErr := mailer.Send(domain.Mail{
+		To:      u.Email,
+		Subject: "Verify your email",
+		Body: fmt.Sprintf(
+			"Use the following link to verify your email, it expires in %s:\n%s?token=%s",
+			ttl, os.Getenv("EMAIL_VERIFICATION_URL"), token,
+		),
+	}); appErr != nil {
+		return appErr
+	}
+
+	return repository.UpdateUserColumns(u.ID, map[string]interface{}{"verification_sent_at": time.Now()})
+}
+
+// verificationSecret secret used to sign verification links
+func verificationSecret() string {
+	if secret := os.Getenv("EMAIL_VERIFICATION_SECRET"); secret != "" {
+		return secret
+	}
+
+	return os.Getenv("JWT_SECRET")
+}
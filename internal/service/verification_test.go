@@ -0,0 +1,80 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	realDomain "github.com/karlbehrensg/go-fiber-template/internal/domain"
+	"github.com/karlbehrensg/go-fiber-template/internal/http/requests"
+	"github.com/karlbehrensg/go-fiber-template/mocks/domain"
+	"github.com/karlbehrensg/go-fiber-template/pkg/utils"
+)
+
+var verificationService VerificationService
+
+func verificationSetup(t *testing.T) func() {
+	ctrl := gomock.NewController(t)
+	mockUserRepo = domain.NewMockUserRepository(ctrl)
+	mockMailer = domain.NewMockMailer(ctrl)
+	verificationService = NewVerificationService(mockUserRepo, mockMailer)
+	return func() {
+		verificationService = nil
+		defer ctrl.Finish()
+	}
+}
+
+func Test_should_mark_user_as_verified_when_link_is_valid(t *testing.T) {
+	// Arrange
+	teardown := verificationSetup(t)
+	defer teardown()
+
+	u := &realDomain.User{ID: 1, Email: "edwyn.rangel.externo@zeleri.com"}
+	token := utils.CreateSignedToken(utils.SignedTokenEmailVerification, "1:edwyn.rangel.externo@zeleri.com", time.Minute, verificationSecret())
+
+	mockUserRepo.EXPECT().FindUserById(uint(1)).Return(u, nil)
+	mockUserRepo.EXPECT().UpdateUserColumns(uint(1), gomock.Any()).Return(nil)
+	// Act
+	appError := verificationService.VerifyEmail(token)
+
+	// Assert
+	if appError != nil {
+		t.Error("Test failed while verifying email")
+	}
+}
+
+func Test_should_return_an_error_when_link_belongs_to_a_previous_email(t *testing.T) {
+	// Arrange
+	teardown := verificationSetup(t)
+	defer teardown()
+
+	u := &realDomain.User{ID: 1, Email: "new@example.com"}
+	token := utils.CreateSignedToken(utils.SignedTokenEmailVerification, "1:old@example.com", time.Minute, verificationSecret())
+
+	mockUserRepo.EXPECT().FindUserById(uint(1)).Return(u, nil)
+	// Act
+	appError := verificationService.VerifyEmail(token)
+
+	// Assert
+	if appError == nil {
+		t.Error("Test failed while validating verification link email")
+	}
+}
+
+func Test_should_throttle_resend_verification(t *testing.T) {
+	// Arrange
+	teardown := verificationSetup(t)
+	defer teardown()
+
+	sentAt := time.Now()
+	u := &realDomain.User{ID: 1, Email: "edwyn.rangel.externo@zeleri.com", VerificationSentAt: &sentAt}
+
+	mockUserRepo.EXPECT().FindUserByEmail(u.Email).Return(u, nil)
+	// Act
+	appError := verificationService.ResendVerification(requests.ResendVerificationRequest{Email: u.Email})
+
+	// Assert
+	if appError == nil || appError.Code != 429 {
+		t.Error("Test failed while throttling verification email")
+	}
+}
@@ -61,3 +61,35 @@ func NewAuthorizationError(message string) *AppError {
 		Code:    http.StatusForbidden,
 	}
 }
+
+// NewUnverifiedError return error for accounts with the email pending of verification
+func NewUnverifiedError(message string) *AppError {
+	return &AppError{
+		Message: message,
+		Code:    http.StatusForbidden,
+	}
+}
+
+// NewTooManyRequestsError return error for throttled requests
+func NewTooManyRequestsError(message string) *AppError {
+	return &AppError{
+		Message: message,
+		Code:    http.StatusTooManyRequests,
+	}
+}
+
+// NewLockedError return error for temporarily locked resources
+func NewLockedError(message string) *AppError {
+	return &AppError{
+		Message: message,
+		Code:    http.StatusLocked,
+	}
+}
+
+// NewConflictError return error for requests conflicting with the current state of the resource
+func NewConflictError(message string) *AppError {
+	return &AppError{
+		Message: message,
+		Code:    http.StatusConflict,
+	}
+}
@@ -0,0 +1,126 @@
+package utils
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// NormalizeISBN remove the hyphens and spaces of the ISBN, the check digit X is upper case
+func NormalizeISBN(isbn string) string {
+	return strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(isbn))
+}
+
+// IsValidISBN validate the checksum of an ISBN-10 or ISBN-13, hyphens and spaces are ignored
+func IsValidISBN(isbn string) bool {
+	isbn = NormalizeISBN(isbn)
+	switch len(isbn) {
+	case 10:
+		sum, ok := isbn10Sum(isbn[:9])
+		if !ok {
+			return false
+		}
+		check, ok := isbn10Digit(isbn[9], true)
+		return ok && (sum+check)%11 == 0
+	case 13:
+		// only the prefixes 978 and 979 are assigned to books
+		if !strings.HasPrefix(isbn, "978") && !strings.HasPrefix(isbn, "979") {
+			return false
+		}
+		sum, ok := isbn13Sum(isbn[:12])
+		if !ok || !isDigit(isbn[12]) {
+			return false
+		}
+		return (sum+int(isbn[12]-'0'))%10 == 0
+	}
+
+	return false
+}
+
+// ISBN13 normalize the ISBN, an ISBN-10 is converted to ISBN-13 with the prefix 978
+func ISBN13(isbn string) (string, error) {
+	if !IsValidISBN(isbn) {
+		return "", errors.New("invalid ISBN")
+	}
+
+	isbn = NormalizeISBN(isbn)
+	if len(isbn) == 13 {
+		return isbn, nil
+	}
+
+	isbn = "978" + isbn[:9]
+	sum, _ := isbn13Sum(isbn)
+	return isbn + string(rune('0'+(10-sum%10)%10)), nil
+}
+
+// ISBN10 convert an ISBN-13 to ISBN-10, only the prefix 978 has ISBN-10
+func ISBN10(isbn13 string) (string, bool) {
+	isbn13 = NormalizeISBN(isbn13)
+	if len(isbn13) != 13 || !strings.HasPrefix(isbn13, "978") || !IsValidISBN(isbn13) {
+		return "", false
+	}
+
+	isbn := isbn13[3:12]
+	sum, _ := isbn10Sum(isbn)
+	check := (11 - sum%11) % 11
+	if check == 10 {
+		return isbn + "X", true
+	}
+
+	return isbn + string(rune('0'+check)), true
+}
+
+// validateISBN validator of the tag isbn, it replaces the built-in one to accept hyphens and check the checksum,
+// an empty isbn is accepted to clear the stored one
+func validateISBN(fl validator.FieldLevel) bool {
+	isbn := fl.Field().String()
+	return isbn == "" || IsValidISBN(isbn)
+}
+
+// isbn10Sum weighted sum of the first nine digits, weights from 10 to 2
+func isbn10Sum(digits string) (int, bool) {
+	sum := 0
+	for i := 0; i < len(digits); i++ {
+		d, ok := isbn10Digit(digits[i], false)
+		if !ok {
+			return 0, false
+		}
+		sum += (10 - i) * d
+	}
+
+	return sum, true
+}
+
+// isbn13Sum weighted sum of the first twelve digits, weights 1 and 3 alternated
+func isbn13Sum(digits string) (int, bool) {
+	sum := 0
+	for i := 0; i < len(digits); i++ {
+		if !isDigit(digits[i]) {
+			return 0, false
+		}
+		weight := 1
+		if i%2 == 1 {
+			weight = 3
+		}
+		sum += weight * int(digits[i]-'0')
+	}
+
+	return sum, true
+}
+
+// isbn10Digit value of a digit of an ISBN-10, X is 10 and only valid as check digit
+func isbn10Digit(c byte, check bool) (int, bool) {
+	if check && c == 'X' {
+		return 10, true
+	}
+	if !isDigit(c) {
+		return 0, false
+	}
+
+	return int(c - '0'), true
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
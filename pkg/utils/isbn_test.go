@@ -0,0 +1,61 @@
+package utils
+
+import "testing"
+
+func Test_should_validate_the_checksum_of_isbn(t *testing.T) {
+	valid := []string{"0-306-40615-2", "0306406152", "080442957x", "978-0-306-40615-7", "979 10 90636 07 1"}
+	invalid := []string{"0-306-40615-3", "978-0-306-40615-8", "X306406152", "97803064061", "abcdefghij", "", "977-0-306-40615-8", "9790306406150"}
+
+	for _, isbn := range valid {
+		if !IsValidISBN(isbn) {
+			t.Errorf("Failed while validating %s", isbn)
+		}
+	}
+	for _, isbn := range invalid {
+		if IsValidISBN(isbn) {
+			t.Errorf("Failed while rejecting %s", isbn)
+		}
+	}
+}
+
+func Test_should_convert_isbn_10_to_isbn_13_and_back(t *testing.T) {
+	cases := map[string]string{"0-306-40615-2": "9780306406157", "0-8044-2957-X": "9780804429573"}
+
+	for isbn10, expected := range cases {
+		isbn13, err := ISBN13(isbn10)
+		if err != nil || isbn13 != expected {
+			t.Errorf("Failed while converting %s: %s", isbn10, isbn13)
+		}
+		if back, ok := ISBN10(isbn13); !ok || back != NormalizeISBN(isbn10) {
+			t.Errorf("Failed while converting back %s: %s", isbn13, back)
+		}
+	}
+
+	// only the prefix 978 has ISBN-10
+	if _, ok := ISBN10("979-10-90636-07-1"); ok {
+		t.Error("Failed while converting ISBN-13 with prefix 979")
+	}
+}
+
+func Test_should_register_the_isbn_validator(t *testing.T) {
+	type request struct {
+		ISBN *string `validate:"omitempty,isbn"`
+	}
+	isbn := func(value string) *string { return &value }
+
+	if err := GetValidator().Struct(request{ISBN: isbn("978-0-306-40615-7")}); err != nil {
+		t.Errorf("Failed while validating hyphenated ISBN: %s", err.Error())
+	}
+	if err := GetValidator().Struct(request{ISBN: isbn("978-0-306-40615-8")}); err == nil {
+		t.Error("Failed while rejecting ISBN with a wrong checksum")
+	}
+	if err := GetValidator().Struct(request{ISBN: isbn("977-0-306-40615-8")}); err == nil {
+		t.Error("Failed while rejecting ISBN-13 without the prefix 978 or 979")
+	}
+	// an empty ISBN clears the stored one and a missing ISBN keeps it
+	for _, value := range []*string{isbn(""), nil} {
+		if err := GetValidator().Struct(request{ISBN: value}); err != nil {
+			t.Errorf("Failed while validating an empty ISBN: %s", err.Error())
+		}
+	}
+}
@@ -1,39 +1,177 @@
 package utils
 
 import (
+	"errors"
+	"fmt"
 	"os"
 	"time"
 
 	"github.com/golang-jwt/jwt"
 )
 
+var (
+	ErrTokenMissingClaims  = errors.New("token is missing required claims")
+	ErrTokenExpired        = errors.New("token has expired")
+	ErrTokenNotValidYet    = errors.New("token is not valid yet")
+	ErrTokenIssuedInFuture = errors.New("token was issued in the future")
+	ErrInvalidIssuer       = errors.New("invalid token issuer")
+	ErrInvalidAudience     = errors.New("invalid token audience")
+)
+
 type JWTClaims struct {
 	*jwt.StandardClaims
 
+	UserID       uint   `json:"user_id"`
+	Email        string `json:"email"`
+	Name         string `json:"name"`
+	Role         string `json:"role"`
+	TokenVersion uint   `json:"ver"`
+	SessionID    uint   `json:"sid,omitempty"`
+	// Actor admin acting as the user, only set in impersonation tokens
+	Actor *JWTActor `json:"act,omitempty"`
+
+	// APIKeyID and Scopes are only set when authenticated with an API key
+	APIKeyID uint     `json:"-"`
+	Scopes   []string `json:"-"`
+}
+
+// JWTActor admin behind an impersonation token
+type JWTActor struct {
 	UserID uint   `json:"user_id"`
 	Email  string `json:"email"`
-	Name   string `json:"name"`
+}
+
+// IsImpersonated validate if the token was minted for an admin acting as the user
+func (c *JWTClaims) IsImpersonated() bool {
+	return c.Actor != nil
+}
+
+// HasScope validate if the credential grants the scope, tokens and unrestricted API keys grant every scope
+func (c *JWTClaims) HasScope(scope string) bool {
+	if c.APIKeyID == 0 || len(c.Scopes) == 0 {
+		return true
+	}
+
+	for _, s := range c.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+
+	return false
 }
 
 // CreateToken create token
 func (c *JWTClaims) CreateToken() (string, error) {
+	// unique identifier used to revoke the token
+	jti, err := GenerateRandomToken(16)
+	if err != nil {
+		return "", err
+	}
+
+	// impersonation tokens are short-lived
+	ttl := GetEnvDuration("JWT_ACCESS_TOKEN_TTL", time.Minute*15)
+	if c.IsImpersonated() {
+		ttl = GetEnvDuration("JWT_IMPERSONATION_TTL", time.Minute*10)
+	}
+
+	now := time.Now()
 	c.StandardClaims = &jwt.StandardClaims{
-		ExpiresAt: time.Now().Add(time.Minute * 15).Unix(),
+		Id:        jti,
+		Issuer:    jwtIssuer(),
+		Audience:  jwtAudience(),
+		IssuedAt:  now.Unix(),
+		NotBefore: now.Unix(),
+		ExpiresAt: now.Add(ttl).Unix(),
+	}
+
+	key := GetKeyRing().SigningKey()
+	if !key.CanSign() {
+		return "", fmt.Errorf("JWT key %s can not sign tokens", key.ID)
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, c)
-	return token.SignedString([]byte(os.Getenv("JWT_SECRET")))
+	token := jwt.NewWithClaims(key.Method, c)
+	token.Header["kid"] = key.ID
+	return token.SignedString(key.Private)
 }
 
 // ValidateToken validate token
 func (c *JWTClaims) ValidateToken(tokenString string) error {
-	token, err := jwt.ParseWithClaims(tokenString, c, func(token *jwt.Token) (interface{}, error) {
-		return []byte(os.Getenv("JWT_SECRET")), nil
+	// claims are validated below with the configured leeway
+	parser := &jwt.Parser{SkipClaimsValidation: true}
+	token, err := parser.ParseWithClaims(tokenString, c, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		key, ok := GetKeyRing().Key(kid)
+		if !ok {
+			return nil, fmt.Errorf("unknown JWT key %s", kid)
+		}
+		// only the algorithm of the selected key is accepted to avoid downgrades
+		if token.Method.Alg() != key.Method.Alg() {
+			return nil, fmt.Errorf("unexpected JWT algorithm %s", token.Method.Alg())
+		}
+		return key.Public, nil
 	})
 
 	if err != nil || !token.Valid {
 		return err
 	}
 
+	return c.validateClaims()
+}
+
+// AcceptedUntil last moment the token is accepted, its expiry plus the allowed clock skew,
+// a revoked token must stay revoked until then
+func (c *JWTClaims) AcceptedUntil() time.Time {
+	return time.Unix(c.ExpiresAt, 0).Add(jwtLeeway())
+}
+
+// validateClaims validate the time based claims, issuer and audience
+func (c *JWTClaims) validateClaims() error {
+	if c.StandardClaims == nil || c.ExpiresAt == 0 || c.IssuedAt == 0 {
+		return ErrTokenMissingClaims
+	}
+
+	now := time.Now()
+	leeway := jwtLeeway()
+
+	if now.After(c.AcceptedUntil()) {
+		return ErrTokenExpired
+	}
+	if c.NotBefore != 0 && now.Add(leeway).Before(time.Unix(c.NotBefore, 0)) {
+		return ErrTokenNotValidYet
+	}
+	if now.Add(leeway).Before(time.Unix(c.IssuedAt, 0)) {
+		return ErrTokenIssuedInFuture
+	}
+	if c.Issuer != jwtIssuer() {
+		return ErrInvalidIssuer
+	}
+	if c.Audience != jwtAudience() {
+		return ErrInvalidAudience
+	}
+
 	return nil
 }
+
+// jwtIssuer issuer emitted and expected in every token
+func jwtIssuer() string {
+	if issuer := os.Getenv("JWT_ISSUER"); issuer != "" {
+		return issuer
+	}
+
+	return "go-fiber-template"
+}
+
+// jwtAudience audience emitted and expected in every token
+func jwtAudience() string {
+	if audience := os.Getenv("JWT_AUDIENCE"); audience != "" {
+		return audience
+	}
+
+	return "go-fiber-template"
+}
+
+// jwtLeeway allowed clock skew when validating exp, nbf and iat
+func jwtLeeway() time.Duration {
+	return GetEnvDuration("JWT_LEEWAY", time.Second*30)
+}
@@ -0,0 +1,166 @@
+package utils
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+)
+
+// writePrivateKey write the key in PKCS8 PEM format and return its path
+func writePrivateKey(t *testing.T, key interface{}) string {
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(t.TempDir(), "private.pem")
+	if err = os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+// useSigningKey replace the keyring for the duration of the test
+func useSigningKey(t *testing.T, key *SigningKey, verificationKeys ...*SigningKey) {
+	keyRing = NewKeyRing(key, verificationKeys...)
+	t.Cleanup(func() { keyRing = nil })
+}
+
+func Test_should_validate_token_when_it_is_signed_with_rs256(t *testing.T) {
+	// Arrange
+	private, _ := rsa.GenerateKey(rand.Reader, 2048)
+	key, err := LoadSigningKey("RS256", "", writePrivateKey(t, private), "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	useSigningKey(t, key)
+	token, _ := (&JWTClaims{UserID: 1}).CreateToken()
+
+	// Act
+	claims := &JWTClaims{}
+	err = claims.ValidateToken(token)
+
+	// Assert
+	if err != nil || claims.UserID != 1 {
+		t.Error("Test failed while validating RS256 token")
+	}
+}
+
+func Test_should_validate_token_when_it_is_signed_with_eddsa(t *testing.T) {
+	// Arrange
+	_, private, _ := ed25519.GenerateKey(rand.Reader)
+	key, err := LoadSigningKey("EdDSA", "ed-key", writePrivateKey(t, private), "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	useSigningKey(t, key)
+	token, _ := (&JWTClaims{UserID: 1}).CreateToken()
+
+	// Act
+	claims := &JWTClaims{}
+	err = claims.ValidateToken(token)
+	parsed, _, _ := new(jwt.Parser).ParseUnverified(token, &JWTClaims{})
+
+	// Assert
+	if err != nil || parsed.Header["kid"] != "ed-key" {
+		t.Error("Test failed while validating EdDSA token")
+	}
+}
+
+func Test_should_return_an_error_when_token_algorithm_was_downgraded(t *testing.T) {
+	// Arrange
+	private, _ := rsa.GenerateKey(rand.Reader, 2048)
+	key, _ := LoadSigningKey("RS256", "rsa-key", writePrivateKey(t, private), "", "")
+	useSigningKey(t, key)
+
+	// HS256 token signed with the public key as secret
+	publicDer, _ := x509.MarshalPKIXPublicKey(&private.PublicKey)
+	forged := jwt.NewWithClaims(jwt.SigningMethodHS256, &JWTClaims{StandardClaims: &jwt.StandardClaims{}, UserID: 1})
+	forged.Header["kid"] = "rsa-key"
+	token, _ := forged.SignedString(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDer}))
+
+	// Act
+	err := (&JWTClaims{}).ValidateToken(token)
+
+	// Assert
+	if err == nil {
+		t.Error("Test failed while validating token algorithm")
+	}
+}
+
+func Test_should_publish_only_asymmetric_keys_in_jwks(t *testing.T) {
+	// Arrange
+	useSigningKey(t, &SigningKey{ID: "default", Method: jwt.SigningMethodHS256, Private: []byte("secret"), Public: []byte("secret")})
+
+	// Act
+	keys := GetKeyRing().PublicJWKs()
+
+	// Assert
+	if len(keys) != 0 {
+		t.Error("Test failed while publishing HS256 key")
+	}
+}
+
+// signClaims sign the claims with a HS256 test key
+func signClaims(t *testing.T, claims *jwt.StandardClaims) string {
+	key := &SigningKey{ID: "default", Method: jwt.SigningMethodHS256, Private: []byte("secret"), Public: []byte("secret")}
+	useSigningKey(t, key)
+
+	token := jwt.NewWithClaims(key.Method, &JWTClaims{StandardClaims: claims, UserID: 1})
+	token.Header["kid"] = key.ID
+	tokenString, _ := token.SignedString(key.Private)
+	return tokenString
+}
+
+func Test_should_return_specific_errors_when_standard_claims_are_invalid(t *testing.T) {
+	now := time.Now()
+	valid := func() *jwt.StandardClaims {
+		return &jwt.StandardClaims{
+			Issuer:    "go-fiber-template",
+			Audience:  "go-fiber-template",
+			IssuedAt:  now.Unix(),
+			NotBefore: now.Unix(),
+			ExpiresAt: now.Add(time.Minute).Unix(),
+		}
+	}
+
+	cases := map[string]struct {
+		change func(*jwt.StandardClaims)
+		err    error
+	}{
+		"valid":                 {func(c *jwt.StandardClaims) {}, nil},
+		"expired within leeway": {func(c *jwt.StandardClaims) { c.ExpiresAt = now.Add(-time.Second * 10).Unix() }, nil},
+		"expired":               {func(c *jwt.StandardClaims) { c.ExpiresAt = now.Add(-time.Minute).Unix() }, ErrTokenExpired},
+		"not before":            {func(c *jwt.StandardClaims) { c.NotBefore = now.Add(time.Minute).Unix() }, ErrTokenNotValidYet},
+		"issued in future":      {func(c *jwt.StandardClaims) { c.IssuedAt = now.Add(time.Minute).Unix() }, ErrTokenIssuedInFuture},
+		"issuer":                {func(c *jwt.StandardClaims) { c.Issuer = "another-service" }, ErrInvalidIssuer},
+		"audience":              {func(c *jwt.StandardClaims) { c.Audience = "another-service" }, ErrInvalidAudience},
+		"missing claims":        {func(c *jwt.StandardClaims) { c.IssuedAt = 0 }, ErrTokenMissingClaims},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			// Arrange
+			claims := valid()
+			tc.change(claims)
+			token := signClaims(t, claims)
+
+			// Act
+			err := (&JWTClaims{}).ValidateToken(token)
+
+			// Assert
+			if err != tc.err {
+				t.Errorf("Test failed while validating claims, expected %v got %v", tc.err, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,230 @@
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/karlbehrensg/go-fiber-template/pkg/logger"
+)
+
+// KeyRing one active key used to sign tokens plus verification only keys, selected by kid
+type KeyRing struct {
+	mu      *sync.RWMutex
+	active  *SigningKey
+	keys    map[string]*SigningKey
+	file    string
+	modTime time.Time
+	grace   time.Duration
+}
+
+// keyRingFile format of the JWT_KEYRING_FILE, e.g.
+// {"active": "2024-06", "keys": [{"kid": "2024-06", "algorithm": "EdDSA", "private_key_file": "keys/2024-06.pem"},
+// {"kid": "2024-01", "algorithm": "EdDSA", "public_key_file": "keys/2024-01.pub", "expires_at": "2024-06-08T00:00:00Z"}]}
+type keyRingFile struct {
+	Active string `json:"active"`
+	Keys   []struct {
+		Kid            string     `json:"kid"`
+		Algorithm      string     `json:"algorithm"`
+		PrivateKeyFile string     `json:"private_key_file"`
+		PublicKeyFile  string     `json:"public_key_file"`
+		Secret         string     `json:"secret"`
+		ExpiresAt      *time.Time `json:"expires_at"`
+	} `json:"keys"`
+}
+
+var keyRing *KeyRing
+
+// GetKeyRing Initialize the keyring from env in singleton way
+func GetKeyRing() *KeyRing {
+
+	if keyRing == nil {
+		ring, err := LoadKeyRing(os.Getenv("JWT_KEYRING_FILE"), GetEnvDuration("JWT_KEY_GRACE_PERIOD", time.Hour))
+		if err != nil {
+			logger.Fatal(fmt.Sprintf("Error loading JWT keyring: %s", err.Error()))
+		}
+		keyRing = ring
+	}
+
+	return keyRing
+}
+
+// NewKeyRing create a keyring with the active key and verification only keys
+func NewKeyRing(active *SigningKey, verificationKeys ...*SigningKey) *KeyRing {
+	keys := map[string]*SigningKey{active.ID: active}
+	for _, key := range verificationKeys {
+		keys[key.ID] = key
+	}
+
+	return &KeyRing{mu: &sync.RWMutex{}, active: active, keys: keys}
+}
+
+// LoadKeyRing load the keyring from file, without file the single key is loaded from env
+func LoadKeyRing(file string, grace time.Duration) (*KeyRing, error) {
+	if file == "" {
+		key, err := LoadSigningKey(
+			os.Getenv("JWT_ALGORITHM"),
+			os.Getenv("JWT_KEY_ID"),
+			os.Getenv("JWT_PRIVATE_KEY_FILE"),
+			os.Getenv("JWT_PUBLIC_KEY_FILE"),
+			os.Getenv("JWT_SECRET"),
+		)
+		if err != nil {
+			return nil, err
+		}
+		return NewKeyRing(key), nil
+	}
+
+	ring := &KeyRing{mu: &sync.RWMutex{}, keys: map[string]*SigningKey{}, file: file, grace: grace}
+	if err := ring.Reload(); err != nil {
+		return nil, err
+	}
+
+	return ring, nil
+}
+
+// Reload read again the keyring file, a key retired from signing stays valid until its grace period ends
+func (r *KeyRing) Reload() error {
+	if r.file == "" {
+		return nil
+	}
+
+	info, err := os.Stat(r.file)
+	if err != nil {
+		return err
+	}
+	content, err := os.ReadFile(r.file)
+	if err != nil {
+		return err
+	}
+
+	var config keyRingFile
+	if err = json.Unmarshal(content, &config); err != nil {
+		return err
+	}
+
+	keys := map[string]*SigningKey{}
+	for _, entry := range config.Keys {
+		if entry.Kid == "" {
+			return errors.New("every key in the keyring requires a kid")
+		}
+		key, err := LoadSigningKey(entry.Algorithm, entry.Kid, entry.PrivateKeyFile, entry.PublicKeyFile, entry.Secret)
+		if err != nil {
+			return fmt.Errorf("key %s: %w", entry.Kid, err)
+		}
+		key.ExpiresAt = entry.ExpiresAt
+		keys[key.ID] = key
+	}
+
+	active, ok := keys[config.Active]
+	if !ok || !active.CanSign() {
+		return fmt.Errorf("active key %s not found or it can not sign", config.Active)
+	}
+	active.ExpiresAt = nil
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// the key that stops being active keeps verifying tokens until the grace period ends, unless the file
+	// expires it earlier, the expiry given on a previous reload is kept when the file has none
+	for kid, key := range keys {
+		previous, ok := r.keys[kid]
+		if key == active || !ok {
+			continue
+		}
+		expiresAt := previous.ExpiresAt
+		if previous == r.active {
+			graceEnd := time.Now().Add(r.grace)
+			expiresAt = &graceEnd
+		}
+		if expiresAt != nil && (key.ExpiresAt == nil || expiresAt.Before(*key.ExpiresAt)) {
+			key.ExpiresAt = expiresAt
+		}
+	}
+
+	// keys removed from the file keep verifying tokens until the grace period ends
+	for kid, key := range r.keys {
+		if _, ok := keys[kid]; ok {
+			continue
+		}
+		retired := *key
+		if retired.ExpiresAt == nil {
+			expiresAt := time.Now().Add(r.grace)
+			retired.ExpiresAt = &expiresAt
+		}
+		if !retired.IsExpired() {
+			keys[kid] = &retired
+		}
+	}
+
+	r.active = active
+	r.keys = keys
+	r.modTime = info.ModTime()
+
+	return nil
+}
+
+// Modified validate if the keyring file changed since the last load
+func (r *KeyRing) Modified() bool {
+	if r.file == "" {
+		return false
+	}
+
+	info, err := os.Stat(r.file)
+	if err != nil {
+		return false
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return !info.ModTime().Equal(r.modTime)
+}
+
+// SigningKey key used to sign new tokens
+func (r *KeyRing) SigningKey() *SigningKey {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.active
+}
+
+// Key find a key valid for verification by kid, tokens without kid use the active key
+func (r *KeyRing) Key(kid string) (*SigningKey, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if kid == "" {
+		return r.active, true
+	}
+
+	key, ok := r.keys[kid]
+	if !ok || key.IsExpired() {
+		return nil, false
+	}
+
+	return key, true
+}
+
+// PublicJWKs public keys to be published in the JWKS endpoint
+func (r *KeyRing) PublicJWKs() []JWK {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	keys := []JWK{}
+	for _, key := range r.keys {
+		if key.IsExpired() {
+			continue
+		}
+		if jwk, ok := key.JWK(); ok {
+			keys = append(keys, *jwk)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].Kid < keys[j].Kid })
+
+	return keys
+}
@@ -0,0 +1,129 @@
+package utils
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeKeyRing write a keyring file with HS256 keys and return its path
+func writeKeyRing(t *testing.T, path string, active string, kids ...string) string {
+	if path == "" {
+		path = filepath.Join(t.TempDir(), "keyring.json")
+	}
+
+	keys := ""
+	for i, kid := range kids {
+		if i > 0 {
+			keys += ","
+		}
+		keys += fmt.Sprintf(`{"kid": "%s", "algorithm": "HS256", "secret": "secret-%s"}`, kid, kid)
+	}
+	content := fmt.Sprintf(`{"active": "%s", "keys": [%s]}`, active, keys)
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+func Test_should_validate_token_signed_with_previous_key_when_keys_are_rotated(t *testing.T) {
+	// Arrange
+	path := writeKeyRing(t, "", "old", "old")
+	ring, err := LoadKeyRing(path, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyRing = ring
+	t.Cleanup(func() { keyRing = nil })
+	token, _ := (&JWTClaims{UserID: 1}).CreateToken()
+
+	// the old key is removed from the file and a new one becomes active
+	writeKeyRing(t, path, "new", "new")
+	if err = ring.Reload(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Act
+	err = (&JWTClaims{}).ValidateToken(token)
+
+	// Assert
+	if err != nil || ring.SigningKey().ID != "new" {
+		t.Error("Test failed while validating token signed with retired key")
+	}
+}
+
+func Test_should_return_an_error_when_retired_key_grace_period_ended(t *testing.T) {
+	// Arrange
+	path := writeKeyRing(t, "", "old", "old")
+	ring, err := LoadKeyRing(path, -time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyRing = ring
+	t.Cleanup(func() { keyRing = nil })
+	token, _ := (&JWTClaims{UserID: 1}).CreateToken()
+
+	writeKeyRing(t, path, "new", "new")
+	if err = ring.Reload(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Act
+	err = (&JWTClaims{}).ValidateToken(token)
+
+	// Assert
+	if err == nil {
+		t.Error("Test failed while validating token signed with expired key")
+	}
+}
+
+func Test_should_expire_the_previous_active_key_when_it_stays_in_the_file(t *testing.T) {
+	// Arrange
+	path := writeKeyRing(t, "", "old", "old")
+	ring, err := LoadKeyRing(path, -time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyRing = ring
+	t.Cleanup(func() { keyRing = nil })
+	token, _ := (&JWTClaims{UserID: 1}).CreateToken()
+
+	// the old key is still listed, without expires_at, but it is not active anymore
+	writeKeyRing(t, path, "new", "new", "old")
+	if err = ring.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	// a later reload keeps the expiry
+	if err = ring.Reload(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Act
+	err = (&JWTClaims{}).ValidateToken(token)
+
+	// Assert
+	if err == nil {
+		t.Error("Test failed while validating token signed with a retired key still listed")
+	}
+}
+
+func Test_should_keep_current_keys_when_keyring_file_is_invalid(t *testing.T) {
+	// Arrange
+	path := writeKeyRing(t, "", "current", "current")
+	ring, err := LoadKeyRing(path, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeKeyRing(t, path, "missing", "current")
+
+	// Act
+	err = ring.Reload()
+
+	// Assert
+	if err == nil || ring.SigningKey().ID != "current" {
+		t.Error("Test failed while reloading invalid keyring")
+	}
+}
@@ -0,0 +1,176 @@
+package utils
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+)
+
+// SigningKey key used to sign and verify JWT
+type SigningKey struct {
+	ID      string
+	Method  jwt.SigningMethod
+	Private interface{}
+	Public  interface{}
+	// ExpiresAt end of the verification grace period, nil when the key does not expire
+	ExpiresAt *time.Time
+}
+
+// JWK public key in JSON Web Key format
+type JWK struct {
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+// LoadSigningKey load a HS256 key from secret or a RS256/EdDSA key from PEM files,
+// the public key file is optional when the private key is given
+func LoadSigningKey(algorithm string, kid string, privateKeyFile string, publicKeyFile string, secret string) (*SigningKey, error) {
+	key := &SigningKey{ID: kid}
+
+	switch algorithm {
+	case "", jwt.SigningMethodHS256.Alg():
+		key.Method = jwt.SigningMethodHS256
+		key.Private = []byte(secret)
+		key.Public = []byte(secret)
+		if key.ID == "" {
+			key.ID = "default"
+		}
+		return key, nil
+	case jwt.SigningMethodRS256.Alg():
+		key.Method = jwt.SigningMethodRS256
+	case jwt.SigningMethodEdDSA.Alg():
+		key.Method = jwt.SigningMethodEdDSA
+	default:
+		return nil, fmt.Errorf("unsupported JWT algorithm %s", algorithm)
+	}
+
+	if privateKeyFile == "" && publicKeyFile == "" {
+		return nil, errors.New("a private or public key file is required")
+	}
+
+	if privateKeyFile != "" {
+		pem, err := os.ReadFile(privateKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		if key.Method == jwt.SigningMethodRS256 {
+			private, err := jwt.ParseRSAPrivateKeyFromPEM(pem)
+			if err != nil {
+				return nil, err
+			}
+			key.Private, key.Public = private, &private.PublicKey
+		} else {
+			private, err := jwt.ParseEdPrivateKeyFromPEM(pem)
+			if err != nil {
+				return nil, err
+			}
+			key.Private, key.Public = private, private.(ed25519.PrivateKey).Public()
+		}
+	}
+
+	if publicKeyFile != "" {
+		pem, err := os.ReadFile(publicKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		if key.Method == jwt.SigningMethodRS256 {
+			key.Public, err = jwt.ParseRSAPublicKeyFromPEM(pem)
+		} else {
+			key.Public, err = jwt.ParseEdPublicKeyFromPEM(pem)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// the kid defaults to a fingerprint of the public key
+	if key.ID == "" {
+		der, err := x509.MarshalPKIXPublicKey(key.Public)
+		if err != nil {
+			return nil, err
+		}
+		sum := sha256.Sum256(der)
+		key.ID = hex.EncodeToString(sum[:8])
+	}
+
+	return key, nil
+}
+
+// IsExpired validate if the grace period of the key ended
+func (k *SigningKey) IsExpired() bool {
+	return k.ExpiresAt != nil && time.Now().After(*k.ExpiresAt)
+}
+
+// CanSign validate if the key has the private part
+func (k *SigningKey) CanSign() bool {
+	return k.Private != nil
+}
+
+// JWK convert the public key to JSON Web Key, symmetric keys are never published
+func (k *SigningKey) JWK() (*JWK, bool) {
+	switch public := k.Public.(type) {
+	case *rsa.PublicKey:
+		return &JWK{
+			Kty: "RSA",
+			Use: "sig",
+			Alg: k.Method.Alg(),
+			Kid: k.ID,
+			N:   base64.RawURLEncoding.EncodeToString(public.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes()),
+		}, true
+	case ed25519.PublicKey:
+		return &JWK{
+			Kty: "OKP",
+			Use: "sig",
+			Alg: k.Method.Alg(),
+			Kid: k.ID,
+			Crv: "Ed25519",
+			X:   base64.RawURLEncoding.EncodeToString(public),
+		}, true
+	}
+
+	return nil, false
+}
+
+// PublicKey convert the JSON Web Key to a public key usable to verify tokens
+func (j JWK) PublicKey() (interface{}, error) {
+	switch j.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(j.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := base64.RawURLEncoding.DecodeString(j.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}, nil
+	case "OKP":
+		x, err := base64.RawURLEncoding.DecodeString(j.X)
+		if err != nil {
+			return nil, err
+		}
+		if j.Crv != "Ed25519" || len(x) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("unsupported OKP curve %s", j.Crv)
+		}
+		return ed25519.PublicKey(x), nil
+	}
+
+	return nil, fmt.Errorf("unsupported JWK type %s", j.Kty)
+}
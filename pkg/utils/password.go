@@ -0,0 +1,136 @@
+package utils
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	PasswordHashBcrypt   = "bcrypt"
+	PasswordHashArgon2id = "argon2id"
+)
+
+var ErrInvalidPasswordHash = errors.New("invalid password hash")
+
+// PasswordHasher hashing algorithm and parameters used for new passwords
+type PasswordHasher struct {
+	Algorithm     string
+	BcryptCost    int
+	Argon2Time    uint32
+	Argon2Memory  uint32
+	Argon2Threads uint8
+	Argon2KeyLen  uint32
+}
+
+// GetPasswordHasher read the hashing algorithm and parameters from env
+func GetPasswordHasher() PasswordHasher {
+	algorithm := strings.ToLower(os.Getenv("PASSWORD_HASH_ALGORITHM"))
+	if algorithm != PasswordHashArgon2id {
+		algorithm = PasswordHashBcrypt
+	}
+
+	return PasswordHasher{
+		Algorithm:     algorithm,
+		BcryptCost:    GetEnvInt("PASSWORD_BCRYPT_COST", 14),
+		Argon2Time:    uint32(GetEnvInt("PASSWORD_ARGON2_TIME", 3)),
+		Argon2Memory:  uint32(GetEnvInt("PASSWORD_ARGON2_MEMORY", 64*1024)),
+		Argon2Threads: uint8(GetEnvInt("PASSWORD_ARGON2_THREADS", 2)),
+		Argon2KeyLen:  32,
+	}
+}
+
+// Hash hash the password with the configured algorithm
+func (h PasswordHasher) Hash(password string) (string, error) {
+	if h.Algorithm == PasswordHashArgon2id {
+		salt := make([]byte, 16)
+		if _, err := rand.Read(salt); err != nil {
+			return "", err
+		}
+		key := argon2.IDKey([]byte(password), salt, h.Argon2Time, h.Argon2Memory, h.Argon2Threads, h.Argon2KeyLen)
+		return fmt.Sprintf(
+			"$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+			argon2.Version, h.Argon2Memory, h.Argon2Time, h.Argon2Threads,
+			base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key),
+		), nil
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), h.BcryptCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+// NeedsRehash validate if the hash was created with other algorithm or parameters
+func (h PasswordHasher) NeedsRehash(hash string) bool {
+	if strings.HasPrefix(hash, "$argon2id$") {
+		if h.Algorithm != PasswordHashArgon2id {
+			return true
+		}
+		params, _, _, err := decodeArgon2id(hash)
+		return err != nil || params.Argon2Time != h.Argon2Time || params.Argon2Memory != h.Argon2Memory ||
+			params.Argon2Threads != h.Argon2Threads || params.Argon2KeyLen != h.Argon2KeyLen
+	}
+
+	if h.Algorithm != PasswordHashBcrypt {
+		return true
+	}
+	cost, err := bcrypt.Cost([]byte(hash))
+	return err != nil || cost != h.BcryptCost
+}
+
+// ComparePassword validate the password against a bcrypt or argon2id hash
+func ComparePassword(hash string, password string) error {
+	if !strings.HasPrefix(hash, "$argon2id$") {
+		return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+	}
+
+	params, salt, key, err := decodeArgon2id(hash)
+	if err != nil {
+		return err
+	}
+	other := argon2.IDKey([]byte(password), salt, params.Argon2Time, params.Argon2Memory, params.Argon2Threads, params.Argon2KeyLen)
+	if subtle.ConstantTimeCompare(key, other) != 1 {
+		return errors.New("password does not match")
+	}
+
+	return nil
+}
+
+// decodeArgon2id parse a hash in the PHC format $argon2id$v=19$m=65536,t=3,p=2$<salt>$<key>
+func decodeArgon2id(hash string) (PasswordHasher, []byte, []byte, error) {
+	params := PasswordHasher{Algorithm: PasswordHashArgon2id}
+
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 {
+		return params, nil, nil, ErrInvalidPasswordHash
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return params, nil, nil, ErrInvalidPasswordHash
+	}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.Argon2Memory, &params.Argon2Time, &params.Argon2Threads); err != nil {
+		return params, nil, nil, ErrInvalidPasswordHash
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return params, nil, nil, ErrInvalidPasswordHash
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil {
+		return params, nil, nil, ErrInvalidPasswordHash
+	}
+	params.Argon2KeyLen = uint32(len(key))
+
+	return params, salt, key, nil
+}
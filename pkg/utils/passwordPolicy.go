@@ -0,0 +1,118 @@
+package utils
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"unicode"
+
+	"github.com/karlbehrensg/go-fiber-template/pkg/logger"
+)
+
+// PasswordPolicy rules a new password must follow
+type PasswordPolicy struct {
+	MinLength     int
+	RequireUpper  bool
+	RequireLower  bool
+	RequireDigit  bool
+	RequireSymbol bool
+	RejectEmail   bool
+	blocklist     map[string]bool
+}
+
+var passwordPolicy *PasswordPolicy
+
+// GetPasswordPolicy Initialize the password policy from env in singleton way
+func GetPasswordPolicy() *PasswordPolicy {
+
+	if passwordPolicy == nil {
+		policy := &PasswordPolicy{
+			MinLength:     GetEnvInt("PASSWORD_MIN_LENGTH", 8),
+			RequireUpper:  os.Getenv("PASSWORD_REQUIRE_UPPER") == "true",
+			RequireLower:  os.Getenv("PASSWORD_REQUIRE_LOWER") == "true",
+			RequireDigit:  os.Getenv("PASSWORD_REQUIRE_DIGIT") == "true",
+			RequireSymbol: os.Getenv("PASSWORD_REQUIRE_SYMBOL") == "true",
+			RejectEmail:   true,
+		}
+		if file := os.Getenv("PASSWORD_BLOCKLIST_FILE"); file != "" {
+			if err := policy.LoadBlocklist(file); err != nil {
+				logger.Fatal(fmt.Sprintf("Error loading password blocklist: %s", err.Error()))
+			}
+		}
+		passwordPolicy = policy
+	}
+
+	return passwordPolicy
+}
+
+// LoadBlocklist load the common passwords from a file with one password per line, lines starting with # are ignored
+func (p *PasswordPolicy) LoadBlocklist(file string) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	blocklist := map[string]bool{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		blocklist[strings.ToLower(line)] = true
+	}
+	if err = scanner.Err(); err != nil {
+		return err
+	}
+
+	p.blocklist = blocklist
+	return nil
+}
+
+// Validate validate the password against the policy, returns the first broken rule
+func (p *PasswordPolicy) Validate(password string, email string) error {
+	if len([]rune(password)) < p.MinLength {
+		return fmt.Errorf("password must have at least %d characters", p.MinLength)
+	}
+
+	var upper, lower, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r) || unicode.IsSpace(r):
+			symbol = true
+		}
+	}
+	if p.RequireUpper && !upper {
+		return fmt.Errorf("password must contain an uppercase letter")
+	}
+	if p.RequireLower && !lower {
+		return fmt.Errorf("password must contain a lowercase letter")
+	}
+	if p.RequireDigit && !digit {
+		return fmt.Errorf("password must contain a digit")
+	}
+	if p.RequireSymbol && !symbol {
+		return fmt.Errorf("password must contain a symbol")
+	}
+
+	lowered := strings.ToLower(password)
+	if p.RejectEmail && email != "" {
+		local, _, _ := strings.Cut(strings.ToLower(email), "@")
+		if lowered == strings.ToLower(email) || lowered == local {
+			return fmt.Errorf("password must not be the email address")
+		}
+	}
+	if p.blocklist[lowered] {
+		return fmt.Errorf("password is too common")
+	}
+
+	return nil
+}
@@ -0,0 +1,74 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var testArgon2Hasher = PasswordHasher{
+	Algorithm:     PasswordHashArgon2id,
+	Argon2Time:    1,
+	Argon2Memory:  1024,
+	Argon2Threads: 1,
+	Argon2KeyLen:  32,
+}
+
+func Test_should_compare_password_hashed_with_argon2id(t *testing.T) {
+	// Arrange
+	hash, _ := testArgon2Hasher.Hash("correct horse")
+
+	// Act
+	err := ComparePassword(hash, "correct horse")
+	wrong := ComparePassword(hash, "battery staple")
+
+	// Assert
+	if err != nil || wrong == nil {
+		t.Error("Test failed while comparing argon2id password")
+	}
+}
+
+func Test_should_need_rehash_when_algorithm_or_parameters_changed(t *testing.T) {
+	// Arrange
+	bcryptHasher := PasswordHasher{Algorithm: PasswordHashBcrypt, BcryptCost: 4}
+	bcryptHash, _ := bcryptHasher.Hash("correct horse")
+	argon2Hash, _ := testArgon2Hasher.Hash("correct horse")
+	stronger := testArgon2Hasher
+	stronger.Argon2Time = 2
+
+	// Assert
+	if bcryptHasher.NeedsRehash(bcryptHash) || testArgon2Hasher.NeedsRehash(argon2Hash) {
+		t.Error("Test failed while validating hash with current parameters")
+	}
+	if !testArgon2Hasher.NeedsRehash(bcryptHash) || !stronger.NeedsRehash(argon2Hash) {
+		t.Error("Test failed while validating hash with old parameters")
+	}
+	if !(PasswordHasher{Algorithm: PasswordHashBcrypt, BcryptCost: 5}).NeedsRehash(bcryptHash) {
+		t.Error("Test failed while validating bcrypt cost")
+	}
+}
+
+func Test_should_reject_password_breaking_the_policy(t *testing.T) {
+	// Arrange
+	file := filepath.Join(t.TempDir(), "blocklist.txt")
+	os.WriteFile(file, []byte("# common passwords\nPassword1!\n"), 0600)
+	policy := &PasswordPolicy{MinLength: 8, RequireUpper: true, RequireDigit: true, RejectEmail: true}
+	if err := policy.LoadBlocklist(file); err != nil {
+		t.Fatal(err)
+	}
+
+	invalid := []string{"Short1", "lowercase1", "NoDigitsHere", "password1!", "Edwyn.Rangel1", "edwyn.rangel1@example.com"}
+	for _, password := range invalid {
+		// Act
+		err := policy.Validate(password, "Edwyn.Rangel1@example.com")
+
+		// Assert
+		if err == nil {
+			t.Errorf("Test failed while validating password %q", password)
+		}
+	}
+
+	if err := policy.Validate("Tr0ub4dor&3", "edwyn@example.com"); err != nil {
+		t.Errorf("Test failed while validating strong password: %s", err.Error())
+	}
+}
@@ -0,0 +1,73 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrInvalidSignedToken = errors.New("invalid signed token")
+var ErrExpiredSignedToken = errors.New("expired signed token")
+
+// purposes of the signed tokens, a token signed for one purpose is rejected by the others
+const (
+	SignedTokenEmailVerification = "email-verification"
+	SignedTokenMFAChallenge      = "mfa-challenge"
+	SignedTokenOIDCFlow          = "oidc-flow"
+)
+
+// CreateSignedToken sign the payload and its expiration with HMAC-SHA256 and a key bound to the purpose
+func CreateSignedToken(purpose string, payload string, ttl time.Duration, secret string) string {
+	data := base64.RawURLEncoding.EncodeToString([]byte(payload)) + "." +
+		strconv.FormatInt(time.Now().Add(ttl).Unix(), 10)
+
+	return data + "." + sign(data, purposeKey(purpose, secret))
+}
+
+// ParseSignedToken validate the signature for the purpose and the expiration, returns the payload
+func ParseSignedToken(purpose string, token string, secret string) (string, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return "", ErrInvalidSignedToken
+	}
+
+	data := parts[0] + "." + parts[1]
+	if !hmac.Equal([]byte(sign(data, purposeKey(purpose, secret))), []byte(parts[2])) {
+		return "", ErrInvalidSignedToken
+	}
+
+	expiresAt, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return "", ErrInvalidSignedToken
+	}
+	if time.Now().Unix() > expiresAt {
+		return "", ErrExpiredSignedToken
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return "", ErrInvalidSignedToken
+	}
+
+	return string(payload), nil
+}
+
+// purposeKey derive the signing key of the purpose from the secret, the secrets can be shared between purposes
+func purposeKey(purpose string, secret string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("signed-token:" + purpose))
+
+	return string(mac.Sum(nil))
+}
+
+// sign create the HMAC-SHA256 signature of data
+func sign(data string, secret string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(data))
+
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
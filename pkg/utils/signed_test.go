@@ -0,0 +1,58 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func Test_should_return_payload_when_signed_token_is_valid(t *testing.T) {
+	// Arrange
+	token := CreateSignedToken(SignedTokenEmailVerification, "verify:1:edwyn@example.com", time.Minute, "secret")
+
+	// Act
+	payload, err := ParseSignedToken(SignedTokenEmailVerification, token, "secret")
+
+	// Assert
+	if err != nil || payload != "verify:1:edwyn@example.com" {
+		t.Error("Test failed while parsing signed token")
+	}
+}
+
+func Test_should_return_an_error_when_signed_token_was_tampered(t *testing.T) {
+	// Arrange
+	token := CreateSignedToken(SignedTokenEmailVerification, "verify:1:edwyn@example.com", time.Minute, "secret")
+
+	// Act
+	_, err := ParseSignedToken(SignedTokenEmailVerification, token, "another-secret")
+
+	// Assert
+	if err != ErrInvalidSignedToken {
+		t.Error("Test failed while validating signature")
+	}
+}
+
+func Test_should_return_an_error_when_signed_token_is_expired(t *testing.T) {
+	// Arrange
+	token := CreateSignedToken(SignedTokenEmailVerification, "verify:1:edwyn@example.com", -time.Minute, "secret")
+
+	// Act
+	_, err := ParseSignedToken(SignedTokenEmailVerification, token, "secret")
+
+	// Assert
+	if err != ErrExpiredSignedToken {
+		t.Error("Test failed while validating expiration")
+	}
+}
+
+func Test_should_return_an_error_when_signed_token_has_another_purpose(t *testing.T) {
+	// Arrange
+	token := CreateSignedToken(SignedTokenEmailVerification, "1", time.Minute, "secret")
+
+	// Act
+	_, err := ParseSignedToken(SignedTokenMFAChallenge, token, "secret")
+
+	// Assert
+	if err != ErrInvalidSignedToken {
+		t.Error("Test failed while validating purpose")
+	}
+}
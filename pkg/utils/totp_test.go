@@ -0,0 +1,43 @@
+package utils
+
+import (
+	"encoding/base32"
+	"testing"
+	"time"
+)
+
+func Test_should_match_rfc_6238_test_vectors(t *testing.T) {
+	// Arrange, SHA1 secret from RFC 6238 appendix B
+	secret := base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte("12345678901234567890"))
+	vectors := map[int64]string{
+		59:         "287082",
+		1111111109: "081804",
+		1234567890: "005924",
+		2000000000: "279037",
+	}
+
+	for unix, expected := range vectors {
+		// Act
+		code, err := TOTPCode(secret, TOTPStep(time.Unix(unix, 0)))
+
+		// Assert
+		if err != nil || code != expected {
+			t.Errorf("Test failed while creating TOTP code for %d, expected %s got %s", unix, expected, code)
+		}
+	}
+}
+
+func Test_should_accept_totp_code_of_adjacent_step_when_clock_drifts(t *testing.T) {
+	// Arrange
+	secret, _ := GenerateTOTPSecret()
+	now := time.Now()
+	code, _ := TOTPCode(secret, TOTPStep(now)-1)
+
+	// Act
+	step, ok := ValidateTOTP(secret, code, now, 1)
+
+	// Assert
+	if !ok || step != TOTPStep(now)-1 {
+		t.Error("Test failed while validating TOTP code with drift")
+	}
+}
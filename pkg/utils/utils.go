@@ -3,6 +3,8 @@ package utils
 import (
 	"fmt"
 	"os"
+	"strconv"
+	"time"
 
 	"github.com/karlbehrensg/go-fiber-template/pkg/logger"
 )
@@ -26,3 +28,35 @@ func CheckEnv() {
 		}
 	}
 }
+
+// GetEnvDuration get a duration from env, returns fallback when it is not defined or invalid
+func GetEnvDuration(key string, fallback time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Invalid duration %q for environment variable %s, using %s", value, key, fallback))
+		return fallback
+	}
+
+	return duration
+}
+
+// GetEnvInt get an integer from env, returns fallback when it is not defined or invalid
+func GetEnvInt(key string, fallback int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+
+	number, err := strconv.Atoi(value)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Invalid integer %q for environment variable %s, using %d", value, key, fallback))
+		return fallback
+	}
+
+	return number
+}
@@ -9,6 +9,7 @@ func GetValidator() *validator.Validate {
 
 	if validate == nil {
 		validate = validator.New()
+		validate.RegisterValidation("isbn", validateISBN)
 	}
 
 	return validate